@@ -1,10 +1,13 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
+	"net"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/gofiber/fiber/v3"
@@ -13,10 +16,20 @@ import (
 	"github.com/gofiber/swagger/v2"
 	"github.com/redis/go-redis/v9"
 
+	"github.com/esdrassantos06/go-shortener/internal/adapters/blocklists"
+	"github.com/esdrassantos06/go-shortener/internal/adapters/crawlers"
+	"github.com/esdrassantos06/go-shortener/internal/adapters/exporters"
+	"github.com/esdrassantos06/go-shortener/internal/adapters/fetchers"
+	"github.com/esdrassantos06/go-shortener/internal/adapters/geoip"
 	"github.com/esdrassantos06/go-shortener/internal/adapters/handlers"
+	"github.com/esdrassantos06/go-shortener/internal/adapters/importers"
 	"github.com/esdrassantos06/go-shortener/internal/adapters/middleware"
+	"github.com/esdrassantos06/go-shortener/internal/adapters/qrcodes"
 	"github.com/esdrassantos06/go-shortener/internal/adapters/repositories"
+	"github.com/esdrassantos06/go-shortener/internal/adapters/storage"
 	"github.com/esdrassantos06/go-shortener/internal/core/auth"
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
 	"github.com/esdrassantos06/go-shortener/internal/core/services"
 
 	_ "github.com/esdrassantos06/go-shortener/docs"
@@ -64,8 +77,53 @@ func main() {
 	rdb := redis.NewClient(opt)
 
 	linkRepo := repositories.NewPostgresRepo(db)
+	domainRepo := repositories.NewPostgresDomainRepo(db)
+	importRepo := repositories.NewPostgresImportRepo(db)
+	clickRepo := repositories.NewPostgresClickRepo(db)
+	exportRepo := repositories.NewPostgresExportRepo(db)
+	tagRepo := repositories.NewPostgresTagRepo(db)
+	folderRepo := repositories.NewPostgresFolderRepo(db)
 	cacheRepo := repositories.NewRedisRepo(rdb)
-	linkService := services.NewLinkService(linkRepo, cacheRepo)
+	fetchClient := fetchers.NewHTTPClient(10 * time.Second)
+	titleFetcher := fetchers.NewTitleFetcher(fetchClient)
+	// Targets are checked against local blocklists when BLOCKLIST_DIR is set;
+	// the files are reloaded when they change.
+	var blocklist ports.Blocklist
+	if dir := os.Getenv("BLOCKLIST_DIR"); dir != "" {
+		if blocklist, err = blocklists.NewFileBlocklist(dir, 30*time.Second); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	linkService := services.NewLinkService(linkRepo, cacheRepo, domainRepo, clickRepo, tagRepo, folderRepo, titleFetcher, blocklist)
+	// Every instance runs a scheduler; scheduled changes are claimed so each
+	// is applied once.
+	go linkService.RunScheduler(context.Background())
+	domainService := services.NewDomainService(domainRepo, net.DefaultResolver)
+	variantService := services.NewVariantService(linkRepo, clickRepo, repositories.NewPostgresConversionRepo(db), cacheRepo, blocklist)
+	qrService := services.NewQRService(qrcodes.NewRenderer(), fetchers.NewImageFetcher(fetchClient), cacheRepo)
+	tagService := services.NewTagService(tagRepo)
+	folderService := services.NewFolderService(folderRepo)
+	moderationService := services.NewModerationService(repositories.NewPostgresModerationRepo(db), linkRepo, cacheRepo)
+	// ADMIN_USER_ROLES=true reads roles from the "role" column Better Auth's
+	// admin plugin adds to the user table.
+	userRepo := repositories.NewPostgresUserRepo(db, os.Getenv("ADMIN_USER_ROLES") == "true")
+	adminService := services.NewAdminService(linkRepo, clickRepo, userRepo, cacheRepo)
+	importService := services.NewImportService(importRepo, linkRepo, domainRepo, tagRepo, map[domain.ImportFormat]ports.ImportParser{
+		domain.ImportFormatBitly:     importers.NewBitlyParser(),
+		domain.ImportFormatRebrandly: importers.NewRebrandlyParser(),
+		domain.ImportFormatCSV:       importers.NewCSVParser(),
+	}, blocklist, linkService)
+
+	exportDir := os.Getenv("EXPORT_DIR")
+	if exportDir == "" {
+		exportDir = filepath.Join(os.TempDir(), "zipway-exports")
+	}
+	exportStorage, err := storage.NewLocalStorage(exportDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	exportService := services.NewExportService(linkRepo, clickRepo, exportRepo, exportStorage, exporters.NewWriterFactory())
 
 	if baseURL == "" {
 		baseURL = "http://localhost:8080"
@@ -74,10 +132,37 @@ func main() {
 	if shortURLDomain == "" {
 		shortURLDomain = baseURL
 	}
-	httpHandler := handlers.NewHTTPHandler(linkService, baseURL, shortURLDomain)
+
+	// Country routing rules need the visitor's country: either from a header
+	// set by a trusted CDN (e.g. CF-IPCountry) or a local GeoIP database.
+	var countryLocator ports.CountryLocator
+	if header := os.Getenv("GEOIP_COUNTRY_HEADER"); header != "" {
+		countryLocator = geoip.NewHeaderLocator(header)
+	} else if path := os.Getenv("GEOIP_DATABASE"); path != "" {
+		if countryLocator, err = geoip.NewMaxMindLocator(path); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	interstitials, err := handlers.LoadInterstitialTemplates(os.Getenv("INTERSTITIAL_TEMPLATE_DIR"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	httpHandler := handlers.NewHTTPHandler(linkService, qrService, variantService, crawlers.NewUserAgentClassifier(), countryLocator, interstitials, baseURL, shortURLDomain)
+	domainHandler := handlers.NewDomainHandler(domainService)
+	importHandler := handlers.NewImportHandler(importService)
+	exportHandler := handlers.NewExportHandler(exportService)
+	adminHandler := handlers.NewAdminHandler(linkService, adminService, httpHandler)
+	moderationHandler := handlers.NewModerationHandler(moderationService, httpHandler)
+	tagHandler := handlers.NewTagHandler(tagService)
+	folderHandler := handlers.NewFolderHandler(folderService)
 
 	sessionValidator := auth.NewSessionValidator(db, cacheRepo)
-	authMiddleware := middleware.NewAuthMiddleware(sessionValidator)
+	authMiddleware := middleware.NewAuthMiddleware(sessionValidator, moderationService)
+	idempotencyMiddleware := middleware.NewIdempotencyMiddleware(cacheRepo)
+	adminMiddleware := middleware.NewAdminMiddleware(os.Getenv("ADMIN_USER_IDS"), adminService)
+	reportLimit := middleware.NewRateLimitMiddleware(cacheRepo, "report", 5, time.Hour)
 
 	app := fiber.New(fiber.Config{
 		ServerHeader:      "Zipway",
@@ -95,8 +180,8 @@ func main() {
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     origins,
 		AllowCredentials: true,
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Cookie"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Cookie", "Idempotency-Key"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 	}))
 
 	app.Get("/swagger/*", swagger.HandlerDefault)
@@ -113,9 +198,75 @@ func main() {
 	})
 
 	app.Get("/api/resolve/:slug", httpHandler.ResolveSlug)
+	app.Get("/api/resolve/:slug/*", httpHandler.ResolveSlug)
+	app.Post("/api/resolve/:slug", httpHandler.ResolveSlug)
+	app.Post("/api/resolve/:slug/*", httpHandler.ResolveSlug)
+	app.Get("/api/postback/:token", httpHandler.RecordConversion)
+	app.Post("/api/report/:slug", reportLimit.Handle, moderationHandler.ReportLink)
+	app.Post("/api/postback/:token", httpHandler.RecordConversion)
 
 	api := app.Group("/api", authMiddleware.RequireAuth)
-	api.Post("/shorten", httpHandler.CreateShortLink)
+	api.Post("/shorten", idempotencyMiddleware.Handle, httpHandler.CreateShortLink)
+	api.Post("/links/bulk", idempotencyMiddleware.Handle, httpHandler.CreateBulkLinks)
+	api.Get("/links", httpHandler.ListLinks)
+	api.Get("/links/search", httpHandler.SearchLinks)
+	api.Get("/links/by-target", httpHandler.FindLinksByTarget)
+	api.Post("/links/rewrite-targets", httpHandler.RewriteTargets)
+	api.Get("/links/export", exportHandler.ExportLinks)
+	api.Patch("/links/:slug", httpHandler.UpdateLink)
+	api.Get("/links/:slug/qr", httpHandler.LinkQRCode)
+	api.Get("/links/:slug/history", httpHandler.LinkHistory)
+	api.Get("/links/:slug/versions", httpHandler.ListLinkVersions)
+	api.Post("/links/:slug/versions/:n/restore", httpHandler.RestoreLinkVersion)
+	api.Get("/links/:slug/scheduled-changes", httpHandler.ListScheduledChanges)
+	api.Post("/links/:slug/scheduled-changes", httpHandler.ScheduleLinkChange)
+	api.Delete("/links/:slug/scheduled-changes/:id", httpHandler.CancelScheduledChange)
+	api.Put("/links/:slug/tags", httpHandler.SetLinkTags)
+	api.Put("/links/:slug/folder", httpHandler.SetLinkFolder)
+	api.Put("/links/:slug/rules", httpHandler.SetLinkRules)
+	api.Put("/links/:slug/query-options", httpHandler.SetLinkQueryOptions)
+	api.Put("/links/:slug/path-forwarding", httpHandler.SetLinkPathForwarding)
+	api.Put("/links/:slug/schedule", httpHandler.SetLinkSchedule)
+	api.Put("/links/:slug/max-uses", httpHandler.SetLinkMaxUses)
+	api.Put("/links/:slug/interstitial", httpHandler.SetLinkInterstitial)
+	api.Put("/links/:slug/variants", httpHandler.SetLinkVariants)
+	api.Get("/links/:slug/variants/stats", httpHandler.VariantStats)
+	api.Get("/tags", tagHandler.ListTags)
+	api.Post("/tags", tagHandler.CreateTag)
+	api.Patch("/tags/:id", tagHandler.RenameTag)
+	api.Get("/folders", folderHandler.ListFolders)
+	api.Post("/folders", folderHandler.CreateFolder)
+	api.Patch("/folders/:id", folderHandler.RenameFolder)
+	api.Get("/domains", domainHandler.ListDomains)
+	api.Post("/domains", domainHandler.RegisterDomain)
+	api.Post("/domains/:hostname/verify", domainHandler.VerifyDomain)
+	api.Post("/imports", importHandler.CreateImport)
+	api.Get("/imports/:id", importHandler.GetImport)
+	api.Post("/exports", exportHandler.CreateExport)
+	api.Get("/exports/:id", exportHandler.GetExport)
+	api.Get("/exports/:id/download", exportHandler.DownloadExport)
+
+	admin := api.Group("/admin", adminMiddleware.RequireAdmin)
+	admin.Get("/links/search", adminHandler.SearchLinks)
+	admin.Get("/links/by-target", adminHandler.FindLinksByTarget)
+	admin.Get("/links/history", adminHandler.ListLinkChanges)
+	admin.Put("/links/:slug/flag", moderationHandler.SetLinkFlagged)
+	admin.Put("/links/:slug/status", moderationHandler.SetLinkStatus)
+	admin.Delete("/links/:slug/cache", adminHandler.FlushLinkCache)
+	admin.Delete("/links/:slug", moderationHandler.DeleteLink)
+	admin.Get("/users", adminHandler.SearchUsers)
+	admin.Get("/users/:id", adminHandler.GetUser)
+	admin.Get("/stats", adminHandler.PlatformStats)
+	admin.Get("/reports", moderationHandler.ListReports)
+	admin.Post("/reports/:id/review", moderationHandler.ReviewReport)
+	admin.Put("/users/:id/ban", moderationHandler.BanUser)
+	admin.Delete("/users/:id/ban", moderationHandler.UnbanUser)
+	admin.Get("/moderation/actions", moderationHandler.ListModerationActions)
+
+	app.Get("/:slug", httpHandler.Redirect)
+	app.Get("/:slug/*", httpHandler.Redirect)
+	app.Post("/:slug", httpHandler.Redirect)
+	app.Post("/:slug/*", httpHandler.Redirect)
 
 	port := os.Getenv("PORT")
 	if port == "" {
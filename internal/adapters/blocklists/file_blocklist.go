@@ -0,0 +1,289 @@
+package blocklists
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+)
+
+// List files are picked up by extension; any other file in the directory is
+// ignored. Blank lines and lines starting with "#" are skipped, and so are
+// malformed lines, which are logged.
+const (
+	// domainsExt lists hosts, one per line. A plain host also blocks its
+	// subdomains; hosts containing "*" are glob patterns ("*.example.com",
+	// "login-*.example.net"). Hosts-file lines ("0.0.0.0 example.com") work
+	// too.
+	domainsExt = ".domains"
+	// regexExt lists RE2 expressions matched against the whole URL.
+	regexExt = ".regex"
+	// hashesExt lists hex SHA-256 hash prefixes (4 to 32 bytes) of Safe
+	// Browsing URL expressions. A prefix that full hashes in the lists start
+	// with only blocks URLs matching one of those full hashes.
+	hashesExt = ".hashes"
+)
+
+const minHashPrefix = 4
+
+type wildcard struct {
+	pattern string
+	list    string
+}
+
+type pattern struct {
+	re   *regexp.Regexp
+	list string
+}
+
+// lists is one loaded snapshot of the directory; it is never modified after
+// loading, so matching needs no locks beyond swapping the pointer.
+type lists struct {
+	hosts     map[string]string
+	wildcards []wildcard
+	patterns  []pattern
+	// hashes is keyed by the raw prefix bytes; hashLens holds the prefix
+	// lengths in use, shortest first.
+	hashes   map[string]string
+	hashLens []int
+	// confirmed holds the shorter prefixes that full hashes start with.
+	confirmed map[string]bool
+}
+
+// fileBlocklist matches URLs against the list files in a directory and
+// reloads them when any of them changes.
+type fileBlocklist struct {
+	dir string
+
+	current   atomic.Pointer[lists]
+	version   atomic.Uint64
+	mu        sync.Mutex
+	signature string
+}
+
+// NewFileBlocklist loads the list files in dir and polls it every
+// reloadEvery for changes. A reload that fails keeps the lists loaded before.
+func NewFileBlocklist(dir string, reloadEvery time.Duration) (ports.Blocklist, error) {
+	b := &fileBlocklist{dir: dir}
+	if _, err := b.reload(); err != nil {
+		return nil, err
+	}
+	go func() {
+		for range time.Tick(reloadEvery) {
+			reloaded, err := b.reload()
+			switch {
+			case err != nil:
+				log.Printf("failed to reload blocklists from %s: %v", dir, err)
+			case reloaded:
+				log.Printf("reloaded blocklists from %s", dir)
+			}
+		}
+	}()
+	return b, nil
+}
+
+func (b *fileBlocklist) Version() uint64 {
+	return b.version.Load()
+}
+
+func (b *fileBlocklist) Match(rawURL string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return "", false
+	}
+	l := b.current.Load()
+	host := canonicalHost(u.Hostname())
+
+	for h := host; h != ""; {
+		if list, ok := l.hosts[h]; ok {
+			return list, true
+		}
+		_, parent, found := strings.Cut(h, ".")
+		if !found {
+			break
+		}
+		h = parent
+	}
+	for _, w := range l.wildcards {
+		if ok, _ := path.Match(w.pattern, host); ok {
+			return w.list, true
+		}
+	}
+	for _, p := range l.patterns {
+		if p.re.MatchString(rawURL) {
+			return p.list, true
+		}
+	}
+	if len(l.hashes) > 0 {
+		for _, expr := range urlExpressions(u) {
+			if list, ok := l.matchHash(sha256.Sum256([]byte(expr))); ok {
+				return list, true
+			}
+		}
+	}
+	return "", false
+}
+
+// matchHash looks up each listed prefix of sum. A prefix hit that full hashes
+// start with is confirmed by the full hash instead, which comes last.
+func (l *lists) matchHash(sum [sha256.Size]byte) (string, bool) {
+	for _, n := range l.hashLens {
+		prefix := string(sum[:n])
+		if list, ok := l.hashes[prefix]; ok && !l.confirmed[prefix] {
+			return list, true
+		}
+	}
+	return "", false
+}
+
+// reload reads the directory again when its list files changed since the
+// last load, and reports whether it did.
+func (b *fileBlocklist) reload() (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	files, signature, err := b.listFiles()
+	if err != nil {
+		return false, err
+	}
+	if b.current.Load() != nil && signature == b.signature {
+		return false, nil
+	}
+
+	l := &lists{
+		hosts:     make(map[string]string),
+		hashes:    make(map[string]string),
+		confirmed: make(map[string]bool),
+	}
+	for _, file := range files {
+		if err := l.load(file); err != nil {
+			return false, err
+		}
+	}
+	l.indexHashes()
+
+	b.current.Store(l)
+	b.signature = signature
+	b.version.Add(1)
+	return true, nil
+}
+
+// listFiles returns the list files in the directory and a signature of their
+// names, sizes and modification times.
+func (b *fileBlocklist) listFiles() ([]string, string, error) {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return nil, "", err
+	}
+	var files []string
+	var signature strings.Builder
+	for _, entry := range entries {
+		switch filepath.Ext(entry.Name()) {
+		case domainsExt, regexExt, hashesExt:
+		default:
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, "", err
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		files = append(files, filepath.Join(b.dir, entry.Name()))
+		fmt.Fprintf(&signature, "%s:%d:%d;", entry.Name(), info.Size(), info.ModTime().UnixNano())
+	}
+	return files, signature.String(), nil
+}
+
+func (l *lists) load(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	name := filepath.Base(file)
+	ext := filepath.Ext(file)
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := l.add(ext, name, line); err != nil {
+			log.Printf("skipping blocklist line %s:%d: %v", name, n, err)
+		}
+	}
+	return scanner.Err()
+}
+
+func (l *lists) add(ext string, list string, line string) error {
+	switch ext {
+	case domainsExt:
+		fields := strings.Fields(line)
+		// Hosts-file format: the address comes first.
+		if len(fields) == 2 && net.ParseIP(fields[0]) != nil {
+			line = fields[1]
+		} else if len(fields) != 1 {
+			return fmt.Errorf("expected one host per line")
+		}
+		host := canonicalHost(line)
+		if strings.Contains(host, "*") {
+			if _, err := path.Match(host, ""); err != nil {
+				return err
+			}
+			l.wildcards = append(l.wildcards, wildcard{pattern: host, list: list})
+		} else {
+			l.hosts[host] = list
+		}
+	case regexExt:
+		re, err := regexp.Compile(line)
+		if err != nil {
+			return err
+		}
+		l.patterns = append(l.patterns, pattern{re: re, list: list})
+	case hashesExt:
+		prefix, err := hex.DecodeString(line)
+		if err != nil || len(prefix) < minHashPrefix || len(prefix) > sha256.Size {
+			return fmt.Errorf("expected a hex SHA-256 hash prefix of %d to %d bytes", minHashPrefix, sha256.Size)
+		}
+		l.hashes[string(prefix)] = list
+	}
+	return nil
+}
+
+// indexHashes collects the prefix lengths in use and marks the prefixes that
+// full hashes start with, once every file is loaded.
+func (l *lists) indexHashes() {
+	for prefix := range l.hashes {
+		if !slices.Contains(l.hashLens, len(prefix)) {
+			l.hashLens = append(l.hashLens, len(prefix))
+		}
+	}
+	slices.Sort(l.hashLens)
+	for full := range l.hashes {
+		if len(full) != sha256.Size {
+			continue
+		}
+		for _, n := range l.hashLens[:len(l.hashLens)-1] {
+			if _, ok := l.hashes[full[:n]]; ok {
+				l.confirmed[full[:n]] = true
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package blocklists
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func hashHex(expr string, bytes int) string {
+	sum := sha256.Sum256([]byte(expr))
+	return hex.EncodeToString(sum[:bytes])
+}
+
+func TestFileBlocklistHashes(t *testing.T) {
+	lines := []string{
+		"# Safe Browsing style prefixes",
+		hashHex("evil.example/", 4),
+		hashHex("phish.example/login", sha256.Size),
+		// Confirmed by the full hash below, so only that URL matches.
+		hashHex("shared.example/bad", 8),
+		hashHex("shared.example/bad", sha256.Size),
+		// Malformed lines are skipped without dropping the rest.
+		hashHex("toolong.example/", sha256.Size) + "00",
+		"abc",
+		"0011",
+		"not hex at all",
+		hashHex("after.example/", 16),
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "sb.hashes"), []byte(strings.Join(lines, "\n")), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewFileBlocklist(dir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://evil.example/", true},
+		{"https://www.evil.example/any/path?q=1", true},
+		{"https://phish.example/login", true},
+		{"https://phish.example/", false},
+		{"https://shared.example/bad", true},
+		{"https://after.example/", true},
+		{"https://toolong.example/", false},
+		{"https://example.com/", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			list, got := b.Match(tt.url)
+			if got != tt.want {
+				t.Fatalf("Match(%q) = %v, want %v", tt.url, got, tt.want)
+			}
+			if got && list != "sb.hashes" {
+				t.Errorf("Match(%q) list = %q, want sb.hashes", tt.url, list)
+			}
+		})
+	}
+}
+
+func TestMatchHashConfirmsSharedPrefix(t *testing.T) {
+	full := sha256.Sum256([]byte("shared.example/bad"))
+	l := &lists{hashes: map[string]string{}, confirmed: map[string]bool{}}
+	l.hashes[string(full[:8])] = "sb.hashes"
+	l.hashes[string(full[:])] = "sb.hashes"
+	l.indexHashes()
+
+	other := full
+	other[sha256.Size-1] ^= 0xff
+	if _, ok := l.matchHash(other); ok {
+		t.Error("a prefix confirmed by a full hash matched a different full hash")
+	}
+	if _, ok := l.matchHash(full); !ok {
+		t.Error("the listed full hash did not match")
+	}
+}
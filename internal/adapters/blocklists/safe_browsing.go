@@ -0,0 +1,101 @@
+package blocklists
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// canonicalHost lowercases a hostname and drops empty labels, so "Example.COM."
+// and "example..com" are listed and looked up the same way.
+func canonicalHost(host string) string {
+	if unescaped, err := url.PathUnescape(host); err == nil {
+		host = unescaped
+	}
+	labels := strings.Split(strings.ToLower(host), ".")
+	kept := labels[:0]
+	for _, label := range labels {
+		if label != "" {
+			kept = append(kept, label)
+		}
+	}
+	return strings.Join(kept, ".")
+}
+
+// canonicalPath resolves "." and ".." segments and repeated slashes in a
+// URL path, which always starts with "/".
+func canonicalPath(p string) string {
+	for {
+		unescaped, err := url.PathUnescape(p)
+		if err != nil || unescaped == p {
+			break
+		}
+		p = unescaped
+	}
+
+	var segments []string
+	for _, seg := range strings.Split(p, "/") {
+		switch seg {
+		case "", ".":
+		case "..":
+			if len(segments) > 0 {
+				segments = segments[:len(segments)-1]
+			}
+		default:
+			segments = append(segments, seg)
+		}
+	}
+	out := "/" + strings.Join(segments, "/")
+	if len(segments) > 0 && strings.HasSuffix(p, "/") {
+		out += "/"
+	}
+	return out
+}
+
+// urlExpressions returns the host suffix and path prefix combinations that
+// Safe Browsing hash-prefix lists are built from: the exact host and up to
+// four suffixes of its last five labels, each with the exact path and query,
+// the exact path, and up to four path prefixes from the root.
+func urlExpressions(u *url.URL) []string {
+	host := canonicalHost(u.Hostname())
+	hosts := []string{host}
+	if net.ParseIP(host) == nil {
+		labels := strings.Split(host, ".")
+		start := max(len(labels)-5, 1)
+		for i := start; i < len(labels)-1 && len(hosts) < 5; i++ {
+			hosts = append(hosts, strings.Join(labels[i:], "."))
+		}
+	}
+
+	p := canonicalPath(u.EscapedPath())
+	var paths []string
+	if u.RawQuery != "" {
+		paths = append(paths, p+"?"+u.RawQuery)
+	}
+	paths = append(paths, p)
+	var dirs []string
+	if trimmed := strings.Trim(p, "/"); trimmed != "" {
+		dirs = strings.Split(trimmed, "/")
+		if !strings.HasSuffix(p, "/") {
+			dirs = dirs[:len(dirs)-1]
+		}
+	}
+	prefix := "/"
+	for i := 0; ; i++ {
+		if prefix != p {
+			paths = append(paths, prefix)
+		}
+		if i == len(dirs) || i == 3 {
+			break
+		}
+		prefix += dirs[i] + "/"
+	}
+
+	exprs := make([]string, 0, len(hosts)*len(paths))
+	for _, h := range hosts {
+		for _, path := range paths {
+			exprs = append(exprs, h+path)
+		}
+	}
+	return exprs
+}
@@ -0,0 +1,56 @@
+package crawlers
+
+import (
+	"strings"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+)
+
+// previewAgents are lowercased User-Agent fragments of the bots that fetch a
+// URL to render a link preview (unfurl) in a chat, social feed or mail client.
+// Search engine crawlers are deliberately left out: they should follow the
+// redirect and index the target.
+var previewAgents = []string{
+	"facebookexternalhit",
+	"facebot",
+	"twitterbot",
+	"linkedinbot",
+	"slackbot-linkexpanding",
+	"slack-imgproxy",
+	"discordbot",
+	"telegrambot",
+	"whatsapp",
+	"pinterest",
+	"redditbot",
+	"applebot",
+	"skypeuripreview",
+	"embedly",
+	"vkshare",
+	"iframely",
+	"mastodon",
+	"bluesky",
+	"google-pagerenderer",
+	"snapchat",
+	"viber",
+}
+
+type userAgentClassifier struct {
+	agents []string
+}
+
+func NewUserAgentClassifier() ports.CrawlerClassifier {
+	return &userAgentClassifier{agents: previewAgents}
+}
+
+func (c *userAgentClassifier) IsPreviewCrawler(userAgent string) bool {
+	if userAgent == "" {
+		return false
+	}
+	ua := strings.ToLower(userAgent)
+	for _, agent := range c.agents {
+		if strings.Contains(ua, agent) {
+			return true
+		}
+	}
+	return false
+}
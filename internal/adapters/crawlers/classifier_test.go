@@ -0,0 +1,36 @@
+package crawlers
+
+import "testing"
+
+func TestIsPreviewCrawler(t *testing.T) {
+	tests := []struct {
+		name      string
+		userAgent string
+		want      bool
+	}{
+		{"empty", "", false},
+		{"facebook", "facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)", true},
+		{"twitter", "Twitterbot/1.0", true},
+		{"linkedin", "LinkedInBot/1.0 (compatible; Mozilla/5.0; Apache-HttpClient +http://www.linkedin.com)", true},
+		{"slack unfurl", "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)", true},
+		{"discord", "Mozilla/5.0 (compatible; Discordbot/2.0; +https://discordapp.com)", true},
+		{"telegram", "TelegramBot (like TwitterBot)", true},
+		{"whatsapp", "WhatsApp/2.23.20.0 A", true},
+		{"imessage", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_11_1) AppleWebKit/601.2.4 (KHTML, like Gecko) Version/9.0.1 Safari/601.2.4 facebookexternalhit/1.1 Facebot Twitterbot/1.0", true},
+		{"mixed case", "DISCORDBOT", true},
+		{"googlebot follows the redirect", "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", false},
+		{"bingbot follows the redirect", "Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)", false},
+		{"plain slackbot is not the unfurler", "Slackbot 1.0 (+https://api.slack.com/robots)", false},
+		{"browser", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0 Safari/537.36", false},
+		{"curl", "curl/8.5.0", false},
+	}
+
+	classifier := NewUserAgentClassifier()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifier.IsPreviewCrawler(tt.userAgent); got != tt.want {
+				t.Errorf("IsPreviewCrawler(%q) = %v, want %v", tt.userAgent, got, tt.want)
+			}
+		})
+	}
+}
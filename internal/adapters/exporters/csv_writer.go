@@ -0,0 +1,38 @@
+package exporters
+
+import (
+	"encoding/csv"
+	"io"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+)
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer, dataset domain.ExportDataset) (*csvWriter, error) {
+	cw := &csvWriter{w: csv.NewWriter(w)}
+
+	header := linkColumns
+	if dataset == domain.ExportDatasetClicks {
+		header = clickColumns
+	}
+	if err := cw.w.Write(header); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (cw *csvWriter) WriteLink(link domain.Link) error {
+	return cw.w.Write(newLinkRow(link).csvRecord())
+}
+
+func (cw *csvWriter) WriteClick(click domain.ClickEvent) error {
+	return cw.w.Write(newClickRow(click).csvRecord())
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
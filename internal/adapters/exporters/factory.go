@@ -0,0 +1,180 @@
+package exporters
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+)
+
+type writerFactory struct{}
+
+func NewWriterFactory() ports.ExportWriterFactory {
+	return writerFactory{}
+}
+
+func (writerFactory) NewWriter(w io.Writer, format domain.ExportFormat, dataset domain.ExportDataset) (ports.ExportWriter, error) {
+	if !dataset.Valid() {
+		return nil, domain.ErrInvalidExport
+	}
+
+	switch format {
+	case domain.ExportFormatCSV:
+		return newCSVWriter(w, dataset)
+	case domain.ExportFormatJSONL:
+		return newJSONLWriter(w), nil
+	case domain.ExportFormatParquet:
+		return newParquetWriter(w, dataset), nil
+	}
+	return nil, domain.ErrInvalidExport
+}
+
+// linkRow and clickRow are the flat export schemas shared by every format.
+// Structured link settings are JSON columns, left empty when unset, so a CSV
+// export can be imported again without losing them.
+type linkRow struct {
+	ID        string    `json:"id" parquet:"id"`
+	ShortID   string    `json:"short_id" parquet:"short_id"`
+	Domain    string    `json:"domain" parquet:"domain"`
+	TargetURL string    `json:"target_url" parquet:"target_url"`
+	Status    string    `json:"status" parquet:"status"`
+	Clicks    int64     `json:"clicks" parquet:"clicks"`
+	CreatedAt time.Time `json:"created_at" parquet:"created_at"`
+	FolderID  string    `json:"folder_id" parquet:"folder_id"`
+	Tags      []string  `json:"tags" parquet:"tags,list"`
+
+	Title         string `json:"title" parquet:"title"`
+	Description   string `json:"description" parquet:"description"`
+	Notes         string `json:"notes" parquet:"notes"`
+	OGTitle       string `json:"og_title" parquet:"og_title"`
+	OGDescription string `json:"og_description" parquet:"og_description"`
+	OGImageURL    string `json:"og_image_url" parquet:"og_image_url"`
+
+	ForwardPath  bool     `json:"forward_path" parquet:"forward_path"`
+	Rules        jsonText `json:"rules" parquet:"rules,optional,json"`
+	Variants     jsonText `json:"variants" parquet:"variants,optional,json"`
+	QueryOptions jsonText `json:"query_options" parquet:"query_options,optional,json"`
+	Schedule     jsonText `json:"schedule" parquet:"schedule,optional,json"`
+	MaxUses      *int64   `json:"max_uses" parquet:"max_uses,optional"`
+	Interstitial jsonText `json:"interstitial" parquet:"interstitial,optional,json"`
+}
+
+// jsonText is a JSON value kept as text: JSONL exports embed it, CSV and
+// Parquet exports store the text. The empty text is null.
+type jsonText string
+
+func (t jsonText) MarshalJSON() ([]byte, error) {
+	if t == "" {
+		return []byte("null"), nil
+	}
+	return []byte(t), nil
+}
+
+// jsonColumn encodes a setting for a JSON column, or returns the empty text
+// when it is unset.
+func jsonColumn(v any, unset bool) jsonText {
+	if unset {
+		return ""
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return jsonText(raw)
+}
+
+func newLinkRow(link domain.Link) linkRow {
+	var folderID string
+	if link.FolderID != nil {
+		folderID = *link.FolderID
+	}
+	tags := link.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	var maxUses *int64
+	if link.MaxUses != nil {
+		n := int64(*link.MaxUses)
+		maxUses = &n
+	}
+	return linkRow{
+		ID:        link.ID,
+		ShortID:   link.ShortID,
+		Domain:    link.Domain,
+		TargetURL: link.TargetURL,
+		Status:    string(link.Status),
+		Clicks:    int64(link.Clicks),
+		CreatedAt: link.CreatedAt,
+		FolderID:  folderID,
+		Tags:      tags,
+
+		Title:         link.Title,
+		Description:   link.Description,
+		Notes:         link.Notes,
+		OGTitle:       link.OGTitle,
+		OGDescription: link.OGDescription,
+		OGImageURL:    link.OGImageURL,
+
+		ForwardPath:  link.ForwardPath,
+		Rules:        jsonColumn(link.Rules, len(link.Rules) == 0),
+		Variants:     jsonColumn(link.Variants, len(link.Variants) == 0),
+		QueryOptions: jsonColumn(link.QueryOptions, link.QueryOptions.IsZero()),
+		Schedule:     jsonColumn(link.Schedule, link.Schedule == domain.LinkSchedule{}),
+		MaxUses:      maxUses,
+		Interstitial: jsonColumn(link.Interstitial, link.Interstitial.IsZero()),
+	}
+}
+
+func (r linkRow) csvRecord() []string {
+	var maxUses string
+	if r.MaxUses != nil {
+		maxUses = strconv.FormatInt(*r.MaxUses, 10)
+	}
+	return []string{r.ID, r.ShortID, r.Domain, r.TargetURL, r.Status, strconv.FormatInt(r.Clicks, 10), r.CreatedAt.UTC().Format(time.RFC3339), r.FolderID, strings.Join(r.Tags, "|"), r.Title, r.Description, r.Notes,
+		r.OGTitle, r.OGDescription, r.OGImageURL, strconv.FormatBool(r.ForwardPath), string(r.Rules), string(r.Variants), string(r.QueryOptions), string(r.Schedule), maxUses, string(r.Interstitial)}
+}
+
+var linkColumns = []string{"id", "short_id", "domain", "target_url", "status", "clicks", "created_at", "folder_id", "tags", "title", "description", "notes",
+	"og_title", "og_description", "og_image_url", "forward_path", "rules", "variants", "query_options", "schedule", "max_uses", "interstitial"}
+
+type clickRow struct {
+	LinkID    string    `json:"link_id" parquet:"link_id"`
+	ShortID   string    `json:"short_id" parquet:"short_id"`
+	Domain    string    `json:"domain" parquet:"domain"`
+	ClickedAt time.Time `json:"clicked_at" parquet:"clicked_at"`
+	Referrer  string    `json:"referrer" parquet:"referrer"`
+	UserAgent string    `json:"user_agent" parquet:"user_agent"`
+	RuleIndex *int32    `json:"rule_index" parquet:"rule_index,optional,json"`
+	VariantID string    `json:"variant_id" parquet:"variant_id"`
+}
+
+func newClickRow(click domain.ClickEvent) clickRow {
+	row := clickRow{
+		LinkID:    click.LinkID,
+		ShortID:   click.ShortID,
+		Domain:    click.Domain,
+		ClickedAt: click.ClickedAt,
+		Referrer:  click.Referrer,
+		UserAgent: click.UserAgent,
+		VariantID: click.VariantID,
+	}
+	if click.RuleIndex != nil {
+		idx := int32(*click.RuleIndex)
+		row.RuleIndex = &idx
+	}
+	return row
+}
+
+func (r clickRow) csvRecord() []string {
+	var ruleIndex string
+	if r.RuleIndex != nil {
+		ruleIndex = strconv.Itoa(int(*r.RuleIndex))
+	}
+	return []string{r.LinkID, r.ShortID, r.Domain, r.ClickedAt.UTC().Format(time.RFC3339Nano), r.Referrer, r.UserAgent, ruleIndex, r.VariantID}
+}
+
+var clickColumns = []string{"link_id", "short_id", "domain", "clicked_at", "referrer", "user_agent", "rule_index", "variant_id"}
@@ -0,0 +1,28 @@
+package exporters
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+)
+
+type jsonlWriter struct {
+	enc *json.Encoder
+}
+
+func newJSONLWriter(w io.Writer) *jsonlWriter {
+	return &jsonlWriter{enc: json.NewEncoder(w)}
+}
+
+func (jw *jsonlWriter) WriteLink(link domain.Link) error {
+	return jw.enc.Encode(newLinkRow(link))
+}
+
+func (jw *jsonlWriter) WriteClick(click domain.ClickEvent) error {
+	return jw.enc.Encode(newClickRow(click))
+}
+
+func (jw *jsonlWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,56 @@
+package exporters
+
+import (
+	"errors"
+	"io"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress/snappy"
+)
+
+// parquetRowGroupSize bounds how many rows are buffered in memory before a
+// row group is flushed to the output.
+const parquetRowGroupSize = 10000
+
+var errDatasetMismatch = errors.New("row does not match the export dataset")
+
+type parquetWriter struct {
+	links  *parquet.GenericWriter[linkRow]
+	clicks *parquet.GenericWriter[clickRow]
+}
+
+func newParquetWriter(w io.Writer, dataset domain.ExportDataset) *parquetWriter {
+	options := []parquet.WriterOption{
+		parquet.Compression(&snappy.Codec{}),
+		parquet.MaxRowsPerRowGroup(parquetRowGroupSize),
+	}
+
+	if dataset == domain.ExportDatasetClicks {
+		return &parquetWriter{clicks: parquet.NewGenericWriter[clickRow](w, options...)}
+	}
+	return &parquetWriter{links: parquet.NewGenericWriter[linkRow](w, options...)}
+}
+
+func (pw *parquetWriter) WriteLink(link domain.Link) error {
+	if pw.links == nil {
+		return errDatasetMismatch
+	}
+	_, err := pw.links.Write([]linkRow{newLinkRow(link)})
+	return err
+}
+
+func (pw *parquetWriter) WriteClick(click domain.ClickEvent) error {
+	if pw.clicks == nil {
+		return errDatasetMismatch
+	}
+	_, err := pw.clicks.Write([]clickRow{newClickRow(click)})
+	return err
+}
+
+func (pw *parquetWriter) Close() error {
+	if pw.links != nil {
+		return pw.links.Close()
+	}
+	return pw.clicks.Close()
+}
@@ -0,0 +1,66 @@
+package fetchers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+)
+
+// maxImageBytes bounds the size of fetched images such as QR code logos.
+const maxImageBytes = 1 << 20
+
+type httpImageFetcher struct {
+	Client ports.HTTPClient
+}
+
+func NewImageFetcher(client ports.HTTPClient) ports.ImageFetcher {
+	return &httpImageFetcher{Client: client}
+}
+
+func (f *httpImageFetcher) FetchImage(ctx context.Context, imageURL string) (image.Image, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "image/png,image/jpeg,image/gif")
+
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("fetching %s: unexpected status %d", imageURL, resp.StatusCode)
+	}
+	if resp.ContentLength > maxImageBytes {
+		return nil, fmt.Errorf("fetching %s: image is larger than %d bytes", imageURL, maxImageBytes)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageBytes))
+	if err != nil {
+		return nil, err
+	}
+	// A small compressed image can decode to a huge bitmap, so the
+	// dimensions are checked before any pixels are decoded.
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width > domain.MaxQRSize || cfg.Height > domain.MaxQRSize {
+		return nil, fmt.Errorf("fetching %s: image is larger than %dx%d pixels", imageURL, domain.MaxQRSize, domain.MaxQRSize)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
@@ -0,0 +1,41 @@
+package fetchers
+
+import (
+	"context"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchImage(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		wantErr       bool
+	}{
+		{name: "small logo", width: 64, height: 64},
+		{name: "largest allowed", width: 2048, height: 1},
+		{name: "too wide", width: 2049, height: 1, wantErr: true},
+		{name: "too tall", width: 1, height: 4096, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "image/png")
+				png.Encode(w, image.NewGray(image.Rect(0, 0, tt.width, tt.height)))
+			}))
+			defer server.Close()
+
+			img, err := NewImageFetcher(server.Client()).FetchImage(context.Background(), server.URL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FetchImage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (img.Bounds().Dx() != tt.width || img.Bounds().Dy() != tt.height) {
+				t.Errorf("FetchImage() bounds = %v, want %dx%d", img.Bounds(), tt.width, tt.height)
+			}
+		})
+	}
+}
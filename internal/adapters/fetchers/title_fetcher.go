@@ -0,0 +1,88 @@
+package fetchers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// maxTitleBodyBytes bounds how much of a page is read looking for <title>;
+// it is almost always in the first few kilobytes of <head>.
+const maxTitleBodyBytes = 512 << 10
+
+const userAgent = "ZipwayBot/1.0 (+https://zipway.app)"
+
+type htmlTitleFetcher struct {
+	Client ports.HTTPClient
+}
+
+func NewTitleFetcher(client ports.HTTPClient) ports.TitleFetcher {
+	return &htmlTitleFetcher{Client: client}
+}
+
+// FetchTitle returns the page's <title> with whitespace collapsed, or an
+// empty string when the target is not an HTML page or has no title.
+func (f *htmlTitleFetcher) FetchTitle(ctx context.Context, targetURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.1")
+
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("fetching %s: unexpected status %d", targetURL, resp.StatusCode)
+	}
+	if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil &&
+		mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return "", nil
+	}
+
+	return parseTitle(io.LimitReader(resp.Body, maxTitleBodyBytes)), nil
+}
+
+func parseTitle(r io.Reader) string {
+	z := html.NewTokenizer(r)
+	inTitle := false
+	var title strings.Builder
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return cleanTitle(title.String())
+		case html.StartTagToken:
+			name, _ := z.TagName()
+			switch atom.Lookup(name) {
+			case atom.Title:
+				inTitle = true
+			case atom.Body:
+				return cleanTitle(title.String())
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			if atom.Lookup(name) == atom.Title {
+				return cleanTitle(title.String())
+			}
+		case html.TextToken:
+			if inTitle {
+				title.Write(z.Text())
+			}
+		}
+	}
+}
+
+func cleanTitle(title string) string {
+	return strings.Join(strings.Fields(strings.ToValidUTF8(title, "")), " ")
+}
@@ -0,0 +1,124 @@
+package fetchers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFetchTitle(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		contentType string
+		body        string
+		want        string
+		wantErr     bool
+	}{
+		{
+			name:        "title in head",
+			contentType: "text/html; charset=utf-8",
+			body:        `<html><head><meta charset="utf-8"><title>Example Domain</title></head><body>x</body></html>`,
+			want:        "Example Domain",
+		},
+		{
+			name:        "whitespace collapsed and entities decoded",
+			contentType: "text/html",
+			body:        "<title>\n  Fish &amp;\tChips \n</title>",
+			want:        "Fish & Chips",
+		},
+		{
+			name:        "xhtml",
+			contentType: "application/xhtml+xml",
+			body:        `<html xmlns="http://www.w3.org/1999/xhtml"><head><title>Strict</title></head></html>`,
+			want:        "Strict",
+		},
+		{
+			name:        "missing content type is parsed",
+			contentType: "",
+			body:        `<title>Untyped</title>`,
+			want:        "Untyped",
+		},
+		{
+			name:        "no title",
+			contentType: "text/html",
+			body:        `<html><head></head><body><h1>Heading</h1></body></html>`,
+			want:        "",
+		},
+		{
+			name:        "title inside body is ignored",
+			contentType: "text/html",
+			body:        `<html><body><svg><title>Icon</title></svg></body></html>`,
+			want:        "",
+		},
+		{
+			name:        "not html",
+			contentType: "application/pdf",
+			body:        `%PDF-1.7 <title>Nope</title>`,
+			want:        "",
+		},
+		{
+			name:        "error status",
+			status:      http.StatusNotFound,
+			contentType: "text/html",
+			body:        `<title>Not Found</title>`,
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("User-Agent"); got != userAgent {
+					t.Errorf("User-Agent = %q, want %q", got, userAgent)
+				}
+				w.Header()["Content-Type"] = []string{tt.contentType}
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+				}
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			got, err := NewTitleFetcher(server.Client()).FetchTitle(context.Background(), server.URL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FetchTitle() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("FetchTitle() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchTitleReadsAtMostTheLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><head><!--" + strings.Repeat("x", maxTitleBodyBytes) + "--><title>Too late</title>"))
+	}))
+	defer server.Close()
+
+	got, err := NewTitleFetcher(server.Client()).FetchTitle(context.Background(), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "" {
+		t.Errorf("FetchTitle() = %q, want the title past the limit to be ignored", got)
+	}
+}
+
+func TestHTTPClientRefusesLoopback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the request reached a loopback server")
+	}))
+	defer server.Close()
+
+	_, err := NewTitleFetcher(NewHTTPClient(time.Second)).FetchTitle(context.Background(), server.URL)
+	if !errors.Is(err, errForbiddenAddress) {
+		t.Errorf("FetchTitle() error = %v, want %v", err, errForbiddenAddress)
+	}
+}
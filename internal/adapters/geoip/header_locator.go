@@ -0,0 +1,21 @@
+package geoip
+
+import (
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+)
+
+// headerLocator trusts a country header set by a CDN in front of the API,
+// such as Cloudflare's CF-IPCountry. It must only be used when clients cannot
+// reach the API without going through that CDN.
+type headerLocator struct {
+	Header string
+}
+
+func NewHeaderLocator(header string) ports.CountryLocator {
+	return &headerLocator{Header: header}
+}
+
+func (l *headerLocator) Locate(_ string, header func(key string) string) string {
+	return domain.NormalizeCountry(header(l.Header))
+}
@@ -0,0 +1,36 @@
+package geoip
+
+import (
+	"net"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+	"github.com/oschwald/geoip2-golang"
+)
+
+// maxMindLocator looks visitors up in a local MaxMind GeoIP2 or GeoLite2
+// Country (or City) database, which is memory-mapped so lookups do not touch
+// the network.
+type maxMindLocator struct {
+	Reader *geoip2.Reader
+}
+
+func NewMaxMindLocator(path string) (ports.CountryLocator, error) {
+	reader, err := geoip2.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return &maxMindLocator{Reader: reader}, nil
+}
+
+func (l *maxMindLocator) Locate(ip string, _ func(key string) string) string {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return ""
+	}
+	record, err := l.Reader.Country(addr)
+	if err != nil {
+		return ""
+	}
+	return domain.NormalizeCountry(record.Country.IsoCode)
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+	"github.com/gofiber/fiber/v3"
+)
+
+// AdminHandler serves the /api/admin routes, which act across every user's
+// links. HTTP addresses the ?domain= parameter the same way the link handlers
+// do.
+type AdminHandler struct {
+	Links ports.LinkService
+	Admin ports.AdminService
+	HTTP  *HTTPHandler
+}
+
+func NewAdminHandler(links ports.LinkService, admin ports.AdminService, http *HTTPHandler) *AdminHandler {
+	return &AdminHandler{Links: links, Admin: admin, HTTP: http}
+}
+
+// SearchLinks godoc
+// @Summary      Search every user's links (admin)
+// @Description  Full-text search over all links, like /api/links/search but across users. Pass user_id to search one user's links only. Requires an admin account.
+// @Tags         admin
+// @Produce      json
+// @Param        q        query     string  true   "Search text"  example(spring sale)
+// @Param        user_id  query     string  false  "Only this user's links"
+// @Param        status   query     string  false  "Link status"  Enums(ACTIVE, PAUSED, BLOCKED)
+// @Param        from     query     string  false  "Created at or after (YYYY-MM-DD or RFC 3339)"  example(2025-01-01)
+// @Param        to       query     string  false  "Created before (YYYY-MM-DD or RFC 3339)"  example(2025-02-01)
+// @Param        limit    query     int     false  "Page size (default 50, max 200)"
+// @Param        offset   query     int     false  "Number of links to skip"
+// @Success      200      {object}  domain.LinkPage  "Matching links"
+// @Failure      400      {object}  ErrorResponse  "Invalid query or filters"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Failure      403      {object}  ErrorResponse  "Admin access required"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/admin/links/search [get]
+func (h *AdminHandler) SearchLinks(c fiber.Ctx) error {
+	search := domain.LinkSearch{
+		Query:  c.Query("q"),
+		Status: domain.LinkStatus(strings.ToUpper(c.Query("status"))),
+	}
+	switch search.Status {
+	case "", domain.StatusActive, domain.StatusPaused, domain.StatusBlocked:
+	default:
+		return c.Status(400).JSON(ErrorResponse{Error: "status must be 'ACTIVE', 'PAUSED' or 'BLOCKED'"})
+	}
+
+	var err error
+	if search.From, err = parseTimeParam(c.Query("from")); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "from must be a date (YYYY-MM-DD) or RFC 3339 timestamp"})
+	}
+	if search.To, err = parseTimeParam(c.Query("to")); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "to must be a date (YYYY-MM-DD) or RFC 3339 timestamp"})
+	}
+	if search.Limit, err = queryInt(c, "limit"); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid limit"})
+	}
+	if search.Offset, err = queryInt(c, "offset"); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid offset"})
+	}
+
+	var userID *string
+	if id := strings.TrimSpace(c.Query("user_id")); id != "" {
+		userID = &id
+	}
+
+	page, err := h.Admin.SearchLinks(c.Context(), userID, search)
+	if err != nil {
+		if errors.Is(err, domain.ErrEmptySearch) {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while searching links"})
+	}
+	return c.JSON(page)
+}
+
+// FindLinksByTarget godoc
+// @Summary      Find any user's links by target (admin)
+// @Description  Returns every link, across all users, pointing at or under a URL, or at a domain and its subdomains. Requires an admin account.
+// @Tags         admin
+// @Produce      json
+// @Param        url     query     string  false  "Target URL prefix"  example(https://example.com/docs)
+// @Param        domain  query     string  false  "Target domain"  example(example.com)
+// @Success      200     {object}  domain.TargetLookupResult  "Matching links"
+// @Failure      400     {object}  ErrorResponse  "Invalid URL or domain"
+// @Failure      401     {object}  ErrorResponse  "Unauthorized"
+// @Failure      403     {object}  ErrorResponse  "Admin access required"
+// @Failure      500     {object}  ErrorResponse  "Internal server error"
+// @Router       /api/admin/links/by-target [get]
+func (h *AdminHandler) FindLinksByTarget(c fiber.Ctx) error {
+	return findLinksByTarget(c, h.Links, nil)
+}
+
+func findLinksByTarget(c fiber.Ctx, service ports.LinkService, userID *string) error {
+	result, err := service.FindLinksByTarget(c.Context(), userID, domain.TargetLookup{
+		URL:    c.Query("url"),
+		Domain: c.Query("domain"),
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidLookup) || errors.Is(err, domain.ErrInvalidTargetURL) || errors.Is(err, domain.ErrInvalidHostname) {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while looking up links"})
+	}
+	return c.JSON(result)
+}
+
+// SearchUsers godoc
+// @Summary      Search users (admin)
+// @Description  Finds users by exact ID, or by email or name containing the query, newest first, with the number of links they own, their total clicks and whether they are banned. Requires an admin account.
+// @Tags         admin
+// @Produce      json
+// @Param        q       query     string  true   "User ID, or part of an email or name"  example(jane@example.com)
+// @Param        limit   query     int     false  "Page size (default 50, max 200)"
+// @Param        offset  query     int     false  "Number of users to skip"
+// @Success      200     {object}  domain.UserPage  "Matching users"
+// @Failure      400     {object}  ErrorResponse  "Missing query or invalid pagination"
+// @Failure      401     {object}  ErrorResponse  "Unauthorized"
+// @Failure      403     {object}  ErrorResponse  "Admin access required"
+// @Failure      500     {object}  ErrorResponse  "Internal server error"
+// @Router       /api/admin/users [get]
+func (h *AdminHandler) SearchUsers(c fiber.Ctx) error {
+	search := domain.UserSearch{Query: c.Query("q")}
+
+	var err error
+	if search.Limit, err = queryInt(c, "limit"); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid limit"})
+	}
+	if search.Offset, err = queryInt(c, "offset"); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid offset"})
+	}
+
+	page, err := h.Admin.SearchUsers(c.Context(), search)
+	if err != nil {
+		if errors.Is(err, domain.ErrEmptySearch) {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while searching users"})
+	}
+	return c.JSON(page)
+}
+
+// GetUser godoc
+// @Summary      Look up a user (admin)
+// @Description  Returns a user with the number of links they own, their total clicks and whether they are banned. Requires an admin account.
+// @Tags         admin
+// @Produce      json
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {object}  domain.UserSummary  "User"
+// @Failure      401  {object}  ErrorResponse  "Unauthorized"
+// @Failure      403  {object}  ErrorResponse  "Admin access required"
+// @Failure      404  {object}  ErrorResponse  "User not found"
+// @Failure      500  {object}  ErrorResponse  "Internal server error"
+// @Router       /api/admin/users/{id} [get]
+func (h *AdminHandler) GetUser(c fiber.Ctx) error {
+	user, err := h.Admin.GetUser(c.Context(), c.Params("id"))
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "User not found"})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while looking up the user"})
+	}
+	return c.JSON(user)
+}
+
+// PlatformStats godoc
+// @Summary      Platform-wide statistics (admin)
+// @Description  Counts the links created and the clicks recorded on each UTC day over the last days days, today included, and lists the 10 most clicked links of the period. Requires an admin account.
+// @Tags         admin
+// @Produce      json
+// @Param        days  query     int  false  "Number of days (default 30, max 365)"
+// @Success      200   {object}  domain.PlatformStats  "Statistics"
+// @Failure      400   {object}  ErrorResponse  "Invalid days"
+// @Failure      401   {object}  ErrorResponse  "Unauthorized"
+// @Failure      403   {object}  ErrorResponse  "Admin access required"
+// @Failure      500   {object}  ErrorResponse  "Internal server error"
+// @Router       /api/admin/stats [get]
+func (h *AdminHandler) PlatformStats(c fiber.Ctx) error {
+	days, err := queryInt(c, "days")
+	if err != nil || days > domain.MaxStatsDays {
+		return c.Status(400).JSON(ErrorResponse{Error: "days must be a number between 1 and 365"})
+	}
+
+	stats, err := h.Admin.PlatformStats(c.Context(), days)
+	if err != nil {
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while computing statistics"})
+	}
+	return c.JSON(stats)
+}
+
+// FlushLinkCache godoc
+// @Summary      Flush a link from the cache (admin)
+// @Description  Drops the cached copy of any user's link, so the next redirect reads it from the database. Requires an admin account.
+// @Tags         admin
+// @Param        slug    path   string  true   "Shortened link slug"  example(abc123)
+// @Param        domain  query  string  false  "Custom domain the link belongs to"  example(go.acme.com)
+// @Success      204     "Cache flushed"
+// @Failure      401     {object}  ErrorResponse  "Unauthorized"
+// @Failure      403     {object}  ErrorResponse  "Admin access required"
+// @Failure      404     {object}  ErrorResponse  "Link not found"
+// @Failure      500     {object}  ErrorResponse  "Internal server error"
+// @Router       /api/admin/links/{slug}/cache [delete]
+func (h *AdminHandler) FlushLinkCache(c fiber.Ctx) error {
+	linkDomain, err := h.HTTP.queryDomain(c)
+	if err != nil {
+		return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+	}
+
+	if err := h.Admin.FlushLinkCache(c.Context(), linkDomain, slugParam(c)); err != nil {
+		if errors.Is(err, domain.ErrLinkNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while flushing the cache"})
+	}
+	return c.SendStatus(204)
+}
@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/gofiber/fiber/v3"
+)
+
+const maxBulkItems = 1000
+
+type BulkCreateItemResult struct {
+	Index    int          `json:"index" example:"0"`
+	ShortURL string       `json:"short_url,omitempty" example:"http://localhost:8080/abc123"`
+	Details  *domain.Link `json:"details,omitempty"`
+	Error    string       `json:"error,omitempty" example:"slug is already in use"`
+}
+
+type BulkCreateResponse struct {
+	Mode    string                 `json:"mode" example:"atomic"`
+	Created int                    `json:"created" example:"2"`
+	Failed  int                    `json:"failed" example:"0"`
+	Results []BulkCreateItemResult `json:"results"`
+}
+
+// CreateBulkLinks godoc
+// @Summary      Create links in bulk
+// @Description  Creates up to 1000 links in one request. The body is either a JSON array of link objects or a CSV file (text/csv body or multipart "file" field) with a header row naming the target_url, custom_slug, domain, title, description and notes columns. Every item is validated before anything is inserted. In atomic mode (default) a single failure means no link is created; in best_effort mode valid items are created and failures are reported per item.
+// @Tags         links
+// @Accept       json
+// @Accept       text/csv
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        mode     query     string                    false  "atomic or best_effort"  Enums(atomic, best_effort)
+// @Param        request  body      []CreateShortLinkRequest  false  "Links to create"
+// @Param        file     formData  file                      false  "CSV file"
+// @Param        Idempotency-Key  header  string               false  "Replays the original response when a request is retried with the same key (24h, per user)"
+// @Success      200      {object}  BulkCreateResponse  "All items processed"
+// @Failure      400      {object}  ErrorResponse  "Invalid input or too many items"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Failure      422      {object}  BulkCreateResponse  "Atomic batch rejected"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/links/bulk [post]
+func (h *HTTPHandler) CreateBulkLinks(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{
+			Error: "Unauthorized: User ID not found",
+		})
+	}
+
+	mode := c.Query("mode", "atomic")
+	if mode != "atomic" && mode != "best_effort" {
+		return c.Status(400).JSON(ErrorResponse{Error: "mode must be 'atomic' or 'best_effort'"})
+	}
+
+	items, err := parseBulkItems(c)
+	if err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+	}
+	if len(items) == 0 {
+		return c.Status(400).JSON(ErrorResponse{Error: "No links provided"})
+	}
+	if len(items) > maxBulkItems {
+		return c.Status(400).JSON(ErrorResponse{
+			Error: fmt.Sprintf("A bulk request can contain at most %d links", maxBulkItems),
+		})
+	}
+
+	inputs := make([]domain.LinkInput, len(items))
+	for i, item := range items {
+		inputs[i] = domain.LinkInput{
+			TargetURL:    strings.TrimSpace(item.TargetURL),
+			CustomSlug:   strings.TrimSpace(item.CustomSlug),
+			Domain:       strings.TrimSpace(item.Domain),
+			ForwardPath:  item.ForwardPath,
+			Rules:        item.Rules,
+			QueryOptions: item.QueryOptions,
+			Schedule:     item.Schedule,
+			MaxUses:      item.MaxUses,
+			Interstitial: item.Interstitial,
+			LinkMetadata: item.metadata(),
+		}
+		if inputs[i].Domain != "" {
+			// Invalid hostnames are passed through so the service reports
+			// them against the item.
+			if linkDomain, err := h.linkDomain(inputs[i].Domain); err == nil {
+				inputs[i].Domain = linkDomain
+			}
+		}
+	}
+
+	results, err := h.Service.ShortenBulk(c.Context(), inputs, mode == "atomic", &userID)
+	if err != nil {
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while creating the links"})
+	}
+
+	resp := BulkCreateResponse{Mode: mode, Results: make([]BulkCreateItemResult, len(results))}
+	for i, result := range results {
+		item := BulkCreateItemResult{Index: result.Index}
+		switch {
+		case result.Err != nil:
+			item.Error = result.Err.Error()
+			resp.Failed++
+		case result.Link != nil:
+			item.ShortURL = h.shortURL(*result.Link)
+			item.Details = result.Link
+			resp.Created++
+		default:
+			item.Error = "not created because another item in the batch failed"
+			resp.Failed++
+		}
+		resp.Results[i] = item
+	}
+
+	if mode == "atomic" && resp.Failed > 0 {
+		return c.Status(422).JSON(resp)
+	}
+	return c.JSON(resp)
+}
+
+func parseBulkItems(c fiber.Ctx) ([]CreateShortLinkRequest, error) {
+	if isCSVUpload(c) {
+		f, err := openCSVUpload(c)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return parseBulkCSV(f)
+	}
+
+	var items []CreateShortLinkRequest
+	if err := json.Unmarshal(c.Body(), &items); err != nil {
+		return nil, errors.New("expected a JSON array of links")
+	}
+	return items, nil
+}
+
+func isCSVUpload(c fiber.Ctx) bool {
+	contentType := strings.ToLower(c.Get(fiber.HeaderContentType))
+	return strings.HasPrefix(contentType, fiber.MIMEMultipartForm) || strings.HasPrefix(contentType, "text/csv")
+}
+
+// openCSVUpload returns the uploaded CSV, read either from the multipart
+// "file" field or from a text/csv request body.
+func openCSVUpload(c fiber.Ctx) (io.ReadCloser, error) {
+	contentType := strings.ToLower(c.Get(fiber.HeaderContentType))
+	if !strings.HasPrefix(contentType, fiber.MIMEMultipartForm) {
+		return io.NopCloser(bytes.NewReader(c.Body())), nil
+	}
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return nil, errors.New("missing CSV file in 'file' field")
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return nil, errors.New("could not read CSV file")
+	}
+	return f, nil
+}
+
+func parseBulkCSV(r io.Reader) ([]CreateShortLinkRequest, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("CSV is missing its header row")
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["target_url"]; !ok {
+		return nil, errors.New("CSV is missing the target_url column")
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	var items []CreateShortLinkRequest
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		// One past the limit is enough for the caller to reject the batch.
+		if len(items) > maxBulkItems {
+			break
+		}
+		items = append(items, CreateShortLinkRequest{
+			TargetURL:   field(record, "target_url"),
+			CustomSlug:  field(record, "custom_slug"),
+			Domain:      field(record, "domain"),
+			Title:       field(record, "title"),
+			Description: field(record, "description"),
+			Notes:       field(record, "notes"),
+		})
+	}
+	return items, nil
+}
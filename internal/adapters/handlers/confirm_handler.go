@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"bytes"
+	"html/template"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+var confirmTemplate = template.Must(template.ParseFS(templateFS, "templates/confirm.html"))
+
+// sendConfirmation answers a GET on a limited link with a page that asks the
+// visitor to open it. Only the form's POST spends a use, so link unfurlers,
+// mail scanners and browser prefetches can't use the link up.
+func (h *HTTPHandler) sendConfirmation(c fiber.Ctx) error {
+	var buf bytes.Buffer
+	if err := confirmTemplate.Execute(&buf, nil); err != nil {
+		return c.Status(500).SendString("An error occurred while rendering the page")
+	}
+
+	c.Set("Cache-Control", "private, no-store")
+	c.Set("X-Robots-Tag", "noindex")
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.Send(buf.Bytes())
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+	"github.com/gofiber/fiber/v3"
+)
+
+type DomainHandler struct {
+	Service ports.DomainService
+}
+
+func NewDomainHandler(service ports.DomainService) *DomainHandler {
+	return &DomainHandler{Service: service}
+}
+
+type RegisterDomainRequest struct {
+	Hostname string `json:"hostname" example:"go.acme.com" binding:"required"`
+}
+
+type DomainResponse struct {
+	Domain         domain.CustomDomain `json:"domain"`
+	Verified       bool                `json:"verified" example:"false"`
+	TXTRecordName  string              `json:"txt_record_name" example:"_zipway-verification.go.acme.com"`
+	TXTRecordValue string              `json:"txt_record_value" example:"zipway-verification=3f2a..."`
+}
+
+func newDomainResponse(d domain.CustomDomain) DomainResponse {
+	return DomainResponse{
+		Domain:         d,
+		Verified:       d.Verified(),
+		TXTRecordName:  d.VerificationRecordName(),
+		TXTRecordValue: d.VerificationRecordValue(),
+	}
+}
+
+// RegisterDomain godoc
+// @Summary      Register a custom domain
+// @Description  Registers a custom domain for the authenticated user. The response contains the DNS TXT record that must be published before the domain can be verified. Registering only claims the domain: other users may claim it too, and it belongs to whoever verifies it first.
+// @Tags         domains
+// @Accept       json
+// @Produce      json
+// @Param        request  body      RegisterDomainRequest  true  "Domain data"
+// @Success      200      {object}  DomainResponse  "Domain registered"
+// @Failure      400      {object}  ErrorResponse  "Invalid hostname"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Failure      409      {object}  ErrorResponse  "Domain already verified by another user"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/domains [post]
+func (h *DomainHandler) RegisterDomain(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	var req RegisterDomainRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input"})
+	}
+
+	d, err := h.Service.RegisterDomain(c.Context(), req.Hostname, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidHostname) {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid hostname"})
+		}
+		if errors.Is(err, domain.ErrDomainTaken) {
+			return c.Status(409).JSON(ErrorResponse{Error: "This domain is already registered"})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while registering the domain"})
+	}
+
+	return c.JSON(newDomainResponse(d))
+}
+
+// VerifyDomain godoc
+// @Summary      Verify a custom domain
+// @Description  Checks the domain's DNS TXT record and marks the domain as verified when the expected token is present. Other users' pending claims on the domain are dropped.
+// @Tags         domains
+// @Produce      json
+// @Param        hostname  path      string  true  "Domain hostname"  example(go.acme.com)
+// @Success      200       {object}  DomainResponse  "Domain verified"
+// @Failure      401       {object}  ErrorResponse  "Unauthorized"
+// @Failure      404       {object}  ErrorResponse  "Domain not found"
+// @Failure      409       {object}  ErrorResponse  "Domain already verified by another user"
+// @Failure      422       {object}  ErrorResponse  "Verification record not found"
+// @Failure      500       {object}  ErrorResponse  "Internal server error"
+// @Router       /api/domains/{hostname}/verify [post]
+func (h *DomainHandler) VerifyDomain(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	d, err := h.Service.VerifyDomain(c.Context(), c.Params("hostname"), userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrDomainNotFound) || errors.Is(err, domain.ErrInvalidHostname) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Domain not found"})
+		}
+		if errors.Is(err, domain.ErrDomainTaken) {
+			return c.Status(409).JSON(ErrorResponse{Error: "This domain is already registered"})
+		}
+		if errors.Is(err, domain.ErrVerificationFailed) {
+			return c.Status(422).JSON(ErrorResponse{Error: "The verification TXT record was not found"})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while verifying the domain"})
+	}
+
+	return c.JSON(newDomainResponse(d))
+}
+
+// ListDomains godoc
+// @Summary      List custom domains
+// @Description  Lists the custom domains registered by the authenticated user.
+// @Tags         domains
+// @Produce      json
+// @Success      200  {array}   DomainResponse  "Registered domains"
+// @Failure      401  {object}  ErrorResponse  "Unauthorized"
+// @Failure      500  {object}  ErrorResponse  "Internal server error"
+// @Router       /api/domains [get]
+func (h *DomainHandler) ListDomains(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	domains, err := h.Service.ListDomains(c.Context(), userID)
+	if err != nil {
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while listing domains"})
+	}
+
+	resp := make([]DomainResponse, 0, len(domains))
+	for _, d := range domains {
+		resp = append(resp, newDomainResponse(d))
+	}
+	return c.JSON(resp)
+}
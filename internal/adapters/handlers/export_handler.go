@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+	"github.com/gofiber/fiber/v3"
+)
+
+type ExportHandler struct {
+	Service ports.ExportService
+}
+
+func NewExportHandler(service ports.ExportService) *ExportHandler {
+	return &ExportHandler{Service: service}
+}
+
+type CreateExportRequest struct {
+	Dataset string `json:"dataset" example:"clicks" enums:"links,clicks"`
+	Format  string `json:"format" example:"parquet" enums:"csv,jsonl,parquet"`
+	From    string `json:"from,omitempty" example:"2025-01-01"`
+	To      string `json:"to,omitempty" example:"2025-02-01"`
+}
+
+// parseTimeParam accepts RFC 3339 timestamps or plain dates.
+func parseTimeParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
+func buildExportRequest(dataset, format, from, to string) (domain.ExportRequest, error) {
+	req := domain.ExportRequest{
+		Dataset: domain.ExportDataset(dataset),
+		Format:  domain.ExportFormat(format),
+	}
+	if !req.Dataset.Valid() {
+		return req, errors.New("dataset must be 'links' or 'clicks'")
+	}
+	if !req.Format.Valid() {
+		return req, errors.New("format must be one of csv, jsonl or parquet")
+	}
+
+	var err error
+	if req.From, err = parseTimeParam(from); err != nil {
+		return req, errors.New("from must be a date (YYYY-MM-DD) or RFC 3339 timestamp")
+	}
+	if req.To, err = parseTimeParam(to); err != nil {
+		return req, errors.New("to must be a date (YYYY-MM-DD) or RFC 3339 timestamp")
+	}
+	if !req.From.IsZero() && !req.To.IsZero() && !req.From.Before(req.To) {
+		return req, errors.New("from must be before to")
+	}
+	return req, nil
+}
+
+func setDownloadHeaders(c fiber.Ctx, format domain.ExportFormat, fileName string) {
+	c.Set(fiber.HeaderContentType, format.ContentType())
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	c.Set(fiber.HeaderCacheControl, "no-store")
+}
+
+// ExportLinks godoc
+// @Summary      Export links or click events
+// @Description  Streams the authenticated user's links, or the raw click events on their links, as CSV, JSON Lines or Parquet. Rows are written as they are read so large accounts are not loaded into memory. from/to bound click events by time and are ignored for links.
+// @Tags         exports
+// @Produce      text/csv
+// @Produce      application/x-ndjson
+// @Produce      application/vnd.apache.parquet
+// @Param        format   query     string  false  "File format"  Enums(csv, jsonl, parquet)  default(csv)
+// @Param        dataset  query     string  false  "Data to export"  Enums(links, clicks)  default(links)
+// @Param        from     query     string  false  "Start of the click range (inclusive)"  example(2025-01-01)
+// @Param        to       query     string  false  "End of the click range (exclusive)"  example(2025-02-01)
+// @Success      200      {file}    file  "Export file"
+// @Failure      400      {object}  ErrorResponse  "Invalid parameters"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Router       /api/links/export [get]
+func (h *ExportHandler) ExportLinks(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	req, err := buildExportRequest(c.Query("dataset", "links"), c.Query("format", "csv"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+	}
+
+	setDownloadHeaders(c, req.Format, req.FileName())
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		// The stream is written after the handler returns, so the request
+		// context can no longer be used.
+		if err := h.Service.Export(context.Background(), userID, req, w); err != nil {
+			log.Printf("export for user %s failed: %v", userID, err)
+		}
+	})
+}
+
+// CreateExport godoc
+// @Summary      Start an export job
+// @Description  Starts a background export for very large datasets. The file is written to export storage; poll GET /api/exports/{id} and download it from GET /api/exports/{id}/download once the status is COMPLETED.
+// @Tags         exports
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CreateExportRequest  true  "Export parameters"
+// @Success      202      {object}  domain.ExportJob  "Export started"
+// @Failure      400      {object}  ErrorResponse  "Invalid parameters"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/exports [post]
+func (h *ExportHandler) CreateExport(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	var body CreateExportRequest
+	if err := c.Bind().Body(&body); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input"})
+	}
+	if body.Dataset == "" {
+		body.Dataset = string(domain.ExportDatasetLinks)
+	}
+	if body.Format == "" {
+		body.Format = string(domain.ExportFormatCSV)
+	}
+
+	req, err := buildExportRequest(body.Dataset, body.Format, body.From, body.To)
+	if err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+	}
+
+	job, err := h.Service.StartExport(c.Context(), userID, req)
+	if err != nil {
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while starting the export"})
+	}
+	return c.Status(202).JSON(job)
+}
+
+// GetExport godoc
+// @Summary      Get export status
+// @Description  Returns the status and row count of an export job.
+// @Tags         exports
+// @Produce      json
+// @Param        id   path      string  true  "Export job ID"
+// @Success      200  {object}  domain.ExportJob  "Export job"
+// @Failure      401  {object}  ErrorResponse  "Unauthorized"
+// @Failure      404  {object}  ErrorResponse  "Export not found"
+// @Failure      500  {object}  ErrorResponse  "Internal server error"
+// @Router       /api/exports/{id} [get]
+func (h *ExportHandler) GetExport(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	job, err := h.Service.GetExport(c.Context(), c.Params("id"), userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrExportNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Export not found"})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while fetching the export"})
+	}
+	return c.JSON(job)
+}
+
+// DownloadExport godoc
+// @Summary      Download a finished export
+// @Description  Streams the file produced by a completed export job.
+// @Tags         exports
+// @Produce      text/csv
+// @Produce      application/x-ndjson
+// @Produce      application/vnd.apache.parquet
+// @Param        id   path      string  true  "Export job ID"
+// @Success      200  {file}    file  "Export file"
+// @Failure      401  {object}  ErrorResponse  "Unauthorized"
+// @Failure      404  {object}  ErrorResponse  "Export not found"
+// @Failure      409  {object}  ErrorResponse  "Export not finished"
+// @Failure      500  {object}  ErrorResponse  "Internal server error"
+// @Router       /api/exports/{id}/download [get]
+func (h *ExportHandler) DownloadExport(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	job, f, err := h.Service.OpenExport(c.Context(), c.Params("id"), userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrExportNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Export not found"})
+		}
+		if errors.Is(err, domain.ErrExportNotReady) {
+			return c.Status(409).JSON(ErrorResponse{Error: "The export has not finished yet"})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while opening the export"})
+	}
+
+	setDownloadHeaders(c, job.Format, job.Request().FileName())
+	// The response body stream closes f once it has been sent.
+	return c.SendStream(f)
+}
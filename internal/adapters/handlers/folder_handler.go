@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+	"github.com/gofiber/fiber/v3"
+)
+
+type FolderHandler struct {
+	Service ports.FolderService
+}
+
+func NewFolderHandler(service ports.FolderService) *FolderHandler {
+	return &FolderHandler{Service: service}
+}
+
+type FolderRequest struct {
+	Name string `json:"name" example:"Campaigns" binding:"required"`
+}
+
+// CreateFolder godoc
+// @Summary      Create a folder
+// @Description  Creates a folder for the authenticated user. Folder names are unique per user, ignoring case.
+// @Tags         folders
+// @Accept       json
+// @Produce      json
+// @Param        request  body      FolderRequest  true  "Folder data"
+// @Success      200      {object}  domain.Folder     "Folder created"
+// @Failure      400      {object}  ErrorResponse  "Invalid folder name"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Failure      409      {object}  ErrorResponse  "Folder already exists"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/folders [post]
+func (h *FolderHandler) CreateFolder(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	var req FolderRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input"})
+	}
+
+	folder, err := h.Service.CreateFolder(c.Context(), userID, req.Name)
+	if err != nil {
+		return h.folderError(c, err, "An error occurred while creating the folder")
+	}
+	return c.JSON(folder)
+}
+
+// RenameFolder godoc
+// @Summary      Rename a folder
+// @Description  Renames one of the authenticated user's folders. Links stay in the folder.
+// @Tags         folders
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string      true  "Folder ID"
+// @Param        request  body      FolderRequest  true  "New name"
+// @Success      200      {object}  domain.Folder     "Folder renamed"
+// @Failure      400      {object}  ErrorResponse  "Invalid folder name"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Failure      404      {object}  ErrorResponse  "Folder not found"
+// @Failure      409      {object}  ErrorResponse  "Another folder has this name"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/folders/{id} [patch]
+func (h *FolderHandler) RenameFolder(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	var req FolderRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input"})
+	}
+
+	folder, err := h.Service.RenameFolder(c.Context(), userID, c.Params("id"), req.Name)
+	if err != nil {
+		return h.folderError(c, err, "An error occurred while renaming the folder")
+	}
+	return c.JSON(folder)
+}
+
+// ListFolders godoc
+// @Summary      List folders
+// @Description  Lists the authenticated user's folders.
+// @Tags         folders
+// @Produce      json
+// @Success      200  {array}   domain.Folder  "Folders"
+// @Failure      401  {object}  ErrorResponse  "Unauthorized"
+// @Failure      500  {object}  ErrorResponse  "Internal server error"
+// @Router       /api/folders [get]
+func (h *FolderHandler) ListFolders(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	folders, err := h.Service.ListFolders(c.Context(), userID)
+	if err != nil {
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while listing folders"})
+	}
+	return c.JSON(folders)
+}
+
+func (h *FolderHandler) folderError(c fiber.Ctx, err error, fallback string) error {
+	if errors.Is(err, domain.ErrInvalidFolderName) {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+	}
+	if errors.Is(err, domain.ErrFolderNotFound) {
+		return c.Status(404).JSON(ErrorResponse{Error: "Folder not found"})
+	}
+	if isDuplicateError(err) {
+		return c.Status(409).JSON(ErrorResponse{Error: "A folder with this name already exists"})
+	}
+	return c.Status(500).JSON(ErrorResponse{Error: fallback})
+}
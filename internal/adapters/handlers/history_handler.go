@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/gofiber/fiber/v3"
+)
+
+// linkChangeFilter reads the audit log filters shared by the owner and admin
+// history endpoints. It returns the message of a 400 response when a filter
+// is invalid.
+func linkChangeFilter(c fiber.Ctx) (domain.LinkChangeFilter, string) {
+	filter := domain.LinkChangeFilter{Type: domain.LinkChangeType(strings.ToUpper(c.Query("type")))}
+	switch filter.Type {
+	case "", domain.ChangeCreate, domain.ChangeUpdate, domain.ChangePause, domain.ChangeActivate,
+		domain.ChangeBlock, domain.ChangeDelete, domain.ChangeRestore:
+	default:
+		return filter, "type must be one of CREATE, UPDATE, PAUSE, ACTIVATE, BLOCK, DELETE or RESTORE"
+	}
+
+	var err error
+	if filter.From, err = parseTimeParam(c.Query("from")); err != nil {
+		return filter, "from must be a date (YYYY-MM-DD) or RFC 3339 timestamp"
+	}
+	if filter.To, err = parseTimeParam(c.Query("to")); err != nil {
+		return filter, "to must be a date (YYYY-MM-DD) or RFC 3339 timestamp"
+	}
+	if filter.Limit, err = queryInt(c, "limit"); err != nil {
+		return filter, "Invalid limit"
+	}
+	if filter.Offset, err = queryInt(c, "offset"); err != nil {
+		return filter, "Invalid offset"
+	}
+	return filter, ""
+}
+
+// LinkHistory godoc
+// @Summary      Get a link's change history
+// @Description  Returns the audit log of one of the authenticated user's links since it was created, newest first: who created, edited, paused, blocked or restored it, from which IP address, and the fields each change touched before and after. Changes made by admins, such as blocks, are listed as ADMIN without their ID, and the ID and IP address are only shown on the user's own changes.
+// @Tags         links
+// @Produce      json
+// @Param        slug    path      string  true   "Shortened link slug"  example(abc123)
+// @Param        domain  query     string  false  "Custom domain the link belongs to"  example(go.acme.com)
+// @Param        type    query     string  false  "Change type"  Enums(CREATE, UPDATE, PAUSE, ACTIVATE, BLOCK, DELETE, RESTORE)
+// @Param        from    query     string  false  "Changed at or after (YYYY-MM-DD or RFC 3339)"  example(2025-01-01)
+// @Param        to      query     string  false  "Changed before (YYYY-MM-DD or RFC 3339)"  example(2025-02-01)
+// @Param        limit   query     int     false  "Page size (default 50, max 200)"
+// @Param        offset  query     int     false  "Number of changes to skip"
+// @Success      200     {object}  domain.LinkChangePage  "Changes"
+// @Failure      400     {object}  ErrorResponse  "Invalid filters"
+// @Failure      401     {object}  ErrorResponse  "Unauthorized"
+// @Failure      404     {object}  ErrorResponse  "Link not found"
+// @Failure      500     {object}  ErrorResponse  "Internal server error"
+// @Router       /api/links/{slug}/history [get]
+func (h *HTTPHandler) LinkHistory(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	linkDomain, err := h.queryDomain(c)
+	if err != nil {
+		return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+	}
+
+	filter, invalid := linkChangeFilter(c)
+	if invalid != "" {
+		return c.Status(400).JSON(ErrorResponse{Error: invalid})
+	}
+
+	page, err := h.Service.LinkHistory(c.Context(), userID, linkDomain, slugParam(c), filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrLinkNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while loading the link's history"})
+	}
+	return c.JSON(page)
+}
+
+// ListLinkChanges godoc
+// @Summary      Query the link audit log (admin)
+// @Description  Returns changes to every user's links, newest first, including links that have since been deleted. Requires an admin account.
+// @Tags         admin
+// @Produce      json
+// @Param        link_id   query     string  false  "Only changes to this link"
+// @Param        actor_id  query     string  false  "Only changes by this user"
+// @Param        type      query     string  false  "Change type"  Enums(CREATE, UPDATE, PAUSE, ACTIVATE, BLOCK, DELETE, RESTORE)
+// @Param        from      query     string  false  "Changed at or after (YYYY-MM-DD or RFC 3339)"  example(2025-01-01)
+// @Param        to        query     string  false  "Changed before (YYYY-MM-DD or RFC 3339)"  example(2025-02-01)
+// @Param        limit     query     int     false  "Page size (default 50, max 200)"
+// @Param        offset    query     int     false  "Number of changes to skip"
+// @Success      200       {object}  domain.LinkChangePage  "Changes"
+// @Failure      400       {object}  ErrorResponse  "Invalid filters"
+// @Failure      401       {object}  ErrorResponse  "Unauthorized"
+// @Failure      403       {object}  ErrorResponse  "Admin access required"
+// @Failure      500       {object}  ErrorResponse  "Internal server error"
+// @Router       /api/admin/links/history [get]
+func (h *AdminHandler) ListLinkChanges(c fiber.Ctx) error {
+	filter, invalid := linkChangeFilter(c)
+	if invalid != "" {
+		return c.Status(400).JSON(ErrorResponse{Error: invalid})
+	}
+	filter.LinkID = c.Query("link_id")
+	filter.ActorID = c.Query("actor_id")
+
+	page, err := h.Admin.ListLinkChanges(c.Context(), filter)
+	if err != nil {
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while loading link changes"})
+	}
+	return c.JSON(page)
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/esdrassantos06/go-shortener/internal/core/domain"
@@ -13,21 +14,81 @@ import (
 
 type HTTPHandler struct {
 	Service        ports.LinkService
+	QR             ports.QRService
+	Variants       ports.VariantService
+	Crawlers       ports.CrawlerClassifier
+	Countries      ports.CountryLocator
+	Interstitials  *InterstitialTemplates
 	BaseURL        string
 	ShortURLDomain string
+
+	// defaultHosts are the hostnames that serve links on the default domain.
+	// Requests arriving on any other Host are resolved as custom domains.
+	defaultHosts map[string]struct{}
 }
 
-func NewHTTPHandler(service ports.LinkService, baseURL string, shortURLDomain string) *HTTPHandler {
-	return &HTTPHandler{
+// NewHTTPHandler builds the link handlers. A nil countries locator disables
+// country routing rules; nil interstitials use the embedded page.
+func NewHTTPHandler(service ports.LinkService, qr ports.QRService, variants ports.VariantService, crawlers ports.CrawlerClassifier, countries ports.CountryLocator, interstitials *InterstitialTemplates, baseURL string, shortURLDomain string) *HTTPHandler {
+	h := &HTTPHandler{
 		Service:        service,
+		QR:             qr,
+		Variants:       variants,
+		Crawlers:       crawlers,
+		Countries:      countries,
+		Interstitials:  interstitials,
 		BaseURL:        baseURL,
 		ShortURLDomain: shortURLDomain,
+		defaultHosts:   make(map[string]struct{}),
+	}
+	for _, raw := range []string{baseURL, shortURLDomain} {
+		if u, err := url.Parse(raw); err == nil && u.Hostname() != "" {
+			h.defaultHosts[strings.ToLower(u.Hostname())] = struct{}{}
+		}
 	}
+	return h
 }
 
 type CreateShortLinkRequest struct {
 	TargetURL  string `json:"target_url" example:"https://example.com" binding:"required"`
 	CustomSlug string `json:"custom_slug,omitempty" example:"my-custom-link"`
+	Domain     string `json:"domain,omitempty" example:"go.acme.com"`
+	// ReuseExisting returns the user's existing link for the same target
+	// instead of creating a new random slug. Ignored with a custom slug, when
+	// any other setting is given, and by the bulk endpoint.
+	ReuseExisting bool `json:"reuse_existing,omitempty" example:"true"`
+	// Title is fetched from the target page when left empty.
+	Title       string `json:"title,omitempty" example:"Example Domain"`
+	Description string `json:"description,omitempty" example:"Landing page for the spring campaign"`
+	Notes       string `json:"notes,omitempty" example:"Printed on the Q3 flyers"`
+	// The og_ fields override what link-preview crawlers are shown.
+	OGTitle       string `json:"og_title,omitempty" example:"Spring sale: 30% off"`
+	OGDescription string `json:"og_description,omitempty" example:"Only until March 31"`
+	OGImageURL    string `json:"og_image_url,omitempty" example:"https://example.com/spring.png"`
+	// Rules are evaluated in order against the visitor's device and OS; the
+	// first match overrides target_url.
+	Rules        []domain.RoutingRule `json:"rules,omitempty"`
+	QueryOptions domain.QueryOptions  `json:"query_options,omitzero"`
+	// ForwardPath makes the link a prefix: /<slug>/rest redirects to
+	// <target_url>/rest.
+	ForwardPath bool `json:"forward_path,omitempty" example:"true"`
+	// Schedule limits when the link is live.
+	Schedule domain.LinkSchedule `json:"schedule,omitzero"`
+	// MaxUses makes the link stop resolving after that many opens; 1 is
+	// view-once.
+	MaxUses      int                 `json:"max_uses,omitempty" example:"1"`
+	Interstitial domain.Interstitial `json:"interstitial,omitzero"`
+}
+
+func (r CreateShortLinkRequest) metadata() domain.LinkMetadata {
+	return domain.LinkMetadata{
+		Title:         strings.TrimSpace(r.Title),
+		Description:   strings.TrimSpace(r.Description),
+		Notes:         r.Notes,
+		OGTitle:       strings.TrimSpace(r.OGTitle),
+		OGDescription: strings.TrimSpace(r.OGDescription),
+		OGImageURL:    strings.TrimSpace(r.OGImageURL),
+	}
 }
 
 type CreateShortLinkResponse struct {
@@ -39,25 +100,6 @@ type ErrorResponse struct {
 	Error string `json:"error" example:"Invalid input"`
 }
 
-var reservedSlugs = map[string]struct{}{
-	"api":         {},
-	"swagger":     {},
-	"shorten":     {},
-	"admin":       {},
-	"health":      {},
-	"metrics":     {},
-	"docs":        {},
-	"static":      {},
-	"assets":      {},
-	"favicon.ico": {},
-}
-
-func isReservedSlug(slug string) bool {
-	slug = strings.ToLower(slug)
-	_, exists := reservedSlugs[slug]
-	return exists
-}
-
 func isDuplicateError(err error) bool {
 	var pgErr *pgconn.PgError
 	if errors.As(err, &pgErr) {
@@ -66,17 +108,126 @@ func isDuplicateError(err error) bool {
 	return strings.Contains(err.Error(), "duplicate key value violates unique constraint")
 }
 
+// isInvalidLinkInput reports whether creating a link failed because of the
+// request itself.
+func isInvalidLinkInput(err error) bool {
+	for _, target := range []error{
+		domain.ErrInvalidTargetURL,
+		domain.ErrInvalidSlug,
+		domain.ErrReservedSlug,
+		domain.ErrMetadataTooLong,
+		domain.ErrInvalidImageURL,
+		domain.ErrInvalidRoutingRule,
+		domain.ErrInvalidQueryOptions,
+		domain.ErrInvalidSchedule,
+		domain.ErrInvalidMaxUses,
+		domain.ErrInvalidInterstitial,
+		domain.ErrTargetBlocked,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// shortURL builds the public short URL for a link, using the link's custom
+// domain when it has one.
+func (h *HTTPHandler) shortURL(link domain.Link) string {
+	if link.Domain != "" {
+		return fmt.Sprintf("https://%s/%s", link.Domain, link.ShortID)
+	}
+	shortURLDomain := h.ShortURLDomain
+	if shortURLDomain == "" {
+		shortURLDomain = h.BaseURL
+	}
+	return fmt.Sprintf("%s/%s", shortURLDomain, link.ShortID)
+}
+
+// linkDomain maps a hostname to the link domain it serves, returning an empty
+// string for the default short domain.
+func (h *HTTPHandler) linkDomain(raw string) (string, error) {
+	host, err := domain.NormalizeHostname(raw)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := h.defaultHosts[host]; ok {
+		return "", nil
+	}
+	return host, nil
+}
+
+// requestDomain is linkDomain for the request Host header. Hosts that are not
+// valid DNS names (e.g. "localhost") fall back to the default domain.
+func (h *HTTPHandler) requestDomain(c fiber.Ctx) string {
+	host, err := h.linkDomain(c.Hostname())
+	if err != nil {
+		return ""
+	}
+	return host
+}
+
+// slugParam returns the :slug route parameter. Nested slugs such as
+// "guide/v2" reach the owner endpoints percent-encoded ("guide%2Fv2").
+func slugParam(c fiber.Ctx) string {
+	slug := c.Params("slug")
+	if unescaped, err := url.PathUnescape(slug); err == nil {
+		return unescaped
+	}
+	return slug
+}
+
+// requestPath joins the :slug parameter with whatever the /:slug/* wildcard
+// matched, giving the full path to resolve.
+func requestPath(c fiber.Ctx) string {
+	path := slugParam(c)
+	if rest := c.Params("*"); rest != "" {
+		path += "/" + rest
+	}
+	return path
+}
+
+// routesPerVisitor reports whether the link's target depends on who visits
+// it, when, or how often it was opened, or is about to change, so responses
+// must not be cached.
+func routesPerVisitor(link domain.Link) bool {
+	return len(link.Rules) > 0 || len(link.Variants) > 0 || !link.Schedule.IsZero() || link.IsLimited() || link.ChangePending
+}
+
+// visitorFromRequest copies the visitor details out of the request, since
+// Fiber's strings are only valid inside the handler and clicks are tracked
+// asynchronously.
+func (h *HTTPHandler) visitorFromRequest(c fiber.Ctx) domain.Visitor {
+	visitor := domain.Visitor{
+		IP:        strings.Clone(c.IP()),
+		UserAgent: strings.Clone(c.Get(fiber.HeaderUserAgent)),
+		Referrer:  strings.Clone(c.Get(fiber.HeaderReferer)),
+	}
+	visitor.Query = string(c.Request().URI().QueryString())
+	visitor.Confirmed = c.Method() == fiber.MethodPost
+	if h.Countries != nil {
+		visitor.Country = h.Countries.Locate(visitor.IP, func(key string) string {
+			return strings.Clone(c.Get(key))
+		})
+	}
+	return visitor
+}
+
 // CreateShortLink godoc
 // @Summary      Create a shortened link
-// @Description  Create a new shortened link from a URL. Requires authentication. Optionally allows defining a custom slug. Reserved slugs (api, swagger, shorten, admin, health, metrics, docs, static, assets, favicon.ico) cannot be used.
+// @Description  Create a new shortened link from a URL. Requires authentication. Optionally allows defining a custom slug, a title, a description and private notes; without a title, the target page's title is fetched in the background. With reuse_existing, no custom slug and no other settings, the user's existing active link for the same normalized target URL is returned instead of a new one. Optional routing rules send visitors on a given OS or device type (e.g. iOS, Android, desktop) to another target. Custom slugs may be nested with "/" (e.g. guide/v2). Reserved slugs (api, swagger, shorten, admin, health, metrics, docs, static, assets, favicon.ico) cannot be used.
 // @Tags         links
 // @Accept       json
 // @Produce      json
-// @Param        request  body      CreateShortLinkRequest  true  "Link data"
+// @Param        request          body      CreateShortLinkRequest  true   "Link data"
+// @Param        Idempotency-Key  header    string                  false  "Replays the original response when a request is retried with the same key (24h, per user)"
 // @Success      200      {object}  CreateShortLinkResponse  "Link created successfully"
-// @Failure      400      {object}  ErrorResponse  "Validation error or reserved slug"
+// @Failure      400      {object}  ErrorResponse  "Validation error, reserved slug, invalid metadata or invalid routing rules"
 // @Failure      401      {object}  ErrorResponse  "Unauthorized"
-// @Failure      409      {object}  ErrorResponse  "Custom slug already exists"
+// @Failure      403      {object}  ErrorResponse  "Domain not verified"
+// @Failure      404      {object}  ErrorResponse  "Domain not found"
+// @Failure      409      {object}  ErrorResponse  "Custom slug already exists, or a request with the same Idempotency-Key is in progress"
+// @Failure      422      {object}  ErrorResponse  "Idempotency-Key reused with a different body"
 // @Failure      500      {object}  ErrorResponse  "Internal server error"
 // @Router       /api/shorten [post]
 func (h *HTTPHandler) CreateShortLink(c fiber.Ctx) error {
@@ -92,14 +243,43 @@ func (h *HTTPHandler) CreateShortLink(c fiber.Ctx) error {
 		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input"})
 	}
 
-	if req.CustomSlug != "" && isReservedSlug(req.CustomSlug) {
+	if req.CustomSlug != "" && domain.IsReservedSlug(req.CustomSlug) {
 		return c.Status(400).JSON(ErrorResponse{
 			Error: fmt.Sprintf("The slug '%s' is reserved and cannot be used. Please choose a different one.", req.CustomSlug),
 		})
 	}
 
-	link, err := h.Service.ShortenURL(c.Context(), req.TargetURL, req.CustomSlug, &userID)
+	var linkDomain string
+	if req.Domain != "" {
+		var err error
+		if linkDomain, err = h.linkDomain(req.Domain); err != nil {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid domain"})
+		}
+	}
+
+	link, err := h.Service.ShortenURL(c.Context(), domain.LinkInput{
+		TargetURL:     req.TargetURL,
+		CustomSlug:    req.CustomSlug,
+		Domain:        linkDomain,
+		ReuseExisting: req.ReuseExisting,
+		ForwardPath:   req.ForwardPath,
+		Rules:         req.Rules,
+		QueryOptions:  req.QueryOptions,
+		Schedule:      req.Schedule,
+		MaxUses:       req.MaxUses,
+		Interstitial:  req.Interstitial,
+		LinkMetadata:  req.metadata(),
+	}, &userID)
 	if err != nil {
+		if isInvalidLinkInput(err) {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+		}
+		if errors.Is(err, domain.ErrDomainNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Domain not found"})
+		}
+		if errors.Is(err, domain.ErrDomainNotVerified) {
+			return c.Status(403).JSON(ErrorResponse{Error: "Domain has not been verified yet"})
+		}
 		if isDuplicateError(err) {
 			slug := req.CustomSlug
 			if slug == "" {
@@ -112,59 +292,149 @@ func (h *HTTPHandler) CreateShortLink(c fiber.Ctx) error {
 		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while creating the link"})
 	}
 
-	shortURLDomain := h.ShortURLDomain
-	if shortURLDomain == "" {
-		shortURLDomain = h.BaseURL
-	}
 	return c.JSON(CreateShortLinkResponse{
-		ShortURL: fmt.Sprintf("%s/%s", shortURLDomain, link.ShortID),
+		ShortURL: h.shortURL(link),
 		Details:  link,
 	})
 }
 
 // Redirect godoc
 // @Summary      Redirect to original URL
-// @Description  Redirects to the original URL associated with the provided slug
+// @Description  Redirects to the original URL associated with the provided slug. The link is looked up on the domain given by the Host header. Longer paths such as /guide/getting-started resolve to a nested slug with that exact path, or else to the longest prefix link with path forwarding, whose target gets the rest of the path appended. Link-preview crawlers (Slack, Discord, Facebook, X, LinkedIn, ...) get an HTML page with the link's Open Graph and Twitter Card tags instead, which does not redirect, and are not counted as clicks; they are held to the link's schedule, use limit and interstitial like anyone else. Limited-use links answer GET with a confirmation page whose form POSTs back to the same URL; only the POST spends a use and redirects. Links with an interstitial, enabled by the owner or forced on flagged links by an admin, answer with a page naming the destination and a continue button instead of redirecting.
 // @Tags         links
 // @Accept       json
 // @Produce      json
+// @Produce      html
 // @Param        slug  path      string  true  "Shortened link slug"  example(abc123)
+// @Success      200   {string}  string  "Preview page for link-preview crawlers, confirmation page for limited-use links, or interstitial page"
 // @Success      301   {string}  string  "Permanent redirect"
-// @Failure      404   {string}  string  "Link not found"
+// @Success      302   {string}  string  "Temporary redirect, for links with routing rules, variants, a schedule or a use limit"
+// @Success      303   {string}  string  "Redirect after confirming a limited-use link"
+// @Failure      403   {string}  string  "Link blocked by a moderator or its destination is on a blocklist"
+// @Failure      404   {string}  string  "Link not found or not live yet"
+// @Failure      410   {string}  string  "Link has ended or been used up"
 // @Router       /{slug} [get]
+// @Router       /{slug} [post]
 func (h *HTTPHandler) Redirect(c fiber.Ctx) error {
-	slug := c.Params("slug")
+	path := requestPath(c)
 
-	link, err := h.Service.ResolveURL(c.Context(), slug)
+	if c.Method() == fiber.MethodGet && h.Crawlers != nil && h.Crawlers.IsPreviewCrawler(c.Get(fiber.HeaderUserAgent)) {
+		link, err := h.Service.PreviewLink(c.Context(), h.requestDomain(c), path)
+		if err != nil {
+			return h.sendRedirectError(c, err)
+		}
+		// Crawlers get the interstitial too, so a spoofed User-Agent cannot
+		// skip the warning on a flagged link.
+		if link.ShowsInterstitial() {
+			return h.sendInterstitial(c, link)
+		}
+		return h.sendPreview(c, link)
+	}
+
+	link, err := h.Service.ResolvePath(c.Context(), h.requestDomain(c), path, h.visitorFromRequest(c))
 	if err != nil {
-		return c.Status(404).SendString("Link not found")
+		return h.sendRedirectError(c, err)
 	}
 
-	return c.Redirect().Status(fiber.StatusMovedPermanently).To(link.TargetURL)
+	if link.ShowsInterstitial() {
+		return h.sendInterstitial(c, link)
+	}
+
+	// A permanent redirect would be cached by the browser, pinning the
+	// visitor to one target and hiding later clicks from rules and variants,
+	// or outliving the link's schedule and use limit.
+	status := fiber.StatusMovedPermanently
+	switch {
+	case c.Method() == fiber.MethodPost:
+		status = fiber.StatusSeeOther
+	case routesPerVisitor(link):
+		status = fiber.StatusFound
+	}
+	return c.Redirect().Status(status).To(link.TargetURL)
+}
+
+// sendRedirectError answers a short URL that does not lead anywhere right
+// now, or only after confirmation.
+func (h *HTTPHandler) sendRedirectError(c fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, domain.ErrConfirmationNeeded):
+		return h.sendConfirmation(c)
+	case errors.Is(err, domain.ErrLinkEnded):
+		return c.Status(410).SendString("Link has ended")
+	case errors.Is(err, domain.ErrLinkUsedUp):
+		return c.Status(410).SendString("Link has been used up")
+	case errors.Is(err, domain.ErrTargetBlocked):
+		return c.Status(403).SendString("Link disabled: the destination is on a blocklist")
+	case errors.Is(err, domain.ErrLinkBlocked):
+		return c.Status(403).SendString("Link disabled by a moderator")
+	}
+	return c.Status(404).SendString("Link not found")
 }
 
 // ResolveSlug - Public endpoint for resolving (used by the frontend)
 // @Summary      Resolve a shortened link
-// @Description  Returns the target URL for a given slug. Public endpoint, no authentication required.
+// @Description  Returns the target URL for a given slug. Public endpoint, no authentication required. Paths below the slug (/api/resolve/guide/getting-started) resolve like they do on the short URL. Any query parameters other than domain are treated as the short URL's query string, for links that forward it to their target. Limited-use links answer GET with 409 and resolve, spending a use, on POST. Links with an interstitial include it in the response, for the frontend to show before continuing.
 // @Tags         links
 // @Accept       json
 // @Produce      json
-// @Param        slug  path      string  true  "Shortened link slug"  example(abc123)
+// @Param        slug    path      string  true   "Shortened link slug"  example(abc123)
+// @Param        domain  query     string  false  "Custom domain the link belongs to"  example(go.acme.com)
 // @Success      200   {object}  map[string]string  "Target URL"
-// @Failure      404   {object}  ErrorResponse  "Link not found"
+// @Failure      403   {object}  ErrorResponse  "Link blocked by a moderator or its destination is on a blocklist"
+// @Failure      404   {object}  ErrorResponse  "Link not found or not live yet"
+// @Failure      409   {object}  ErrorResponse  "Limited-use link, POST to open it"
+// @Failure      410   {object}  ErrorResponse  "Link has ended or been used up"
 // @Router       /api/resolve/{slug} [get]
+// @Router       /api/resolve/{slug} [post]
 func (h *HTTPHandler) ResolveSlug(c fiber.Ctx) error {
-	slug := c.Params("slug")
+	path := requestPath(c)
 
-	link, err := h.Service.ResolveURL(c.Context(), slug)
+	linkDomain, err := h.queryDomain(c)
 	if err != nil {
 		return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
 	}
 
-	c.Set("Cache-Control", "public, max-age=60, s-maxage=60, stale-while-revalidate=300")
+	visitor := h.visitorFromRequest(c)
+	if query, err := url.ParseQuery(visitor.Query); err == nil {
+		query.Del("domain")
+		visitor.Query = query.Encode()
+	}
 
-	return c.JSON(fiber.Map{
+	link, err := h.Service.ResolvePath(c.Context(), linkDomain, path, visitor)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrConfirmationNeeded):
+			c.Set("Cache-Control", "private, no-store")
+			return c.Status(409).JSON(ErrorResponse{Error: "Confirmation required: POST to open this limited-use link"})
+		case errors.Is(err, domain.ErrLinkEnded):
+			return c.Status(410).JSON(ErrorResponse{Error: "Link has ended"})
+		case errors.Is(err, domain.ErrLinkUsedUp):
+			return c.Status(410).JSON(ErrorResponse{Error: "Link has been used up"})
+		case errors.Is(err, domain.ErrTargetBlocked):
+			return c.Status(403).JSON(ErrorResponse{Error: "Link disabled: the destination is on a blocklist"})
+		case errors.Is(err, domain.ErrLinkBlocked):
+			return c.Status(403).JSON(ErrorResponse{Error: "Link disabled by a moderator"})
+		}
+		return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+	}
+
+	if routesPerVisitor(link) {
+		c.Set("Cache-Control", "private, no-store")
+	} else {
+		c.Set("Cache-Control", "public, max-age=60, s-maxage=60, stale-while-revalidate=300")
+	}
+
+	resp := fiber.Map{
 		"target_url": link.TargetURL,
 		"status":     link.Status,
-	})
+	}
+	if link.ShowsInterstitial() {
+		page := h.interstitialPage(link)
+		resp["interstitial"] = fiber.Map{
+			"target_host": page.TargetHost,
+			"countdown":   page.Countdown,
+			"flagged":     page.Flagged,
+		}
+	}
+	return c.JSON(resp)
 }
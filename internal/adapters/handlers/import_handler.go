@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+	"github.com/gofiber/fiber/v3"
+)
+
+type ImportHandler struct {
+	Service ports.ImportService
+}
+
+func NewImportHandler(service ports.ImportService) *ImportHandler {
+	return &ImportHandler{Service: service}
+}
+
+// CreateImport godoc
+// @Summary      Import links from an export file
+// @Description  Starts a background job that imports links from a Bitly, Rebrandly or generic CSV export (slug, target, created_at, clicks, tags), or a CSV links export of this API with its settings. Original slugs and click counts are preserved; slugs that already exist are reported as conflicts. Poll GET /api/imports/{id} for progress.
+// @Tags         imports
+// @Accept       multipart/form-data
+// @Accept       text/csv
+// @Produce      json
+// @Param        format  query     string  true   "Export format"  Enums(bitly, rebrandly, csv)
+// @Param        domain  query     string  false  "Verified custom domain to import the links into"
+// @Param        file    formData  file    false  "Export file"
+// @Success      202     {object}  domain.ImportJob  "Import started"
+// @Failure      400     {object}  ErrorResponse  "Invalid file or format"
+// @Failure      401     {object}  ErrorResponse  "Unauthorized"
+// @Failure      403     {object}  ErrorResponse  "Domain not verified"
+// @Failure      404     {object}  ErrorResponse  "Domain not found"
+// @Failure      500     {object}  ErrorResponse  "Internal server error"
+// @Router       /api/imports [post]
+func (h *ImportHandler) CreateImport(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	if !isCSVUpload(c) {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: upload the export as text/csv or in the multipart 'file' field"})
+	}
+	f, err := openCSVUpload(c)
+	if err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+	}
+	defer f.Close()
+
+	format := domain.ImportFormat(c.Query("format", string(domain.ImportFormatCSV)))
+	job, err := h.Service.StartImport(c.Context(), userID, format, c.Query("domain"), f)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUnsupportedFormat):
+			return c.Status(400).JSON(ErrorResponse{Error: "format must be one of bitly, rebrandly or csv"})
+		case errors.Is(err, domain.ErrInvalidHostname), errors.Is(err, domain.ErrDomainNotFound):
+			return c.Status(404).JSON(ErrorResponse{Error: "Domain not found"})
+		case errors.Is(err, domain.ErrDomainNotVerified):
+			return c.Status(403).JSON(ErrorResponse{Error: "Domain has not been verified yet"})
+		case errors.Is(err, domain.ErrEmptyImport):
+			return c.Status(400).JSON(ErrorResponse{Error: "The file contains no links"})
+		case errors.Is(err, domain.ErrInvalidImportFile):
+			return c.Status(400).JSON(ErrorResponse{Error: err.Error()})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while starting the import"})
+	}
+
+	return c.Status(202).JSON(job)
+}
+
+// GetImport godoc
+// @Summary      Get import progress
+// @Description  Returns the status, progress counters, conflicts and per-row errors of an import job.
+// @Tags         imports
+// @Produce      json
+// @Param        id   path      string  true  "Import job ID"
+// @Success      200  {object}  domain.ImportJob  "Import job"
+// @Failure      401  {object}  ErrorResponse  "Unauthorized"
+// @Failure      404  {object}  ErrorResponse  "Import not found"
+// @Failure      500  {object}  ErrorResponse  "Internal server error"
+// @Router       /api/imports/{id} [get]
+func (h *ImportHandler) GetImport(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	job, err := h.Service.GetImport(c.Context(), c.Params("id"), userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrImportNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Import not found"})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while fetching the import"})
+	}
+
+	return c.JSON(job)
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/gofiber/fiber/v3"
+)
+
+var interstitialTemplate = template.Must(template.ParseFS(templateFS, "templates/interstitial.html"))
+
+// InterstitialPage is the data interstitial templates are rendered with.
+// Countdown is 0 unless the page continues by itself; flagged links never do.
+type InterstitialPage struct {
+	ShortURL   string
+	TargetURL  string
+	TargetHost string
+	Countdown  int
+	Flagged    bool
+}
+
+func (h *HTTPHandler) interstitialPage(link domain.Link) InterstitialPage {
+	page := InterstitialPage{
+		ShortURL:   h.shortURL(link),
+		TargetURL:  link.TargetURL,
+		TargetHost: link.TargetURL,
+		Flagged:    link.Flagged,
+	}
+	if u, err := url.Parse(link.TargetURL); err == nil && u.Host != "" {
+		page.TargetHost = u.Hostname()
+	}
+	if !link.Flagged {
+		page.Countdown = link.Interstitial.Countdown
+	}
+	return page
+}
+
+// InterstitialTemplates picks the interstitial template for a link domain.
+// Custom domains stand in for workspaces: each can have its own template,
+// falling back to a shared one and then to the embedded page.
+type InterstitialTemplates struct {
+	fallback *template.Template
+	byDomain map[string]*template.Template
+}
+
+// LoadInterstitialTemplates reads the templates in dir, as set by
+// INTERSTITIAL_TEMPLATE_DIR: <hostname>.html for a custom domain and
+// default.html for every other link. An empty dir uses the embedded page.
+func LoadInterstitialTemplates(dir string) (*InterstitialTemplates, error) {
+	t := &InterstitialTemplates{
+		fallback: interstitialTemplate,
+		byDomain: make(map[string]*template.Template),
+	}
+	if dir == "" {
+		return t, nil
+	}
+
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.html"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		tmpl, err := template.ParseFiles(path)
+		if err != nil {
+			return nil, fmt.Errorf("interstitial template %s: %w", filepath.Base(path), err)
+		}
+		name := strings.ToLower(strings.TrimSuffix(filepath.Base(path), ".html"))
+		if name == "default" {
+			t.fallback = tmpl
+		} else {
+			t.byDomain[name] = tmpl
+		}
+	}
+	return t, nil
+}
+
+func (t *InterstitialTemplates) forDomain(linkDomain string) *template.Template {
+	if tmpl, ok := t.byDomain[linkDomain]; ok {
+		return tmpl
+	}
+	return t.fallback
+}
+
+// sendInterstitial shows the visitor where the link goes, with a button to
+// continue, instead of redirecting.
+func (h *HTTPHandler) sendInterstitial(c fiber.Ctx, link domain.Link) error {
+	tmpl := interstitialTemplate
+	if h.Interstitials != nil {
+		tmpl = h.Interstitials.forDomain(link.Domain)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, h.interstitialPage(link)); err != nil {
+		return c.Status(500).SendString("An error occurred while rendering the page")
+	}
+
+	c.Set("Cache-Control", "private, no-store")
+	c.Set("X-Robots-Tag", "noindex")
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.Send(buf.Bytes())
+}
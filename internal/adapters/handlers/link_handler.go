@@ -0,0 +1,645 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/gofiber/fiber/v3"
+)
+
+// UpdateLinkRequest changes a link's metadata. Omitted fields are left
+// unchanged; an empty string clears the field.
+type UpdateLinkRequest struct {
+	Title         *string `json:"title" example:"Spring sale"`
+	Description   *string `json:"description" example:"Landing page for the spring campaign"`
+	Notes         *string `json:"notes" example:"Printed on the Q3 flyers"`
+	OGTitle       *string `json:"og_title" example:"Spring sale: 30% off"`
+	OGDescription *string `json:"og_description" example:"Only until March 31"`
+	OGImageURL    *string `json:"og_image_url" example:"https://example.com/spring.png"`
+}
+
+type RewriteTargetsRequest struct {
+	From   string `json:"from" example:"https://old.example.com/docs" binding:"required"`
+	To     string `json:"to" example:"https://docs.example.com" binding:"required"`
+	DryRun bool   `json:"dry_run,omitempty" example:"true"`
+}
+
+type RewriteTargetsResponse struct {
+	DryRun  bool                  `json:"dry_run" example:"false"`
+	Updated int                   `json:"updated" example:"12"`
+	Changes []domain.TargetChange `json:"changes"`
+}
+
+type SetLinkTagsRequest struct {
+	Tags []string `json:"tags" example:"marketing,q3"`
+}
+
+type SetLinkRulesRequest struct {
+	Rules []domain.RoutingRule `json:"rules"`
+}
+
+type SetLinkPathForwardingRequest struct {
+	Enabled bool `json:"enabled" example:"true"`
+}
+
+type SetLinkMaxUsesRequest struct {
+	// MaxUses is the number of opens allowed from now on; 0 removes the limit.
+	MaxUses int `json:"max_uses" example:"1"`
+}
+
+type SetLinkFolderRequest struct {
+	// FolderID moves the link into a folder; null removes it from its folder.
+	FolderID *string `json:"folder_id" example:"6b1f0c1e-8a7d-4c5e-9f3a-2d4b5c6d7e8f"`
+}
+
+// ListLinks godoc
+// @Summary      List links
+// @Description  Lists the authenticated user's links, newest first, optionally filtered by tag name (case-insensitive) or folder.
+// @Tags         links
+// @Produce      json
+// @Param        tag     query     string  false  "Tag name"  example(marketing)
+// @Param        folder  query     string  false  "Folder ID"
+// @Param        limit   query     int     false  "Page size (default 50, max 200)"
+// @Param        offset  query     int     false  "Number of links to skip"
+// @Success      200     {object}  domain.LinkPage  "Links"
+// @Failure      400     {object}  ErrorResponse  "Invalid pagination"
+// @Failure      401     {object}  ErrorResponse  "Unauthorized"
+// @Failure      500     {object}  ErrorResponse  "Internal server error"
+// @Router       /api/links [get]
+func (h *HTTPHandler) ListLinks(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	limit, err := queryInt(c, "limit")
+	if err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid limit"})
+	}
+	offset, err := queryInt(c, "offset")
+	if err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid offset"})
+	}
+
+	filter := domain.LinkFilter{
+		Tag:      c.Query("tag"),
+		FolderID: c.Query("folder"),
+		Limit:    limit,
+		Offset:   offset,
+	}
+
+	page, err := h.Service.ListLinks(c.Context(), userID, filter)
+	if err != nil {
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while listing links"})
+	}
+	return c.JSON(page)
+}
+
+// SearchLinks godoc
+// @Summary      Search links
+// @Description  Full-text search over the authenticated user's links, matching slug, target URL, title, description, notes and tags. Words match as prefixes and partial slugs match by trigram similarity. Results are ranked by relevance.
+// @Tags         links
+// @Produce      json
+// @Param        q       query     string  true   "Search text"  example(spring sale)
+// @Param        status  query     string  false  "Link status"  Enums(ACTIVE, PAUSED, BLOCKED)
+// @Param        from    query     string  false  "Created at or after (YYYY-MM-DD or RFC 3339)"  example(2025-01-01)
+// @Param        to      query     string  false  "Created before (YYYY-MM-DD or RFC 3339)"  example(2025-02-01)
+// @Param        limit   query     int     false  "Page size (default 50, max 200)"
+// @Param        offset  query     int     false  "Number of links to skip"
+// @Success      200     {object}  domain.LinkPage  "Matching links"
+// @Failure      400     {object}  ErrorResponse  "Invalid query or filters"
+// @Failure      401     {object}  ErrorResponse  "Unauthorized"
+// @Failure      500     {object}  ErrorResponse  "Internal server error"
+// @Router       /api/links/search [get]
+func (h *HTTPHandler) SearchLinks(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	search := domain.LinkSearch{
+		Query:  c.Query("q"),
+		Status: domain.LinkStatus(strings.ToUpper(c.Query("status"))),
+	}
+	switch search.Status {
+	case "", domain.StatusActive, domain.StatusPaused, domain.StatusBlocked:
+	default:
+		return c.Status(400).JSON(ErrorResponse{Error: "status must be 'ACTIVE', 'PAUSED' or 'BLOCKED'"})
+	}
+
+	var err error
+	if search.From, err = parseTimeParam(c.Query("from")); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "from must be a date (YYYY-MM-DD) or RFC 3339 timestamp"})
+	}
+	if search.To, err = parseTimeParam(c.Query("to")); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "to must be a date (YYYY-MM-DD) or RFC 3339 timestamp"})
+	}
+	if search.Limit, err = queryInt(c, "limit"); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid limit"})
+	}
+	if search.Offset, err = queryInt(c, "offset"); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid offset"})
+	}
+
+	page, err := h.Service.SearchLinks(c.Context(), userID, search)
+	if err != nil {
+		if errors.Is(err, domain.ErrEmptySearch) {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while searching links"})
+	}
+	return c.JSON(page)
+}
+
+// FindLinksByTarget godoc
+// @Summary      Find links by target
+// @Description  Returns the authenticated user's links pointing at or under a URL (matched on normalized targets, at path boundaries), or at a domain and its subdomains. Pass exactly one of url or domain. At most 1000 links are returned.
+// @Tags         links
+// @Produce      json
+// @Param        url     query     string  false  "Target URL prefix"  example(https://example.com/docs)
+// @Param        domain  query     string  false  "Target domain"  example(example.com)
+// @Success      200     {object}  domain.TargetLookupResult  "Matching links"
+// @Failure      400     {object}  ErrorResponse  "Invalid URL or domain"
+// @Failure      401     {object}  ErrorResponse  "Unauthorized"
+// @Failure      500     {object}  ErrorResponse  "Internal server error"
+// @Router       /api/links/by-target [get]
+func (h *HTTPHandler) FindLinksByTarget(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+	return findLinksByTarget(c, h.Service, &userID)
+}
+
+// RewriteTargets godoc
+// @Summary      Rewrite link targets
+// @Description  Replaces the "from" URL prefix with "to" on every one of the authenticated user's links pointing at or under "from", in one transaction, and invalidates their cached redirects. Rewritten targets are stored in normalized form. With dry_run the changes are returned without being applied.
+// @Tags         links
+// @Accept       json
+// @Produce      json
+// @Param        request  body      RewriteTargetsRequest  true  "Prefix rewrite"
+// @Success      200      {object}  RewriteTargetsResponse  "Rewritten links"
+// @Failure      400      {object}  ErrorResponse  "Invalid URL"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Failure      422      {object}  ErrorResponse  "Too many matching links"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/links/rewrite-targets [post]
+func (h *HTTPHandler) RewriteTargets(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	var req RewriteTargetsRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input"})
+	}
+
+	changes, err := h.Service.RewriteTargets(c.Context(), userID, domain.TargetRewrite{
+		From:   strings.TrimSpace(req.From),
+		To:     strings.TrimSpace(req.To),
+		DryRun: req.DryRun,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidTargetURL) || errors.Is(err, domain.ErrTargetBlocked) {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+		}
+		if errors.Is(err, domain.ErrTooManyMatches) {
+			return c.Status(422).JSON(ErrorResponse{Error: "Too many links match this prefix; use a more specific one"})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while rewriting link targets"})
+	}
+
+	return c.JSON(RewriteTargetsResponse{DryRun: req.DryRun, Updated: len(changes), Changes: changes})
+}
+
+// UpdateLink godoc
+// @Summary      Update a link
+// @Description  Updates the title, description, private notes and link-preview (Open Graph) overrides of one of the authenticated user's links. Omitted fields are left unchanged.
+// @Tags         links
+// @Accept       json
+// @Produce      json
+// @Param        slug     path      string             true   "Shortened link slug"  example(abc123)
+// @Param        domain   query     string             false  "Custom domain the link belongs to"  example(go.acme.com)
+// @Param        request  body      UpdateLinkRequest  true   "Fields to update"
+// @Success      200      {object}  domain.Link    "Updated link"
+// @Failure      400      {object}  ErrorResponse  "Invalid input or metadata"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Failure      404      {object}  ErrorResponse  "Link not found"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/links/{slug} [patch]
+func (h *HTTPHandler) UpdateLink(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	linkDomain, err := h.queryDomain(c)
+	if err != nil {
+		return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+	}
+
+	var req UpdateLinkRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input"})
+	}
+	for _, field := range []*string{req.Title, req.Description, req.OGTitle, req.OGDescription, req.OGImageURL} {
+		if field != nil {
+			*field = strings.TrimSpace(*field)
+		}
+	}
+
+	link, err := h.Service.UpdateLink(c.Context(), userID, linkDomain, slugParam(c), domain.LinkUpdate{
+		Title:         req.Title,
+		Description:   req.Description,
+		Notes:         req.Notes,
+		OGTitle:       req.OGTitle,
+		OGDescription: req.OGDescription,
+		OGImageURL:    req.OGImageURL,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrLinkNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+		}
+		if errors.Is(err, domain.ErrMetadataTooLong) || errors.Is(err, domain.ErrInvalidImageURL) {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while updating the link"})
+	}
+	return c.JSON(link)
+}
+
+// SetLinkTags godoc
+// @Summary      Set a link's tags
+// @Description  Replaces the tags of one of the authenticated user's links. Tags are matched by name, ignoring case, and created when missing. An empty list removes all tags.
+// @Tags         links
+// @Accept       json
+// @Produce      json
+// @Param        slug     path      string              true   "Shortened link slug"  example(abc123)
+// @Param        domain   query     string              false  "Custom domain the link belongs to"  example(go.acme.com)
+// @Param        request  body      SetLinkTagsRequest  true   "Tag names"
+// @Success      200      {object}  domain.Link    "Updated link"
+// @Failure      400      {object}  ErrorResponse  "Invalid tag name"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Failure      404      {object}  ErrorResponse  "Link not found"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/links/{slug}/tags [put]
+func (h *HTTPHandler) SetLinkTags(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	linkDomain, err := h.queryDomain(c)
+	if err != nil {
+		return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+	}
+
+	var req SetLinkTagsRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input"})
+	}
+
+	link, err := h.Service.SetLinkTags(c.Context(), userID, linkDomain, slugParam(c), req.Tags)
+	if err != nil {
+		if errors.Is(err, domain.ErrLinkNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+		}
+		if errors.Is(err, domain.ErrInvalidTagName) {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while updating the link's tags"})
+	}
+	return c.JSON(link)
+}
+
+// SetLinkRules godoc
+// @Summary      Set a link's routing rules
+// @Description  Replaces the ordered routing rules of one of the authenticated user's links. Each rule matches on os (ios, android, windows, macos, linux, chromeos) and/or device (mobile, tablet, desktop), detected from the visitor's User-Agent; the first matching rule's target_url is used, and visitors matching no rule get the link's target. Clicks record the index of the rule that matched. An empty list removes all rules.
+// @Tags         links
+// @Accept       json
+// @Produce      json
+// @Param        slug     path      string               true   "Shortened link slug"  example(abc123)
+// @Param        domain   query     string               false  "Custom domain the link belongs to"  example(go.acme.com)
+// @Param        request  body      SetLinkRulesRequest  true   "Routing rules"
+// @Success      200      {object}  domain.Link    "Updated link"
+// @Failure      400      {object}  ErrorResponse  "Invalid routing rule"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Failure      404      {object}  ErrorResponse  "Link not found"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/links/{slug}/rules [put]
+func (h *HTTPHandler) SetLinkRules(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	linkDomain, err := h.queryDomain(c)
+	if err != nil {
+		return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+	}
+
+	var req SetLinkRulesRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input"})
+	}
+
+	link, err := h.Service.SetLinkRules(c.Context(), userID, linkDomain, slugParam(c), req.Rules)
+	if err != nil {
+		if errors.Is(err, domain.ErrLinkNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+		}
+		if errors.Is(err, domain.ErrInvalidRoutingRule) || errors.Is(err, domain.ErrTargetBlocked) {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while updating the link's rules"})
+	}
+	return c.JSON(link)
+}
+
+// SetLinkPathForwarding godoc
+// @Summary      Turn path forwarding on or off
+// @Description  Makes one of the authenticated user's links a prefix link: /{slug}/getting-started then redirects to the link's target with /getting-started appended. When several prefix links match a path, the longest wins, and a nested slug matching the whole path wins over any prefix.
+// @Tags         links
+// @Accept       json
+// @Produce      json
+// @Param        slug     path      string                        true   "Shortened link slug"  example(guide)
+// @Param        domain   query     string                        false  "Custom domain the link belongs to"  example(go.acme.com)
+// @Param        request  body      SetLinkPathForwardingRequest  true   "Path forwarding"
+// @Success      200      {object}  domain.Link    "Updated link"
+// @Failure      400      {object}  ErrorResponse  "Invalid input"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Failure      404      {object}  ErrorResponse  "Link not found"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/links/{slug}/path-forwarding [put]
+func (h *HTTPHandler) SetLinkPathForwarding(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	linkDomain, err := h.queryDomain(c)
+	if err != nil {
+		return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+	}
+
+	var req SetLinkPathForwardingRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input"})
+	}
+
+	link, err := h.Service.SetLinkPathForwarding(c.Context(), userID, linkDomain, slugParam(c), req.Enabled)
+	if err != nil {
+		if errors.Is(err, domain.ErrLinkNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while updating the link's path forwarding"})
+	}
+	return c.JSON(link)
+}
+
+// SetLinkMaxUses godoc
+// @Summary      Limit how many times a link can be opened
+// @Description  Makes one of the authenticated user's links stop resolving after max_uses opens (1 for view-once), or removes the limit with 0. Setting a limit resets the uses left to max_uses. Visitors of a limited link confirm on an interstitial page before a use is spent, so link unfurlers and mail scanners can't use it up.
+// @Tags         links
+// @Accept       json
+// @Produce      json
+// @Param        slug     path      string                 true   "Shortened link slug"  example(abc123)
+// @Param        domain   query     string                 false  "Custom domain the link belongs to"  example(go.acme.com)
+// @Param        request  body      SetLinkMaxUsesRequest  true   "Use limit"
+// @Success      200      {object}  domain.Link    "Updated link"
+// @Failure      400      {object}  ErrorResponse  "Invalid use limit"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Failure      404      {object}  ErrorResponse  "Link not found"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/links/{slug}/max-uses [put]
+func (h *HTTPHandler) SetLinkMaxUses(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	linkDomain, err := h.queryDomain(c)
+	if err != nil {
+		return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+	}
+
+	var req SetLinkMaxUsesRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input"})
+	}
+
+	link, err := h.Service.SetLinkMaxUses(c.Context(), userID, linkDomain, slugParam(c), req.MaxUses)
+	if err != nil {
+		if errors.Is(err, domain.ErrLinkNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+		}
+		if errors.Is(err, domain.ErrInvalidMaxUses) {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while updating the link's use limit"})
+	}
+	return c.JSON(link)
+}
+
+// SetLinkInterstitial godoc
+// @Summary      Show an interstitial page before redirecting
+// @Description  Turns the interstitial page of one of the authenticated user's links on or off. Visitors then see the destination domain and a continue button instead of being redirected; with a countdown (1 to 30 seconds) the page continues by itself.
+// @Tags         links
+// @Accept       json
+// @Produce      json
+// @Param        slug     path      string               true   "Shortened link slug"  example(abc123)
+// @Param        domain   query     string               false  "Custom domain the link belongs to"  example(go.acme.com)
+// @Param        request  body      domain.Interstitial  true   "Interstitial settings"
+// @Success      200      {object}  domain.Link    "Updated link"
+// @Failure      400      {object}  ErrorResponse  "Invalid interstitial settings"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Failure      404      {object}  ErrorResponse  "Link not found"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/links/{slug}/interstitial [put]
+func (h *HTTPHandler) SetLinkInterstitial(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	linkDomain, err := h.queryDomain(c)
+	if err != nil {
+		return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+	}
+
+	var req domain.Interstitial
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input"})
+	}
+
+	link, err := h.Service.SetLinkInterstitial(c.Context(), userID, linkDomain, slugParam(c), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrLinkNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+		}
+		if errors.Is(err, domain.ErrInvalidInterstitial) {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while updating the link's interstitial"})
+	}
+	return c.JSON(link)
+}
+
+// SetLinkSchedule godoc
+// @Summary      Set when a link is live
+// @Description  Replaces the schedule of one of the authenticated user's links. Before active_from visitors are sent to not_live_url, and from active_until on to ended_url; without those URLs the link answers 404 before its window and 410 after it. Either bound may be omitted, and an empty body makes the link live at all times. Links with a schedule always redirect with 302.
+// @Tags         links
+// @Accept       json
+// @Produce      json
+// @Param        slug     path      string               true   "Shortened link slug"  example(abc123)
+// @Param        domain   query     string               false  "Custom domain the link belongs to"  example(go.acme.com)
+// @Param        request  body      domain.LinkSchedule  true   "Schedule"
+// @Success      200      {object}  domain.Link    "Updated link"
+// @Failure      400      {object}  ErrorResponse  "Invalid schedule"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Failure      404      {object}  ErrorResponse  "Link not found"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/links/{slug}/schedule [put]
+func (h *HTTPHandler) SetLinkSchedule(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	linkDomain, err := h.queryDomain(c)
+	if err != nil {
+		return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+	}
+
+	var req domain.LinkSchedule
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input"})
+	}
+
+	link, err := h.Service.SetLinkSchedule(c.Context(), userID, linkDomain, slugParam(c), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrLinkNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+		}
+		if errors.Is(err, domain.ErrInvalidSchedule) || errors.Is(err, domain.ErrTargetBlocked) {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while updating the link's schedule"})
+	}
+	return c.JSON(link)
+}
+
+// SetLinkQueryOptions godoc
+// @Summary      Set a link's UTM parameters and query forwarding
+// @Description  Replaces the query options of one of the authenticated user's links. UTM values are set on the target at redirect time, replacing the target's own utm_ parameters. With forward_query, the short URL's query string is merged into the target; when a parameter already exists, policy decides: target keeps the existing value (default), incoming replaces it, append keeps both. The options apply to rule and variant targets too.
+// @Tags         links
+// @Accept       json
+// @Produce      json
+// @Param        slug     path      string               true   "Shortened link slug"  example(abc123)
+// @Param        domain   query     string               false  "Custom domain the link belongs to"  example(go.acme.com)
+// @Param        request  body      domain.QueryOptions  true   "Query options"
+// @Success      200      {object}  domain.Link    "Updated link"
+// @Failure      400      {object}  ErrorResponse  "Invalid query options"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Failure      404      {object}  ErrorResponse  "Link not found"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/links/{slug}/query-options [put]
+func (h *HTTPHandler) SetLinkQueryOptions(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	linkDomain, err := h.queryDomain(c)
+	if err != nil {
+		return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+	}
+
+	var req domain.QueryOptions
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input"})
+	}
+
+	link, err := h.Service.SetLinkQueryOptions(c.Context(), userID, linkDomain, slugParam(c), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrLinkNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+		}
+		if errors.Is(err, domain.ErrInvalidQueryOptions) {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while updating the link's query options"})
+	}
+	return c.JSON(link)
+}
+
+// SetLinkFolder godoc
+// @Summary      Move a link to a folder
+// @Description  Moves one of the authenticated user's links into a folder, or out of its folder when folder_id is null.
+// @Tags         links
+// @Accept       json
+// @Produce      json
+// @Param        slug     path      string                true   "Shortened link slug"  example(abc123)
+// @Param        domain   query     string                false  "Custom domain the link belongs to"  example(go.acme.com)
+// @Param        request  body      SetLinkFolderRequest  true   "Folder"
+// @Success      200      {object}  domain.Link    "Updated link"
+// @Failure      400      {object}  ErrorResponse  "Invalid input"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Failure      404      {object}  ErrorResponse  "Link or folder not found"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/links/{slug}/folder [put]
+func (h *HTTPHandler) SetLinkFolder(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	linkDomain, err := h.queryDomain(c)
+	if err != nil {
+		return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+	}
+
+	var req SetLinkFolderRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input"})
+	}
+
+	link, err := h.Service.SetLinkFolder(c.Context(), userID, linkDomain, slugParam(c), req.FolderID)
+	if err != nil {
+		if errors.Is(err, domain.ErrLinkNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+		}
+		if errors.Is(err, domain.ErrFolderNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Folder not found"})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while moving the link"})
+	}
+	return c.JSON(link)
+}
+
+// queryInt parses an optional non-negative integer query parameter, returning
+// zero when it is absent.
+func queryInt(c fiber.Ctx, name string) (int, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		return 0, errors.New("invalid " + name)
+	}
+	return n, nil
+}
+
+// queryDomain reads the optional ?domain= parameter used by owner endpoints
+// to address links on a custom domain.
+func (h *HTTPHandler) queryDomain(c fiber.Ctx) (string, error) {
+	raw := c.Query("domain")
+	if raw == "" {
+		return "", nil
+	}
+	return h.linkDomain(raw)
+}
@@ -0,0 +1,455 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+	"github.com/gofiber/fiber/v3"
+)
+
+type ReportLinkRequest struct {
+	Reason  string `json:"reason" example:"PHISHING"`
+	Details string `json:"details,omitempty" example:"Asks for my bank password"`
+	// Email lets moderators follow up with the reporter.
+	Email string `json:"email,omitempty" example:"reporter@example.com"`
+}
+
+type ReportLinkResponse struct {
+	ID     string              `json:"id" example:"0f8c3a52-2b1e-4d7a-9c5f-6e4b3a2d1c0b"`
+	Status domain.ReportStatus `json:"status" example:"OPEN"`
+}
+
+type ReviewReportRequest struct {
+	// Action is "dismiss" to close the report or "block" to block the link.
+	Action string `json:"action" example:"block"`
+	Note   string `json:"note,omitempty" example:"Confirmed phishing page"`
+}
+
+type SetLinkStatusRequest struct {
+	// Status is ACTIVE, PAUSED or BLOCKED.
+	Status string `json:"status" example:"BLOCKED"`
+	Note   string `json:"note,omitempty" example:"Malware download"`
+}
+
+type DeleteLinkRequest struct {
+	Note string `json:"note,omitempty" example:"Reported by the target's owner"`
+}
+
+type SetLinkFlaggedRequest struct {
+	Flagged bool   `json:"flagged" example:"true"`
+	Note    string `json:"note,omitempty" example:"Lookalike domain"`
+}
+
+type BanUserRequest struct {
+	Reason string `json:"reason,omitempty" example:"Repeated phishing links"`
+	// BlockLinks also blocks every link the user owns.
+	BlockLinks bool `json:"block_links" example:"true"`
+}
+
+type UnbanUserRequest struct {
+	Note string `json:"note,omitempty" example:"Appeal accepted"`
+}
+
+// ModerationHandler serves abuse reports and the moderator routes under
+// /api/admin. Links addresses the ?domain= parameter the same way the link
+// handlers do.
+type ModerationHandler struct {
+	Service ports.ModerationService
+	Links   *HTTPHandler
+}
+
+func NewModerationHandler(service ports.ModerationService, links *HTTPHandler) *ModerationHandler {
+	return &ModerationHandler{Service: service, Links: links}
+}
+
+// ReportLink godoc
+// @Summary      Report an abusive link
+// @Description  Files an abuse report on a link for moderators to review. Public endpoint, no authentication required. Requests are rate limited per IP address; over the limit the response is 429 with a Retry-After header. Reporting the same link again from the same address within 24 hours returns 409.
+// @Tags         moderation
+// @Accept       json
+// @Produce      json
+// @Param        slug     path      string             true   "Shortened link slug"  example(abc123)
+// @Param        domain   query     string             false  "Custom domain the link belongs to"  example(go.acme.com)
+// @Param        request  body      ReportLinkRequest  true   "Report"
+// @Success      202      {object}  ReportLinkResponse  "Report received"
+// @Failure      400      {object}  ErrorResponse  "Invalid reason, details or email"
+// @Failure      404      {object}  ErrorResponse  "Link not found"
+// @Failure      409      {object}  ErrorResponse  "Link already reported from this address"
+// @Failure      429      {object}  ErrorResponse  "Too many reports"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/report/{slug} [post]
+func (h *ModerationHandler) ReportLink(c fiber.Ctx) error {
+	linkDomain, err := h.Links.queryDomain(c)
+	if err != nil {
+		return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+	}
+
+	var req ReportLinkRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input"})
+	}
+
+	report, err := h.Service.ReportLink(c.Context(), linkDomain, slugParam(c), domain.ReportInput{
+		Reason:     domain.ReportReason(req.Reason),
+		Details:    req.Details,
+		Email:      req.Email,
+		ReporterIP: c.IP(),
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidReport) {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+		}
+		if errors.Is(err, domain.ErrLinkNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+		}
+		if errors.Is(err, domain.ErrAlreadyReported) {
+			return c.Status(409).JSON(ErrorResponse{Error: "You have already reported this link"})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while filing the report"})
+	}
+	return c.Status(202).JSON(ReportLinkResponse{ID: report.ID, Status: report.Status})
+}
+
+// ListReports godoc
+// @Summary      List abuse reports (admin)
+// @Description  Lists abuse reports by status. Open reports form the moderation queue and come oldest first; dismissed and actioned reports come newest first. Requires an admin account.
+// @Tags         admin
+// @Produce      json
+// @Param        status   query     string  false  "Report status (default OPEN)"  Enums(OPEN, DISMISSED, ACTIONED)
+// @Param        link_id  query     string  false  "Only reports on this link"
+// @Param        limit    query     int     false  "Page size (default 50, max 200)"
+// @Param        offset   query     int     false  "Number of reports to skip"
+// @Success      200      {object}  domain.ReportPage  "Reports"
+// @Failure      400      {object}  ErrorResponse  "Invalid status or pagination"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Failure      403      {object}  ErrorResponse  "Admin access required"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/admin/reports [get]
+func (h *ModerationHandler) ListReports(c fiber.Ctx) error {
+	filter := domain.ReportFilter{
+		Status: domain.ReportStatus(strings.ToUpper(c.Query("status"))),
+		LinkID: c.Query("link_id"),
+	}
+	switch filter.Status {
+	case "", domain.ReportOpen, domain.ReportDismissed, domain.ReportActioned:
+	default:
+		return c.Status(400).JSON(ErrorResponse{Error: "status must be 'OPEN', 'DISMISSED' or 'ACTIONED'"})
+	}
+
+	var err error
+	if filter.Limit, err = queryInt(c, "limit"); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid limit"})
+	}
+	if filter.Offset, err = queryInt(c, "offset"); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid offset"})
+	}
+
+	page, err := h.Service.ListReports(c.Context(), filter)
+	if err != nil {
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while listing reports"})
+	}
+	return c.JSON(page)
+}
+
+// ReviewReport godoc
+// @Summary      Review an abuse report (admin)
+// @Description  Dismisses an open report, or blocks the reported link, which also closes every other open report on it. Blocked links stop redirecting and their owners cannot unblock them. Requires an admin account.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string               true  "Report ID"
+// @Param        request  body      ReviewReportRequest  true  "Decision"
+// @Success      200      {object}  domain.AbuseReport  "Reviewed report"
+// @Failure      400      {object}  ErrorResponse  "Invalid action or note"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Failure      403      {object}  ErrorResponse  "Admin access required"
+// @Failure      404      {object}  ErrorResponse  "Report not found"
+// @Failure      409      {object}  ErrorResponse  "Report already reviewed"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/admin/reports/{id}/review [post]
+func (h *ModerationHandler) ReviewReport(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	var req ReviewReportRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input"})
+	}
+
+	report, err := h.Service.ReviewReport(c.Context(), userID, c.Params("id"), domain.ReportReview{
+		Action: domain.ReviewAction(req.Action),
+		Note:   req.Note,
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidReview) {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+		}
+		if errors.Is(err, domain.ErrReportNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Report not found"})
+		}
+		if errors.Is(err, domain.ErrReportClosed) {
+			return c.Status(409).JSON(ErrorResponse{Error: "This report has already been reviewed"})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while reviewing the report"})
+	}
+	return c.JSON(report)
+}
+
+// SetLinkStatus godoc
+// @Summary      Force the status of any user's link (admin)
+// @Description  Sets a link's status to ACTIVE, PAUSED or BLOCKED. Blocking a link also closes its open reports, and unlike PAUSED, owners cannot lift a block. Requires an admin account.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        slug     path      string                true   "Shortened link slug"  example(abc123)
+// @Param        domain   query     string                false  "Custom domain the link belongs to"  example(go.acme.com)
+// @Param        request  body      SetLinkStatusRequest  true   "Status"
+// @Success      200      {object}  domain.Link    "Updated link"
+// @Failure      400      {object}  ErrorResponse  "Invalid status or note"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Failure      403      {object}  ErrorResponse  "Admin access required"
+// @Failure      404      {object}  ErrorResponse  "Link not found"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/admin/links/{slug}/status [put]
+func (h *ModerationHandler) SetLinkStatus(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	linkDomain, err := h.Links.queryDomain(c)
+	if err != nil {
+		return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+	}
+
+	var req SetLinkStatusRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input"})
+	}
+
+	status := domain.LinkStatus(strings.ToUpper(strings.TrimSpace(req.Status)))
+	link, err := h.Service.SetLinkStatus(c.Context(), userID, linkDomain, slugParam(c), status, req.Note)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidLinkStatus) || errors.Is(err, domain.ErrInvalidReview) {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+		}
+		if errors.Is(err, domain.ErrLinkNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while updating the link's status"})
+	}
+	return c.JSON(link)
+}
+
+// DeleteLink godoc
+// @Summary      Delete any user's link (admin)
+// @Description  Deletes a link along with its abuse reports, and frees its slug. The deletion is recorded in the moderation audit log. Requires an admin account.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        slug     path  string             true   "Shortened link slug"  example(abc123)
+// @Param        domain   query string             false  "Custom domain the link belongs to"  example(go.acme.com)
+// @Param        request  body  DeleteLinkRequest  false  "Note for the audit log"
+// @Success      204      "Link deleted"
+// @Failure      400      {object}  ErrorResponse  "Invalid note"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Failure      403      {object}  ErrorResponse  "Admin access required"
+// @Failure      404      {object}  ErrorResponse  "Link not found"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/admin/links/{slug} [delete]
+func (h *ModerationHandler) DeleteLink(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	linkDomain, err := h.Links.queryDomain(c)
+	if err != nil {
+		return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+	}
+
+	var req DeleteLinkRequest
+	if len(c.Body()) > 0 {
+		if err := c.Bind().Body(&req); err != nil {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid input"})
+		}
+	}
+
+	if err := h.Service.DeleteLink(c.Context(), userID, linkDomain, slugParam(c), req.Note); err != nil {
+		if errors.Is(err, domain.ErrInvalidReview) {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+		}
+		if errors.Is(err, domain.ErrLinkNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while deleting the link"})
+	}
+	return c.SendStatus(204)
+}
+
+// SetLinkFlagged godoc
+// @Summary      Flag any user's link as suspicious (admin)
+// @Description  Flags or unflags a link as suspicious. Flagged links always show a warning interstitial, without a countdown, whatever their owner chose. Requires an admin account.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        slug     path      string                 true   "Shortened link slug"  example(abc123)
+// @Param        domain   query     string                 false  "Custom domain the link belongs to"  example(go.acme.com)
+// @Param        request  body      SetLinkFlaggedRequest  true   "Flag"
+// @Success      200      {object}  domain.Link    "Updated link"
+// @Failure      400      {object}  ErrorResponse  "Invalid input"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Failure      403      {object}  ErrorResponse  "Admin access required"
+// @Failure      404      {object}  ErrorResponse  "Link not found"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/admin/links/{slug}/flag [put]
+func (h *ModerationHandler) SetLinkFlagged(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	linkDomain, err := h.Links.queryDomain(c)
+	if err != nil {
+		return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+	}
+
+	var req SetLinkFlaggedRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input"})
+	}
+
+	link, err := h.Service.SetLinkFlagged(c.Context(), userID, linkDomain, slugParam(c), req.Flagged, req.Note)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidReview) {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+		}
+		if errors.Is(err, domain.ErrLinkNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while flagging the link"})
+	}
+	return c.JSON(link)
+}
+
+// BanUser godoc
+// @Summary      Ban a user (admin)
+// @Description  Bans a user, after which every authenticated request they make is refused with 403. With block_links every link they own is blocked too; unbanning does not unblock them. Banning a banned user replaces the reason. Requires an admin account.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string          true  "User ID"
+// @Param        request  body      BanUserRequest  true  "Ban"
+// @Success      200      {object}  domain.UserBan  "Ban"
+// @Failure      400      {object}  ErrorResponse  "Invalid input"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Failure      403      {object}  ErrorResponse  "Admin access required"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/admin/users/{id}/ban [put]
+func (h *ModerationHandler) BanUser(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	target := c.Params("id")
+	if target == userID {
+		return c.Status(400).JSON(ErrorResponse{Error: "You cannot ban yourself"})
+	}
+
+	var req BanUserRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input"})
+	}
+
+	ban, err := h.Service.BanUser(c.Context(), userID, target, req.Reason, req.BlockLinks)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidReview) {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while banning the user"})
+	}
+	return c.JSON(ban)
+}
+
+// UnbanUser godoc
+// @Summary      Lift a user's ban (admin)
+// @Description  Lets a banned user use the API again. Links blocked with the ban stay blocked. Requires an admin account.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path  string            true   "User ID"
+// @Param        request  body  UnbanUserRequest  false  "Note for the audit log"
+// @Success      204      "Ban lifted"
+// @Failure      400      {object}  ErrorResponse  "Invalid input"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Failure      403      {object}  ErrorResponse  "Admin access required"
+// @Failure      404      {object}  ErrorResponse  "User is not banned"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/admin/users/{id}/ban [delete]
+func (h *ModerationHandler) UnbanUser(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	var req UnbanUserRequest
+	if len(c.Body()) > 0 {
+		if err := c.Bind().Body(&req); err != nil {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid input"})
+		}
+	}
+
+	if err := h.Service.UnbanUser(c.Context(), userID, c.Params("id"), req.Note); err != nil {
+		if errors.Is(err, domain.ErrInvalidReview) {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+		}
+		if errors.Is(err, domain.ErrUserNotBanned) {
+			return c.Status(404).JSON(ErrorResponse{Error: "User is not banned"})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while lifting the ban"})
+	}
+	return c.SendStatus(204)
+}
+
+// ListModerationActions godoc
+// @Summary      List moderator actions (admin)
+// @Description  Returns the moderation audit log, newest first: dismissed reports, link status changes and deletions, flagged links, and bans. Requires an admin account.
+// @Tags         admin
+// @Produce      json
+// @Param        moderator  query     string  false  "Only actions by this moderator"
+// @Param        link_id    query     string  false  "Only actions on this link"
+// @Param        user_id    query     string  false  "Only actions on this user"
+// @Param        limit      query     int     false  "Page size (default 50, max 200)"
+// @Param        offset     query     int     false  "Number of entries to skip"
+// @Success      200        {object}  domain.ModerationActionPage  "Audit log entries"
+// @Failure      400        {object}  ErrorResponse  "Invalid pagination"
+// @Failure      401        {object}  ErrorResponse  "Unauthorized"
+// @Failure      403        {object}  ErrorResponse  "Admin access required"
+// @Failure      500        {object}  ErrorResponse  "Internal server error"
+// @Router       /api/admin/moderation/actions [get]
+func (h *ModerationHandler) ListModerationActions(c fiber.Ctx) error {
+	filter := domain.ModerationActionFilter{
+		ModeratorID: c.Query("moderator"),
+		LinkID:      c.Query("link_id"),
+		UserID:      c.Query("user_id"),
+	}
+
+	var err error
+	if filter.Limit, err = queryInt(c, "limit"); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid limit"})
+	}
+	if filter.Offset, err = queryInt(c, "offset"); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid offset"})
+	}
+
+	page, err := h.Service.ListActions(c.Context(), filter)
+	if err != nil {
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while listing moderator actions"})
+	}
+	return c.JSON(page)
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/gofiber/fiber/v3"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+var previewTemplate = template.Must(template.ParseFS(templateFS, "templates/preview.html"))
+
+// sendPreview answers a link-preview crawler with a page carrying the link's
+// Open Graph and Twitter Card tags. The page does not redirect: the crawler
+// check trusts the User-Agent, so the page must not be a way around the
+// redirect path.
+func (h *HTTPHandler) sendPreview(c fiber.Ctx, link domain.Link) error {
+	var buf bytes.Buffer
+	if err := previewTemplate.Execute(&buf, link.Preview(h.shortURL(link))); err != nil {
+		return c.Status(500).SendString("An error occurred while rendering the preview")
+	}
+
+	// Only previews that would be the same for every visitor at any time may
+	// be cached by shared caches.
+	if routesPerVisitor(link) {
+		c.Set("Cache-Control", "private, no-store")
+	} else {
+		c.Set("Cache-Control", "public, max-age=300")
+	}
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.Send(buf.Bytes())
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/gofiber/fiber/v3"
+)
+
+// LinkQRCode godoc
+// @Summary      Get a link's QR code
+// @Description  Renders a QR code for the link's full short URL as PNG or SVG. Responses carry an ETag; send it back in If-None-Match to get a 304 when the image has not changed. A logo forces error correction H.
+// @Tags         links
+// @Produce      png
+// @Produce      image/svg+xml
+// @Param        slug    path      string  true   "Shortened link slug"  example(abc123)
+// @Param        domain  query     string  false  "Custom domain the link belongs to"  example(go.acme.com)
+// @Param        format  query     string  false  "Image format"  Enums(png, svg)
+// @Param        size    query     int     false  "Width and height in pixels (64-2048, default 512)"
+// @Param        margin  query     int     false  "Quiet zone in modules (0-16, default 4)"
+// @Param        ecc     query     string  false  "Error correction level"  Enums(L, M, Q, H)
+// @Param        fg      query     string  false  "Foreground color"  example(#000000)
+// @Param        bg      query     string  false  "Background color"  example(#ffffff)
+// @Param        logo    query     string  false  "URL of a PNG, JPEG or GIF logo drawn in the center"
+// @Success      200     {file}    binary  "QR code image"
+// @Success      304     {string}  string  "Not modified"
+// @Failure      400     {object}  ErrorResponse  "Invalid options or logo"
+// @Failure      401     {object}  ErrorResponse  "Unauthorized"
+// @Failure      404     {object}  ErrorResponse  "Link not found"
+// @Failure      500     {object}  ErrorResponse  "Internal server error"
+// @Router       /api/links/{slug}/qr [get]
+func (h *HTTPHandler) LinkQRCode(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	linkDomain, err := h.queryDomain(c)
+	if err != nil {
+		return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+	}
+
+	opts, err := parseQROptions(c)
+	if err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+	}
+
+	link, err := h.Service.GetLink(c.Context(), userID, linkDomain, slugParam(c))
+	if err != nil {
+		if errors.Is(err, domain.ErrLinkNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while loading the link"})
+	}
+
+	content := h.shortURL(link)
+	etag := `"` + opts.Fingerprint(content) + `"`
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderCacheControl, "private, max-age=86400")
+	if c.Get(fiber.HeaderIfNoneMatch) == etag {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	img, err := h.QR.RenderQRCode(c.Context(), content, opts)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidQROptions) || errors.Is(err, domain.ErrInvalidLogo) {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while rendering the QR code"})
+	}
+
+	c.Set(fiber.HeaderContentType, opts.Format.ContentType())
+	return c.Send(img)
+}
+
+func parseQROptions(c fiber.Ctx) (domain.QROptions, error) {
+	opts := domain.DefaultQROptions()
+	if format := c.Query("format"); format != "" {
+		opts.Format = domain.QRFormat(strings.ToLower(format))
+	}
+	if ecc := c.Query("ecc"); ecc != "" {
+		opts.ErrorCorrection = domain.QRErrorCorrection(strings.ToUpper(ecc))
+	}
+	if c.Query("size") != "" {
+		size, err := queryInt(c, "size")
+		if err != nil {
+			return opts, err
+		}
+		opts.Size = size
+	}
+	if c.Query("margin") != "" {
+		margin, err := queryInt(c, "margin")
+		if err != nil {
+			return opts, err
+		}
+		opts.Margin = margin
+	}
+
+	var err error
+	if fg := c.Query("fg"); fg != "" {
+		if opts.Foreground, err = domain.ParseHexColor(fg); err != nil {
+			return opts, err
+		}
+	}
+	if bg := c.Query("bg"); bg != "" {
+		if opts.Background, err = domain.ParseHexColor(bg); err != nil {
+			return opts, err
+		}
+	}
+
+	if logo := c.Query("logo"); logo != "" {
+		if u, err := url.Parse(logo); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return opts, domain.ErrInvalidLogo
+		}
+		opts.LogoURL = strings.Clone(logo)
+		opts.ErrorCorrection = domain.QRErrorCorrectionH
+	}
+	return opts, opts.Validate()
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+	"github.com/gofiber/fiber/v3"
+)
+
+type TagHandler struct {
+	Service ports.TagService
+}
+
+func NewTagHandler(service ports.TagService) *TagHandler {
+	return &TagHandler{Service: service}
+}
+
+type TagRequest struct {
+	Name string `json:"name" example:"marketing" binding:"required"`
+}
+
+// CreateTag godoc
+// @Summary      Create a tag
+// @Description  Creates a tag for the authenticated user. Tag names are unique per user, ignoring case.
+// @Tags         tags
+// @Accept       json
+// @Produce      json
+// @Param        request  body      TagRequest  true  "Tag data"
+// @Success      200      {object}  domain.Tag     "Tag created"
+// @Failure      400      {object}  ErrorResponse  "Invalid tag name"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Failure      409      {object}  ErrorResponse  "Tag already exists"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/tags [post]
+func (h *TagHandler) CreateTag(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	var req TagRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input"})
+	}
+
+	tag, err := h.Service.CreateTag(c.Context(), userID, req.Name)
+	if err != nil {
+		return h.tagError(c, err, "An error occurred while creating the tag")
+	}
+	return c.JSON(tag)
+}
+
+// RenameTag godoc
+// @Summary      Rename a tag
+// @Description  Renames one of the authenticated user's tags. Links keep the tag.
+// @Tags         tags
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string      true  "Tag ID"
+// @Param        request  body      TagRequest  true  "New name"
+// @Success      200      {object}  domain.Tag     "Tag renamed"
+// @Failure      400      {object}  ErrorResponse  "Invalid tag name"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Failure      404      {object}  ErrorResponse  "Tag not found"
+// @Failure      409      {object}  ErrorResponse  "Another tag has this name"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/tags/{id} [patch]
+func (h *TagHandler) RenameTag(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	var req TagRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input"})
+	}
+
+	tag, err := h.Service.RenameTag(c.Context(), userID, c.Params("id"), req.Name)
+	if err != nil {
+		return h.tagError(c, err, "An error occurred while renaming the tag")
+	}
+	return c.JSON(tag)
+}
+
+// ListTags godoc
+// @Summary      List tags
+// @Description  Lists the authenticated user's tags with the number of tagged links and their total clicks.
+// @Tags         tags
+// @Produce      json
+// @Success      200  {array}   domain.TagStats  "Tags"
+// @Failure      401  {object}  ErrorResponse  "Unauthorized"
+// @Failure      500  {object}  ErrorResponse  "Internal server error"
+// @Router       /api/tags [get]
+func (h *TagHandler) ListTags(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	tags, err := h.Service.ListTags(c.Context(), userID)
+	if err != nil {
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while listing tags"})
+	}
+	return c.JSON(tags)
+}
+
+func (h *TagHandler) tagError(c fiber.Ctx, err error, fallback string) error {
+	if errors.Is(err, domain.ErrInvalidTagName) {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+	}
+	if errors.Is(err, domain.ErrTagNotFound) {
+		return c.Status(404).JSON(ErrorResponse{Error: "Tag not found"})
+	}
+	if isDuplicateError(err) {
+		return c.Status(409).JSON(ErrorResponse{Error: "A tag with this name already exists"})
+	}
+	return c.Status(500).JSON(ErrorResponse{Error: fallback})
+}
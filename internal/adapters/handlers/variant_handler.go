@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/gofiber/fiber/v3"
+)
+
+type SetLinkVariantsRequest struct {
+	Variants []domain.Variant `json:"variants"`
+}
+
+// SetLinkVariants godoc
+// @Summary      Set a link's A/B split test
+// @Description  Replaces the weighted variants of one of the authenticated user's links. Visitors who match no routing rule are spread across the variants in proportion to their weights and kept on the same variant by a hash of the link, their IP and User-Agent. Variant IDs are chosen by the owner (letters, digits, '-' and '_'). Setting variants gives the link a postback_token for reporting conversions; an empty list ends the split test and revokes the token.
+// @Tags         links
+// @Accept       json
+// @Produce      json
+// @Param        slug     path      string                  true   "Shortened link slug"  example(abc123)
+// @Param        domain   query     string                  false  "Custom domain the link belongs to"  example(go.acme.com)
+// @Param        request  body      SetLinkVariantsRequest  true   "Variants"
+// @Success      200      {object}  domain.Link    "Updated link"
+// @Failure      400      {object}  ErrorResponse  "Invalid variant"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Failure      404      {object}  ErrorResponse  "Link not found"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/links/{slug}/variants [put]
+func (h *HTTPHandler) SetLinkVariants(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	linkDomain, err := h.queryDomain(c)
+	if err != nil {
+		return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+	}
+
+	var req SetLinkVariantsRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input"})
+	}
+
+	link, err := h.Variants.SetLinkVariants(c.Context(), userID, linkDomain, slugParam(c), req.Variants)
+	if err != nil {
+		if errors.Is(err, domain.ErrLinkNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+		}
+		if errors.Is(err, domain.ErrInvalidVariant) || errors.Is(err, domain.ErrTargetBlocked) {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while updating the link's variants"})
+	}
+	return c.JSON(link)
+}
+
+// VariantStats godoc
+// @Summary      Compare split test variants
+// @Description  Returns clicks, conversions and conversion rate for each current variant of one of the authenticated user's links.
+// @Tags         links
+// @Produce      json
+// @Param        slug    path      string  true   "Shortened link slug"  example(abc123)
+// @Param        domain  query     string  false  "Custom domain the link belongs to"  example(go.acme.com)
+// @Success      200     {array}   domain.VariantStats  "Per-variant statistics"
+// @Failure      401     {object}  ErrorResponse  "Unauthorized"
+// @Failure      404     {object}  ErrorResponse  "Link not found"
+// @Failure      500     {object}  ErrorResponse  "Internal server error"
+// @Router       /api/links/{slug}/variants/stats [get]
+func (h *HTTPHandler) VariantStats(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	linkDomain, err := h.queryDomain(c)
+	if err != nil {
+		return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+	}
+
+	stats, err := h.Variants.VariantStats(c.Context(), userID, linkDomain, slugParam(c))
+	if err != nil {
+		if errors.Is(err, domain.ErrLinkNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while loading variant statistics"})
+	}
+	return c.JSON(stats)
+}
+
+// RecordConversion godoc
+// @Summary      Report a split test conversion
+// @Description  Postback endpoint for the destination (or its ad/analytics platform) to report that a visitor sent to a variant converted. Authenticated by the link's postback_token instead of a session, so it can be called server to server. Accepts GET and POST.
+// @Tags         links
+// @Param        token    path      string  true  "Link postback token"
+// @Param        variant  query     string  true  "Variant ID"  example(b)
+// @Success      204      "Conversion recorded"
+// @Failure      400      {object}  ErrorResponse  "Unknown variant"
+// @Failure      404      {object}  ErrorResponse  "Invalid postback token"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/postback/{token} [post]
+func (h *HTTPHandler) RecordConversion(c fiber.Ctx) error {
+	variantID := c.Query("variant")
+	if variantID == "" {
+		return c.Status(400).JSON(ErrorResponse{Error: "variant is required"})
+	}
+
+	err := h.Variants.RecordConversion(c.Context(), c.Params("token"), variantID)
+	if err != nil {
+		if errors.Is(err, domain.ErrLinkNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Invalid postback token"})
+		}
+		if errors.Is(err, domain.ErrVariantNotFound) {
+			return c.Status(400).JSON(ErrorResponse{Error: "Unknown variant"})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while recording the conversion"})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
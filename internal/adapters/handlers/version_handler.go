@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/gofiber/fiber/v3"
+)
+
+// ListLinkVersions godoc
+// @Summary      List a link's previous versions
+// @Description  Returns the previous settings of one of the authenticated user's links, newest first. A version is kept every time the target, routing, schedule, use limit, interstitial or metadata of the link is replaced, whoever replaced it; replaced_at is when the link stopped using it. Status, folder and tags are not part of a version.
+// @Tags         links
+// @Produce      json
+// @Param        slug    path      string  true   "Shortened link slug"  example(abc123)
+// @Param        domain  query     string  false  "Custom domain the link belongs to"  example(go.acme.com)
+// @Success      200     {array}   domain.LinkVersion  "Versions"
+// @Failure      401     {object}  ErrorResponse  "Unauthorized"
+// @Failure      404     {object}  ErrorResponse  "Link not found"
+// @Failure      500     {object}  ErrorResponse  "Internal server error"
+// @Router       /api/links/{slug}/versions [get]
+func (h *HTTPHandler) ListLinkVersions(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	linkDomain, err := h.queryDomain(c)
+	if err != nil {
+		return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+	}
+
+	versions, err := h.Service.ListLinkVersions(c.Context(), userID, linkDomain, slugParam(c))
+	if err != nil {
+		if errors.Is(err, domain.ErrLinkNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while loading the link's versions"})
+	}
+	return c.JSON(versions)
+}
+
+// RestoreLinkVersion godoc
+// @Summary      Roll a link back to a previous version
+// @Description  Puts back the settings of a previous version of one of the authenticated user's links. The settings it replaces are kept as a new version, so the rollback can itself be undone. The link's cached entry is dropped, so visitors follow the restored settings at once. Targets are checked against the blocklists again.
+// @Tags         links
+// @Produce      json
+// @Param        slug    path      string  true   "Shortened link slug"  example(abc123)
+// @Param        n       path      int     true   "Version number"  example(3)
+// @Param        domain  query     string  false  "Custom domain the link belongs to"  example(go.acme.com)
+// @Success      200     {object}  domain.Link    "Restored link"
+// @Failure      400     {object}  ErrorResponse  "Invalid version number or blocked target"
+// @Failure      401     {object}  ErrorResponse  "Unauthorized"
+// @Failure      404     {object}  ErrorResponse  "Link or version not found"
+// @Failure      500     {object}  ErrorResponse  "Internal server error"
+// @Router       /api/links/{slug}/versions/{n}/restore [post]
+func (h *HTTPHandler) RestoreLinkVersion(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	linkDomain, err := h.queryDomain(c)
+	if err != nil {
+		return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+	}
+
+	number, err := strconv.Atoi(c.Params("n"))
+	if err != nil || number < 1 {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid version number"})
+	}
+
+	link, err := h.Service.RestoreLinkVersion(c.Context(), userID, linkDomain, slugParam(c), number)
+	if err != nil {
+		if errors.Is(err, domain.ErrLinkNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+		}
+		if errors.Is(err, domain.ErrVersionNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Version not found"})
+		}
+		if errors.Is(err, domain.ErrTargetBlocked) {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while restoring the link"})
+	}
+	return c.JSON(link)
+}
+
+// ScheduleLinkChange godoc
+// @Summary      Schedule a change to a link
+// @Description  Switches one of the authenticated user's links to target_url, or restores version, at run_at. Exactly one of target_url and version is required, and run_at must be within the next year; give it with an offset to mean a local time, e.g. "2026-03-06T09:00:00+01:00" for Friday 09:00 in Paris. A target switch only replaces the main target and keeps every other setting as it is when the change runs. The link's cached entry is dropped at the moment of the switch.
+// @Tags         links
+// @Accept       json
+// @Produce      json
+// @Param        slug     path      string                       true   "Shortened link slug"  example(abc123)
+// @Param        domain   query     string                       false  "Custom domain the link belongs to"  example(go.acme.com)
+// @Param        request  body      domain.ScheduledChangeInput  true   "Scheduled change"
+// @Success      200      {object}  domain.ScheduledChange  "Scheduled change"
+// @Failure      400      {object}  ErrorResponse  "Invalid scheduled change"
+// @Failure      401      {object}  ErrorResponse  "Unauthorized"
+// @Failure      404      {object}  ErrorResponse  "Link or version not found"
+// @Failure      500      {object}  ErrorResponse  "Internal server error"
+// @Router       /api/links/{slug}/scheduled-changes [post]
+func (h *HTTPHandler) ScheduleLinkChange(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	linkDomain, err := h.queryDomain(c)
+	if err != nil {
+		return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+	}
+
+	var req domain.ScheduledChangeInput
+	if err := c.Bind().Body(&req); err != nil {
+		return c.Status(400).JSON(ErrorResponse{Error: "Invalid input"})
+	}
+
+	change, err := h.Service.ScheduleLinkChange(c.Context(), userID, linkDomain, slugParam(c), req)
+	if err != nil {
+		if errors.Is(err, domain.ErrLinkNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+		}
+		if errors.Is(err, domain.ErrVersionNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Version not found"})
+		}
+		if errors.Is(err, domain.ErrInvalidScheduledChange) || errors.Is(err, domain.ErrTargetBlocked) {
+			return c.Status(400).JSON(ErrorResponse{Error: "Invalid input: " + err.Error()})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while scheduling the change"})
+	}
+	return c.JSON(change)
+}
+
+// ListScheduledChanges godoc
+// @Summary      List a link's scheduled changes
+// @Description  Returns the scheduled changes of one of the authenticated user's links in the order they run, including the ones that already ran, failed or were canceled.
+// @Tags         links
+// @Produce      json
+// @Param        slug    path      string  true   "Shortened link slug"  example(abc123)
+// @Param        domain  query     string  false  "Custom domain the link belongs to"  example(go.acme.com)
+// @Success      200     {array}   domain.ScheduledChange  "Scheduled changes"
+// @Failure      401     {object}  ErrorResponse  "Unauthorized"
+// @Failure      404     {object}  ErrorResponse  "Link not found"
+// @Failure      500     {object}  ErrorResponse  "Internal server error"
+// @Router       /api/links/{slug}/scheduled-changes [get]
+func (h *HTTPHandler) ListScheduledChanges(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	linkDomain, err := h.queryDomain(c)
+	if err != nil {
+		return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+	}
+
+	changes, err := h.Service.ListScheduledChanges(c.Context(), userID, linkDomain, slugParam(c))
+	if err != nil {
+		if errors.Is(err, domain.ErrLinkNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while loading scheduled changes"})
+	}
+	return c.JSON(changes)
+}
+
+// CancelScheduledChange godoc
+// @Summary      Cancel a scheduled change
+// @Description  Cancels a pending change of one of the authenticated user's links. Changes that already ran cannot be canceled; restore a version instead.
+// @Tags         links
+// @Produce      json
+// @Param        slug    path      string  true   "Shortened link slug"  example(abc123)
+// @Param        id      path      string  true   "Scheduled change ID"
+// @Param        domain  query     string  false  "Custom domain the link belongs to"  example(go.acme.com)
+// @Success      204     "Change canceled"
+// @Failure      401     {object}  ErrorResponse  "Unauthorized"
+// @Failure      404     {object}  ErrorResponse  "Link or scheduled change not found"
+// @Failure      409     {object}  ErrorResponse  "Change already ran or was canceled"
+// @Failure      500     {object}  ErrorResponse  "Internal server error"
+// @Router       /api/links/{slug}/scheduled-changes/{id} [delete]
+func (h *HTTPHandler) CancelScheduledChange(c fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(ErrorResponse{Error: "Unauthorized: User ID not found"})
+	}
+
+	linkDomain, err := h.queryDomain(c)
+	if err != nil {
+		return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+	}
+
+	if err := h.Service.CancelScheduledChange(c.Context(), userID, linkDomain, slugParam(c), c.Params("id")); err != nil {
+		if errors.Is(err, domain.ErrLinkNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Link not found"})
+		}
+		if errors.Is(err, domain.ErrScheduledChangeNotFound) {
+			return c.Status(404).JSON(ErrorResponse{Error: "Scheduled change not found"})
+		}
+		if errors.Is(err, domain.ErrScheduledChangeClosed) {
+			return c.Status(409).JSON(ErrorResponse{Error: "This change has already run or been canceled"})
+		}
+		return c.Status(500).JSON(ErrorResponse{Error: "An error occurred while canceling the change"})
+	}
+	return c.SendStatus(204)
+}
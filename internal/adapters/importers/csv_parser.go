@@ -0,0 +1,244 @@
+package importers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+)
+
+// columnParser reads a CSV export whose header row names the columns. Each
+// field lists the header aliases it is read from, in order of preference.
+type columnParser struct {
+	slug      []string
+	target    []string
+	createdAt []string
+	clicks    []string
+	tags      []string
+	title     []string
+	// settings reads the metadata and settings columns of this API's own
+	// link exports, where structured settings are JSON.
+	settings bool
+}
+
+// NewBitlyParser reads Bitly link exports. The slug is taken from the last
+// path segment of the bitlink.
+func NewBitlyParser() ports.ImportParser {
+	return &columnParser{
+		slug:      []string{"custom_bitlink", "bitlink", "link", "short_url", "id"},
+		target:    []string{"long_url", "long url", "destination"},
+		createdAt: []string{"created_at", "created", "date created"},
+		clicks:    []string{"clicks", "total_clicks", "total clicks"},
+		tags:      []string{"tags"},
+		title:     []string{"title"},
+	}
+}
+
+// NewRebrandlyParser reads Rebrandly link exports.
+func NewRebrandlyParser() ports.ImportParser {
+	return &columnParser{
+		slug:      []string{"slashtag", "shorturl", "short url", "short_url"},
+		target:    []string{"destination", "destination url", "long_url"},
+		createdAt: []string{"createdat", "created at", "created_at", "created"},
+		clicks:    []string{"clicks", "total clicks"},
+		tags:      []string{"tags"},
+		title:     []string{"title"},
+	}
+}
+
+// NewCSVParser reads the generic format: slug, target, created_at, clicks,
+// tags, title. It also reads CSV link exports of this API, settings included.
+func NewCSVParser() ports.ImportParser {
+	return &columnParser{
+		slug:      []string{"slug", "short_id"},
+		target:    []string{"target", "target_url"},
+		createdAt: []string{"created_at"},
+		clicks:    []string{"clicks"},
+		tags:      []string{"tags"},
+		title:     []string{"title"},
+		settings:  true,
+	}
+}
+
+func (p *columnParser) Parse(r io.Reader) ([]domain.ImportRecord, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: missing header row", domain.ErrInvalidImportFile)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, exists := columns[name]; !exists {
+			columns[name] = i
+		}
+	}
+
+	lookup := func(aliases []string) int {
+		for _, alias := range aliases {
+			if i, ok := columns[alias]; ok {
+				return i
+			}
+		}
+		return -1
+	}
+	slugCol, targetCol := lookup(p.slug), lookup(p.target)
+	if slugCol == -1 || targetCol == -1 {
+		return nil, fmt.Errorf("%w: expected a %s column and a %s column", domain.ErrInvalidImportFile, p.slug[0], p.target[0])
+	}
+	createdCol, clicksCol, tagsCol, titleCol := lookup(p.createdAt), lookup(p.clicks), lookup(p.tags), lookup(p.title)
+
+	field := func(record []string, i int) string {
+		if i < 0 || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var records []domain.ImportRecord
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidImportFile, err)
+		}
+
+		slug, target := slugFromShortURL(field(record, slugCol)), field(record, targetCol)
+		if slug == "" && target == "" {
+			continue
+		}
+
+		clicks, _ := strconv.Atoi(strings.ReplaceAll(field(record, clicksCol), ",", ""))
+		rec := domain.ImportRecord{
+			Row:          row,
+			Slug:         slug,
+			TargetURL:    target,
+			CreatedAt:    parseTimestamp(field(record, createdCol)),
+			Clicks:       max(clicks, 0),
+			Tags:         splitTags(field(record, tagsCol)),
+			LinkMetadata: domain.LinkMetadata{Title: field(record, titleCol)},
+		}
+		if p.settings {
+			if err := readSettings(&rec, func(name string) string { return field(record, lookup([]string{name})) }); err != nil {
+				return nil, fmt.Errorf("%w: row %d: %v", domain.ErrInvalidImportFile, row, err)
+			}
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// readSettings fills a record from the metadata and settings columns of a
+// link export; value returns "" for a column the file does not have.
+func readSettings(rec *domain.ImportRecord, value func(name string) string) error {
+	rec.Description = value("description")
+	rec.Notes = value("notes")
+	rec.OGTitle = value("og_title")
+	rec.OGDescription = value("og_description")
+	rec.OGImageURL = value("og_image_url")
+
+	if raw := value("forward_path"); raw != "" {
+		forward, err := strconv.ParseBool(raw)
+		if err != nil {
+			return errors.New("forward_path must be true or false")
+		}
+		rec.ForwardPath = forward
+	}
+	if raw := value("max_uses"); raw != "" {
+		maxUses, err := strconv.Atoi(raw)
+		if err != nil {
+			return errors.New("max_uses must be a number")
+		}
+		rec.MaxUses = maxUses
+	}
+
+	for _, column := range []struct {
+		name string
+		dest any
+	}{
+		{"rules", &rec.Rules},
+		{"variants", &rec.Variants},
+		{"query_options", &rec.QueryOptions},
+		{"schedule", &rec.Schedule},
+		{"interstitial", &rec.Interstitial},
+	} {
+		if raw := value(column.name); raw != "" {
+			if err := json.Unmarshal([]byte(raw), column.dest); err != nil {
+				return fmt.Errorf("%s is not valid JSON", column.name)
+			}
+		}
+	}
+	return nil
+}
+
+// slugFromShortURL reduces values like "https://bit.ly/3xYz" or "rebrand.ly/promo"
+// to their slug. Plain slugs are returned unchanged.
+func slugFromShortURL(value string) string {
+	value = strings.TrimSpace(value)
+	if i := strings.Index(value, "://"); i != -1 {
+		value = value[i+3:]
+	}
+	value = strings.Trim(value, "/")
+	if i := strings.LastIndexByte(value, '/'); i != -1 {
+		value = value[i+1:]
+	}
+	if i := strings.IndexAny(value, "?#"); i != -1 {
+		value = value[:i]
+	}
+	return value
+}
+
+var timestampLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05 -0700 MST",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"01/02/2006 15:04",
+	"01/02/2006",
+}
+
+// parseTimestamp returns the zero time for empty or unrecognised values, in
+// which case the link gets the import time as its creation date.
+func parseTimestamp(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC()
+		}
+	}
+	return time.Time{}
+}
+
+func splitTags(value string) []string {
+	if value == "" {
+		return nil
+	}
+	parts := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ';' || r == '|'
+	})
+	tags := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if tag := strings.TrimSpace(part); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
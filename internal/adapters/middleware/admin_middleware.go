@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+	"github.com/gofiber/fiber/v3"
+)
+
+// AdminMiddleware restricts routes to admins: the user IDs configured in
+// ADMIN_USER_IDS, and users whose role in the user table is admin. It must run
+// after AuthMiddleware.RequireAuth.
+type AdminMiddleware struct {
+	admins map[string]struct{}
+	roles  ports.AdminService
+}
+
+// NewAdminMiddleware takes a comma-separated list of admin user IDs, as read
+// from ADMIN_USER_IDS. When roles is nil only those users are admins.
+func NewAdminMiddleware(adminUserIDs string, roles ports.AdminService) *AdminMiddleware {
+	admins := make(map[string]struct{})
+	for _, id := range strings.Split(adminUserIDs, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			admins[id] = struct{}{}
+		}
+	}
+	return &AdminMiddleware{admins: admins, roles: roles}
+}
+
+func (am *AdminMiddleware) RequireAdmin(c fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	if userID == "" || !am.isAdmin(c, userID) {
+		return c.Status(403).JSON(fiber.Map{
+			"error": "Forbidden: Admin access required",
+		})
+	}
+	// Changes made through admin routes are recorded as made by an admin.
+	c.SetContext(domain.ContextWithActor(c.Context(), domain.Actor{Type: domain.ActorAdmin, ID: userID, IP: c.IP()}))
+	return c.Next()
+}
+
+func (am *AdminMiddleware) isAdmin(c fiber.Ctx, userID string) bool {
+	if _, ok := am.admins[userID]; ok {
+		return true
+	}
+	return am.roles != nil && am.roles.IsAdmin(c.Context(), userID)
+}
@@ -2,15 +2,19 @@ package middleware
 
 import (
 	"github.com/esdrassantos06/go-shortener/internal/core/auth"
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
 	"github.com/gofiber/fiber/v3"
 )
 
 type AuthMiddleware struct {
 	validator *auth.SessionValidator
+	// moderation rejects users banned by a moderator; nil skips the check.
+	moderation ports.ModerationService
 }
 
-func NewAuthMiddleware(validator *auth.SessionValidator) *AuthMiddleware {
-	return &AuthMiddleware{validator: validator}
+func NewAuthMiddleware(validator *auth.SessionValidator, moderation ports.ModerationService) *AuthMiddleware {
+	return &AuthMiddleware{validator: validator, moderation: moderation}
 }
 
 func (am *AuthMiddleware) RequireAuth(c fiber.Ctx) error {
@@ -30,7 +34,14 @@ func (am *AuthMiddleware) RequireAuth(c fiber.Ctx) error {
 		})
 	}
 
+	if am.moderation != nil && am.moderation.IsBanned(c.Context(), userID) {
+		return c.Status(403).JSON(fiber.Map{
+			"error": "Forbidden: Account suspended",
+		})
+	}
+
 	c.Locals("userID", userID)
+	c.SetContext(domain.ContextWithActor(c.Context(), domain.Actor{Type: domain.ActorUser, ID: userID, IP: c.IP()}))
 
 	return c.Next()
 }
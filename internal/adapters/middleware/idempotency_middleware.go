@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+	"github.com/gofiber/fiber/v3"
+)
+
+const (
+	idempotencyHeader = "Idempotency-Key"
+	// idempotencyTTL is how long a stored response can be replayed.
+	idempotencyTTL = 24 * 60 * 60
+	// idempotencyLockTTL bounds how long a request that never finishes keeps
+	// its key locked.
+	idempotencyLockTTL      = 60
+	maxIdempotencyKeyLength = 255
+)
+
+type idempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Done        bool   `json:"done"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// IdempotencyMiddleware replays the stored response when a client retries a
+// request with the same Idempotency-Key. Keys are scoped per user, so it must
+// run after RequireAuth.
+type IdempotencyMiddleware struct {
+	cache ports.CacheRepository
+}
+
+func NewIdempotencyMiddleware(cache ports.CacheRepository) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{cache: cache}
+}
+
+func (im *IdempotencyMiddleware) Handle(c fiber.Ctx) error {
+	key := c.Get(idempotencyHeader)
+	if key == "" {
+		return c.Next()
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Idempotency-Key must be at most 255 characters",
+		})
+	}
+
+	userID, _ := c.Locals("userID").(string)
+	cacheKey := "idempotency:" + userID + ":" + key
+
+	sum := sha256.Sum256(append([]byte(c.Method()+" "+c.Path()+"\n"), c.Body()...))
+	fingerprint := hex.EncodeToString(sum[:])
+
+	pending, _ := json.Marshal(idempotencyRecord{Fingerprint: fingerprint})
+	acquired, err := im.cache.SetNX(c.Context(), cacheKey, string(pending), idempotencyLockTTL)
+	if err != nil {
+		// Without Redis the request is processed as if no key was sent.
+		return c.Next()
+	}
+
+	if !acquired {
+		val, err := im.cache.Get(c.Context(), cacheKey)
+		var record idempotencyRecord
+		if err != nil || json.Unmarshal([]byte(val), &record) != nil {
+			return c.Status(409).JSON(fiber.Map{
+				"error": "A request with this Idempotency-Key is still being processed",
+			})
+		}
+		if record.Fingerprint != fingerprint {
+			return c.Status(422).JSON(fiber.Map{
+				"error": "This Idempotency-Key was already used with a different request body",
+			})
+		}
+		if !record.Done {
+			return c.Status(409).JSON(fiber.Map{
+				"error": "A request with this Idempotency-Key is still being processed",
+			})
+		}
+
+		c.Set("Idempotent-Replayed", "true")
+		c.Set(fiber.HeaderContentType, record.ContentType)
+		return c.Status(record.Status).Send(record.Body)
+	}
+
+	if err := c.Next(); err != nil {
+		_ = im.cache.Delete(context.Background(), cacheKey)
+		return err
+	}
+
+	status := c.Response().StatusCode()
+	if status >= 500 {
+		// Server errors are not stored so the client can retry.
+		_ = im.cache.Delete(context.Background(), cacheKey)
+		return nil
+	}
+
+	done, err := json.Marshal(idempotencyRecord{
+		Fingerprint: fingerprint,
+		Done:        true,
+		Status:      status,
+		ContentType: string(c.Response().Header.ContentType()),
+		Body:        c.Response().Body(),
+	})
+	if err == nil {
+		_ = im.cache.Set(context.Background(), cacheKey, string(done), idempotencyTTL)
+	}
+	return nil
+}
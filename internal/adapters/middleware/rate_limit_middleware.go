@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+	"github.com/gofiber/fiber/v3"
+)
+
+// RateLimitMiddleware allows each client IP a fixed number of requests per
+// window, counted in Redis so the limit holds across instances. Rejected
+// requests get a 429 with Retry-After, which a frontend can use to fall back
+// to a captcha.
+type RateLimitMiddleware struct {
+	cache  ports.CacheRepository
+	name   string
+	limit  int
+	window time.Duration
+}
+
+// NewRateLimitMiddleware limits the routes it guards to limit requests per
+// window. name keeps the counters of different limits apart.
+func NewRateLimitMiddleware(cache ports.CacheRepository, name string, limit int, window time.Duration) *RateLimitMiddleware {
+	return &RateLimitMiddleware{cache: cache, name: name, limit: limit, window: window}
+}
+
+func (rl *RateLimitMiddleware) Handle(c fiber.Ctx) error {
+	now := time.Now()
+	windowStart := now.Truncate(rl.window)
+	key := "ratelimit:" + rl.name + ":" + c.IP() + ":" + strconv.FormatInt(windowStart.Unix(), 10)
+
+	count, err := rl.cache.IncrementWindow(c.Context(), key, int(rl.window.Seconds()))
+	if err != nil {
+		// Without Redis requests are let through rather than all refused.
+		return c.Next()
+	}
+
+	c.Set("X-RateLimit-Limit", strconv.Itoa(rl.limit))
+	c.Set("X-RateLimit-Remaining", strconv.Itoa(max(rl.limit-int(count), 0)))
+	if count > int64(rl.limit) {
+		retryAfter := int(windowStart.Add(rl.window).Sub(now).Seconds()) + 1
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfter))
+		return c.Status(429).JSON(fiber.Map{
+			"error":       "Too many requests, try again later",
+			"retry_after": retryAfter,
+		})
+	}
+	return c.Next()
+}
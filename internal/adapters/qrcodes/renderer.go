@@ -0,0 +1,151 @@
+package qrcodes
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// logoRatio is the width of the logo box relative to the code itself. At
+// error correction H a box of this size covers well under the 30% of
+// modules that can be lost.
+const logoRatio = 0.22
+
+type renderer struct{}
+
+func NewRenderer() ports.QRRenderer {
+	return renderer{}
+}
+
+// layout positions the code inside the square image: each module is scale
+// pixels wide and the first module starts at offset on both axes.
+type layout struct {
+	modules [][]bool
+	scale   int
+	offset  int
+	size    int
+}
+
+// logoBox is the square area, centered on the code, cleared for the logo.
+func (l layout) logoBox() image.Rectangle {
+	codeSize := len(l.modules) * l.scale
+	side := int(float64(codeSize) * logoRatio)
+	min := l.offset + (codeSize-side)/2
+	return image.Rect(min, min, min+side, min+side)
+}
+
+func (renderer) Render(content string, opts domain.QROptions, logo image.Image) ([]byte, error) {
+	q, err := qrcode.New(content, recoveryLevel(opts.ErrorCorrection))
+	if err != nil {
+		return nil, err
+	}
+	q.DisableBorder = true
+	modules := q.Bitmap()
+
+	total := len(modules) + 2*opts.Margin
+	scale := opts.Size / total
+	if scale < 1 {
+		return nil, fmt.Errorf("%w: size is too small for this link, use at least %d", domain.ErrInvalidQROptions, total)
+	}
+	l := layout{
+		modules: modules,
+		scale:   scale,
+		offset:  (opts.Size-scale*total)/2 + opts.Margin*scale,
+		size:    opts.Size,
+	}
+
+	if opts.Format == domain.QRFormatSVG {
+		return renderSVG(l, opts, logo)
+	}
+	return renderPNG(l, opts, logo)
+}
+
+func renderPNG(l layout, opts domain.QROptions, logo image.Image) ([]byte, error) {
+	img := image.NewPaletted(image.Rect(0, 0, l.size, l.size), color.Palette{opts.Background, opts.Foreground})
+	for y, row := range l.modules {
+		for x, dark := range row {
+			if dark {
+				px, py := l.offset+x*l.scale, l.offset+y*l.scale
+				draw.Draw(img, image.Rect(px, py, px+l.scale, py+l.scale), image.NewUniform(opts.Foreground), image.Point{}, draw.Src)
+			}
+		}
+	}
+
+	var out image.Image = img
+	if logo != nil {
+		rgba := image.NewRGBA(img.Bounds())
+		draw.Draw(rgba, rgba.Bounds(), img, image.Point{}, draw.Src)
+		box := l.logoBox()
+		draw.Draw(rgba, box, image.NewUniform(opts.Background), image.Point{}, draw.Src)
+		inner := box.Inset(l.scale)
+		scaled := scaleToFit(logo, inner.Dx(), inner.Dy())
+		at := inner.Min.Add(image.Pt((inner.Dx()-scaled.Bounds().Dx())/2, (inner.Dy()-scaled.Bounds().Dy())/2))
+		draw.Draw(rgba, scaled.Bounds().Add(at), scaled, image.Point{}, draw.Over)
+		out = rgba
+	}
+
+	var buf bytes.Buffer
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(&buf, out); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func renderSVG(l layout, opts domain.QROptions, logo image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, l.size, l.size, l.size, l.size)
+	fmt.Fprintf(&buf, `<rect width="100%%" height="100%%" fill="%s"/>`, domain.HexColor(opts.Background))
+	fmt.Fprintf(&buf, `<path fill="%s" d="`, domain.HexColor(opts.Foreground))
+	for y, row := range l.modules {
+		// Runs of dark modules on a row are drawn as one rectangle.
+		for x := 0; x < len(row); {
+			if !row[x] {
+				x++
+				continue
+			}
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			fmt.Fprintf(&buf, "M%d %dh%dv%dh-%dz", l.offset+start*l.scale, l.offset+y*l.scale, (x-start)*l.scale, l.scale, (x-start)*l.scale)
+		}
+	}
+	buf.WriteString(`"/>`)
+
+	if logo != nil {
+		box := l.logoBox()
+		inner := box.Inset(l.scale)
+		var logoPNG bytes.Buffer
+		if err := png.Encode(&logoPNG, scaleToFit(logo, inner.Dx(), inner.Dy())); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&buf, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`, box.Min.X, box.Min.Y, box.Dx(), box.Dy(), domain.HexColor(opts.Background))
+		fmt.Fprintf(&buf, `<image x="%d" y="%d" width="%d" height="%d" preserveAspectRatio="xMidYMid meet" href="data:image/png;base64,%s"/>`,
+			inner.Min.X, inner.Min.Y, inner.Dx(), inner.Dy(), base64.StdEncoding.EncodeToString(logoPNG.Bytes()))
+	}
+
+	buf.WriteString(`</svg>`)
+	return buf.Bytes(), nil
+}
+
+func recoveryLevel(ecc domain.QRErrorCorrection) qrcode.RecoveryLevel {
+	switch ecc {
+	case domain.QRErrorCorrectionL:
+		return qrcode.Low
+	case domain.QRErrorCorrectionQ:
+		return qrcode.High
+	case domain.QRErrorCorrectionH:
+		return qrcode.Highest
+	default:
+		return qrcode.Medium
+	}
+}
@@ -0,0 +1,38 @@
+package qrcodes
+
+import (
+	"image"
+	"image/color"
+)
+
+// scaleToFit resizes img to fit within maxW x maxH, keeping its aspect ratio.
+// Each destination pixel averages the source pixels it covers, which keeps
+// downscaled logos smooth without pulling in an image processing library.
+func scaleToFit(img image.Image, maxW int, maxH int) *image.RGBA {
+	src := img.Bounds()
+	w, h := maxW, maxW*src.Dy()/max(src.Dx(), 1)
+	if h > maxH {
+		w, h = maxH*src.Dx()/max(src.Dy(), 1), maxH
+	}
+	w, h = max(w, 1), max(h, 1)
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0 := src.Min.Y + y*src.Dy()/h
+		y1 := max(src.Min.Y+(y+1)*src.Dy()/h, y0+1)
+		for x := 0; x < w; x++ {
+			x0 := src.Min.X + x*src.Dx()/w
+			x1 := max(src.Min.X+(x+1)*src.Dx()/w, x0+1)
+
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r, g, b, a, n = r+cr, g+cg, b+cb, a+ca, n+1
+				}
+			}
+			dst.SetRGBA64(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)})
+		}
+	}
+	return dst
+}
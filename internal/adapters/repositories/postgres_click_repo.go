@@ -0,0 +1,158 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+)
+
+type postgresClickRepo struct {
+	DB         *sql.DB
+	recordStmt *sql.Stmt
+	initOnce   sync.Once
+}
+
+func NewPostgresClickRepo(db *sql.DB) ports.ClickRepository {
+	repo := &postgresClickRepo{DB: db}
+	repo.initOnce.Do(repo.initStatements)
+	return repo
+}
+
+func (r *postgresClickRepo) initStatements() {
+	var err error
+
+	r.recordStmt, err = r.DB.Prepare(`
+		INSERT INTO click_events (link_id, "shortId", domain, clicked_at, referrer, user_agent, rule_index, variant_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''))`)
+	if err != nil {
+		panic("failed to prepare click record statement: " + err.Error())
+	}
+}
+
+func (r *postgresClickRepo) Record(ctx context.Context, event domain.ClickEvent) error {
+	_, err := r.recordStmt.ExecContext(ctx, event.LinkID, event.ShortID, event.Domain, event.ClickedAt, event.Referrer, event.UserAgent, event.RuleIndex, event.VariantID)
+	return err
+}
+
+func (r *postgresClickRepo) CountByVariant(ctx context.Context, linkID string) (map[string]int, error) {
+	return countByVariant(ctx, r.DB, `
+		SELECT variant_id, COUNT(*)
+		FROM click_events
+		WHERE link_id = $1 AND variant_id IS NOT NULL
+		GROUP BY variant_id`, linkID)
+}
+
+// countByVariant runs a query returning (variant_id, count) rows.
+func countByVariant(ctx context.Context, db *sql.DB, query string, linkID string) (map[string]int, error) {
+	rows, err := db.QueryContext(ctx, query, linkID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var variantID string
+		var n int
+		if err := rows.Scan(&variantID, &n); err != nil {
+			return nil, err
+		}
+		counts[variantID] = n
+	}
+	return counts, rows.Err()
+}
+
+func (r *postgresClickRepo) StreamByUser(ctx context.Context, userID string, from time.Time, to time.Time, fn func(domain.ClickEvent) error) error {
+	var fromArg, toArg *time.Time
+	if !from.IsZero() {
+		fromArg = &from
+	}
+	if !to.IsZero() {
+		toArg = &to
+	}
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT e.link_id, e."shortId", e.domain, e.clicked_at, e.referrer, e.user_agent, e.rule_index, COALESCE(e.variant_id, '')
+		FROM click_events e
+		JOIN urls u ON u.id = e.link_id
+		WHERE u."userId" = $1
+		  AND ($2::timestamp IS NULL OR e.clicked_at >= $2)
+		  AND ($3::timestamp IS NULL OR e.clicked_at < $3)
+		ORDER BY e.clicked_at`, userID, fromArg, toArg)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event domain.ClickEvent
+		if err := rows.Scan(&event.LinkID, &event.ShortID, &event.Domain, &event.ClickedAt, &event.Referrer, &event.UserAgent, &event.RuleIndex, &event.VariantID); err != nil {
+			return err
+		}
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *postgresClickRepo) CountByDay(ctx context.Context, from time.Time, to time.Time) ([]domain.DailyCount, error) {
+	return countByDay(ctx, r.DB, `
+		SELECT to_char(clicked_at, 'YYYY-MM-DD'), COUNT(*)
+		FROM click_events
+		WHERE clicked_at >= $1 AND clicked_at < $2
+		GROUP BY 1
+		ORDER BY 1`, from, to)
+}
+
+// countByDay runs a query over [from, to) returning (day, count) rows.
+func countByDay(ctx context.Context, db *sql.DB, query string, from time.Time, to time.Time) ([]domain.DailyCount, error) {
+	rows, err := db.QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []domain.DailyCount
+	for rows.Next() {
+		var c domain.DailyCount
+		if err := rows.Scan(&c.Day, &c.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+func (r *postgresClickRepo) TopLinks(ctx context.Context, from time.Time, to time.Time, limit int) ([]domain.LinkClicks, error) {
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT u.id, u.domain, u."shortId", u.target_url, u."userId", c.clicks
+		FROM (
+			SELECT link_id, COUNT(*) AS clicks
+			FROM click_events
+			WHERE clicked_at >= $1 AND clicked_at < $2
+			GROUP BY link_id
+			ORDER BY clicks DESC
+			LIMIT $3
+		) c
+		JOIN urls u ON u.id = c.link_id
+		ORDER BY c.clicks DESC, u."shortId"`, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := make([]domain.LinkClicks, 0, limit)
+	for rows.Next() {
+		var l domain.LinkClicks
+		if err := rows.Scan(&l.ID, &l.Domain, &l.ShortID, &l.TargetURL, &l.UserID, &l.Clicks); err != nil {
+			return nil, err
+		}
+		links = append(links, l)
+	}
+	return links, rows.Err()
+}
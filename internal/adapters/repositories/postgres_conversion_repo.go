@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+)
+
+type postgresConversionRepo struct {
+	DB *sql.DB
+}
+
+func NewPostgresConversionRepo(db *sql.DB) ports.ConversionRepository {
+	return &postgresConversionRepo{DB: db}
+}
+
+func (r *postgresConversionRepo) Record(ctx context.Context, conversion domain.Conversion) error {
+	_, err := r.DB.ExecContext(ctx, `
+		INSERT INTO variant_conversions (link_id, variant_id, converted_at)
+		VALUES ($1, $2, $3)`, conversion.LinkID, conversion.VariantID, conversion.ConvertedAt)
+	return err
+}
+
+func (r *postgresConversionRepo) CountByVariant(ctx context.Context, linkID string) (map[string]int, error) {
+	return countByVariant(ctx, r.DB, `
+		SELECT variant_id, COUNT(*)
+		FROM variant_conversions
+		WHERE link_id = $1
+		GROUP BY variant_id`, linkID)
+}
@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+)
+
+type postgresDomainRepo struct {
+	DB                *sql.DB
+	saveStmt          *sql.Stmt
+	getByHostnameStmt *sql.Stmt
+	getClaimStmt      *sql.Stmt
+	listByUserStmt    *sql.Stmt
+	initOnce          sync.Once
+}
+
+func NewPostgresDomainRepo(db *sql.DB) ports.DomainRepository {
+	repo := &postgresDomainRepo{DB: db}
+	repo.initOnce.Do(repo.initStatements)
+	return repo
+}
+
+func (r *postgresDomainRepo) initStatements() {
+	var err error
+
+	r.saveStmt, err = r.DB.Prepare(`
+		INSERT INTO custom_domains (id, hostname, "userId", verification_token, "createdAt")
+		VALUES ($1, $2, $3, $4, NOW())
+		RETURNING "createdAt"`)
+	if err != nil {
+		panic("failed to prepare domain save statement: " + err.Error())
+	}
+
+	r.getByHostnameStmt, err = r.DB.Prepare(`
+		SELECT id, hostname, "userId", verification_token, verified_at, "createdAt"
+		FROM custom_domains
+		WHERE hostname = $1 AND verified_at IS NOT NULL`)
+	if err != nil {
+		panic("failed to prepare getByHostname statement: " + err.Error())
+	}
+
+	r.getClaimStmt, err = r.DB.Prepare(`
+		SELECT id, hostname, "userId", verification_token, verified_at, "createdAt"
+		FROM custom_domains
+		WHERE hostname = $1 AND "userId" = $2`)
+	if err != nil {
+		panic("failed to prepare getClaim statement: " + err.Error())
+	}
+
+	r.listByUserStmt, err = r.DB.Prepare(`
+		SELECT id, hostname, "userId", verification_token, verified_at, "createdAt"
+		FROM custom_domains
+		WHERE "userId" = $1
+		ORDER BY "createdAt" DESC`)
+	if err != nil {
+		panic("failed to prepare listByUser statement: " + err.Error())
+	}
+
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanCustomDomain(row rowScanner) (domain.CustomDomain, error) {
+	var d domain.CustomDomain
+	err := row.Scan(&d.ID, &d.Hostname, &d.UserID, &d.VerificationToken, &d.VerifiedAt, &d.CreatedAt)
+	if err == sql.ErrNoRows {
+		return domain.CustomDomain{}, domain.ErrDomainNotFound
+	}
+	return d, err
+}
+
+func (r *postgresDomainRepo) Save(ctx context.Context, d domain.CustomDomain) (domain.CustomDomain, error) {
+	err := r.saveStmt.QueryRowContext(ctx, d.ID, d.Hostname, d.UserID, d.VerificationToken).Scan(&d.CreatedAt)
+	return d, err
+}
+
+func (r *postgresDomainRepo) GetByHostname(ctx context.Context, hostname string) (domain.CustomDomain, error) {
+	return scanCustomDomain(r.getByHostnameStmt.QueryRowContext(ctx, hostname))
+}
+
+func (r *postgresDomainRepo) ListByUser(ctx context.Context, userID string) ([]domain.CustomDomain, error) {
+	rows, err := r.listByUserStmt.QueryContext(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	domains := make([]domain.CustomDomain, 0)
+	for rows.Next() {
+		d, err := scanCustomDomain(rows)
+		if err != nil {
+			return nil, err
+		}
+		domains = append(domains, d)
+	}
+	return domains, rows.Err()
+}
+
+func (r *postgresDomainRepo) GetClaim(ctx context.Context, hostname string, userID string) (domain.CustomDomain, error) {
+	return scanCustomDomain(r.getClaimStmt.QueryRowContext(ctx, hostname, userID))
+}
+
+// MarkVerified verifies a claim and drops every other claim on its hostname.
+// The claims are locked first, so of two owners verifying at once the second
+// finds the first's verified claim and gets ErrDomainTaken.
+func (r *postgresDomainRepo) MarkVerified(ctx context.Context, id string) (domain.CustomDomain, error) {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.CustomDomain{}, err
+	}
+	defer tx.Rollback()
+
+	var hostname string
+	if err := tx.QueryRowContext(ctx, `SELECT hostname FROM custom_domains WHERE id = $1`, id).Scan(&hostname); err != nil {
+		if err == sql.ErrNoRows {
+			return domain.CustomDomain{}, domain.ErrDomainNotFound
+		}
+		return domain.CustomDomain{}, err
+	}
+
+	var taken bool
+	if err := tx.QueryRowContext(ctx, `
+		SELECT COALESCE(BOOL_OR(verified_at IS NOT NULL AND id <> $2), FALSE)
+		FROM (SELECT id, verified_at FROM custom_domains WHERE hostname = $1 FOR UPDATE) claims`, hostname, id).Scan(&taken); err != nil {
+		return domain.CustomDomain{}, err
+	}
+	if taken {
+		return domain.CustomDomain{}, domain.ErrDomainTaken
+	}
+
+	d, err := scanCustomDomain(tx.QueryRowContext(ctx, `
+		UPDATE custom_domains
+		SET verified_at = COALESCE(verified_at, NOW())
+		WHERE id = $1
+		RETURNING id, hostname, "userId", verification_token, verified_at, "createdAt"`, id))
+	if err != nil {
+		return domain.CustomDomain{}, err
+	}
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM custom_domains WHERE hostname = $1 AND id <> $2`, hostname, id); err != nil {
+		return domain.CustomDomain{}, err
+	}
+	return d, tx.Commit()
+}
@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+)
+
+type postgresExportRepo struct {
+	DB          *sql.DB
+	createStmt  *sql.Stmt
+	updateStmt  *sql.Stmt
+	getByIDStmt *sql.Stmt
+	initOnce    sync.Once
+}
+
+func NewPostgresExportRepo(db *sql.DB) ports.ExportJobRepository {
+	repo := &postgresExportRepo{DB: db}
+	repo.initOnce.Do(repo.initStatements)
+	return repo
+}
+
+func (r *postgresExportRepo) initStatements() {
+	var err error
+
+	r.createStmt, err = r.DB.Prepare(`
+		INSERT INTO export_jobs (id, "userId", dataset, format, from_time, to_time, status, "createdAt")
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		RETURNING "createdAt"`)
+	if err != nil {
+		panic("failed to prepare export create statement: " + err.Error())
+	}
+
+	r.updateStmt, err = r.DB.Prepare(`
+		UPDATE export_jobs
+		SET status = $2, rows = $3, error = $4, finished_at = $5
+		WHERE id = $1`)
+	if err != nil {
+		panic("failed to prepare export update statement: " + err.Error())
+	}
+
+	r.getByIDStmt, err = r.DB.Prepare(`
+		SELECT id, "userId", dataset, format, from_time, to_time, status, rows, error, "createdAt", finished_at
+		FROM export_jobs
+		WHERE id = $1
+		LIMIT 1`)
+	if err != nil {
+		panic("failed to prepare export getByID statement: " + err.Error())
+	}
+}
+
+func (r *postgresExportRepo) Create(ctx context.Context, job domain.ExportJob) (domain.ExportJob, error) {
+	err := r.createStmt.QueryRowContext(ctx, job.ID, job.UserID, job.Dataset, job.Format, job.From, job.To, job.Status).Scan(&job.CreatedAt)
+	return job, err
+}
+
+func (r *postgresExportRepo) Update(ctx context.Context, job domain.ExportJob) error {
+	_, err := r.updateStmt.ExecContext(ctx, job.ID, job.Status, job.Rows, job.Error, job.FinishedAt)
+	return err
+}
+
+func (r *postgresExportRepo) GetByID(ctx context.Context, id string) (domain.ExportJob, error) {
+	var job domain.ExportJob
+
+	err := r.getByIDStmt.QueryRowContext(ctx, id).Scan(&job.ID, &job.UserID, &job.Dataset, &job.Format, &job.From, &job.To, &job.Status, &job.Rows, &job.Error, &job.CreatedAt, &job.FinishedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ExportJob{}, domain.ErrExportNotFound
+		}
+		return domain.ExportJob{}, err
+	}
+	return job, nil
+}
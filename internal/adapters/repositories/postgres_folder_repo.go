@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+)
+
+type postgresFolderRepo struct {
+	DB *sql.DB
+}
+
+func NewPostgresFolderRepo(db *sql.DB) ports.FolderRepository {
+	return &postgresFolderRepo{DB: db}
+}
+
+func scanFolder(row rowScanner) (domain.Folder, error) {
+	var f domain.Folder
+	err := row.Scan(&f.ID, &f.UserID, &f.Name, &f.CreatedAt)
+	if err == sql.ErrNoRows {
+		return domain.Folder{}, domain.ErrFolderNotFound
+	}
+	return f, err
+}
+
+func (r *postgresFolderRepo) Create(ctx context.Context, folder domain.Folder) (domain.Folder, error) {
+	err := r.DB.QueryRowContext(ctx, `
+		INSERT INTO folders (id, "userId", name, "createdAt")
+		VALUES ($1, $2, $3, NOW())
+		RETURNING "createdAt"`, folder.ID, folder.UserID, folder.Name).Scan(&folder.CreatedAt)
+	return folder, err
+}
+
+func (r *postgresFolderRepo) Rename(ctx context.Context, userID string, id string, name string) (domain.Folder, error) {
+	return scanFolder(r.DB.QueryRowContext(ctx, `
+		UPDATE folders SET name = $3
+		WHERE id = $1 AND "userId" = $2
+		RETURNING id, "userId", name, "createdAt"`, id, userID, name))
+}
+
+func (r *postgresFolderRepo) GetByID(ctx context.Context, id string) (domain.Folder, error) {
+	return scanFolder(r.DB.QueryRowContext(ctx, `
+		SELECT id, "userId", name, "createdAt"
+		FROM folders
+		WHERE id = $1`, id))
+}
+
+func (r *postgresFolderRepo) List(ctx context.Context, userID string) ([]domain.Folder, error) {
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT id, "userId", name, "createdAt"
+		FROM folders
+		WHERE "userId" = $1
+		ORDER BY lower(name)`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	folders := make([]domain.Folder, 0)
+	for rows.Next() {
+		f, err := scanFolder(rows)
+		if err != nil {
+			return nil, err
+		}
+		folders = append(folders, f)
+	}
+	return folders, rows.Err()
+}
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sync"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+)
+
+type postgresImportRepo struct {
+	DB                 *sql.DB
+	createStmt         *sql.Stmt
+	updateProgressStmt *sql.Stmt
+	getByIDStmt        *sql.Stmt
+	initOnce           sync.Once
+}
+
+func NewPostgresImportRepo(db *sql.DB) ports.ImportJobRepository {
+	repo := &postgresImportRepo{DB: db}
+	repo.initOnce.Do(repo.initStatements)
+	return repo
+}
+
+func (r *postgresImportRepo) initStatements() {
+	var err error
+
+	r.createStmt, err = r.DB.Prepare(`
+		INSERT INTO import_jobs (id, "userId", format, domain, status, total, "createdAt")
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		RETURNING "createdAt"`)
+	if err != nil {
+		panic("failed to prepare import create statement: " + err.Error())
+	}
+
+	r.updateProgressStmt, err = r.DB.Prepare(`
+		UPDATE import_jobs
+		SET status = $2, processed = $3, imported = $4, conflicts = $5, errors = $6, finished_at = $7
+		WHERE id = $1`)
+	if err != nil {
+		panic("failed to prepare import updateProgress statement: " + err.Error())
+	}
+
+	r.getByIDStmt, err = r.DB.Prepare(`
+		SELECT id, "userId", format, domain, status, total, processed, imported, conflicts, errors, "createdAt", finished_at
+		FROM import_jobs
+		WHERE id = $1
+		LIMIT 1`)
+	if err != nil {
+		panic("failed to prepare import getByID statement: " + err.Error())
+	}
+}
+
+func (r *postgresImportRepo) Create(ctx context.Context, job domain.ImportJob) (domain.ImportJob, error) {
+	err := r.createStmt.QueryRowContext(ctx, job.ID, job.UserID, job.Format, job.Domain, job.Status, job.Total).Scan(&job.CreatedAt)
+	return job, err
+}
+
+func (r *postgresImportRepo) UpdateProgress(ctx context.Context, job domain.ImportJob) error {
+	conflicts, err := json.Marshal(job.Conflicts)
+	if err != nil {
+		return err
+	}
+	errs, err := json.Marshal(job.Errors)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.updateProgressStmt.ExecContext(ctx, job.ID, job.Status, job.Processed, job.Imported, string(conflicts), string(errs), job.FinishedAt)
+	return err
+}
+
+func (r *postgresImportRepo) GetByID(ctx context.Context, id string) (domain.ImportJob, error) {
+	var job domain.ImportJob
+	var conflicts, errs []byte
+
+	err := r.getByIDStmt.QueryRowContext(ctx, id).Scan(&job.ID, &job.UserID, &job.Format, &job.Domain, &job.Status, &job.Total, &job.Processed, &job.Imported, &conflicts, &errs, &job.CreatedAt, &job.FinishedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return domain.ImportJob{}, domain.ErrImportNotFound
+		}
+		return domain.ImportJob{}, err
+	}
+
+	if err := json.Unmarshal(conflicts, &job.Conflicts); err != nil {
+		return domain.ImportJob{}, err
+	}
+	if err := json.Unmarshal(errs, &job.Errors); err != nil {
+		return domain.ImportJob{}, err
+	}
+	return job, nil
+}
@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+)
+
+const linkChangeColumns = `id, link_id, domain, "shortId", type, actor_type, actor_id, ip, before, after, "createdAt"`
+
+// encodeDiff marshals one side of a change for a JSONB column, storing a nil
+// map as NULL.
+func encodeDiff(fields map[string]any) (*string, error) {
+	if fields == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	s := string(raw)
+	return &s, nil
+}
+
+// recordChanges appends entries to the link audit log inside tx, and keeps
+// the settings each of them replaced as a new version of the link.
+func recordChanges(ctx context.Context, tx *sql.Tx, changes ...domain.LinkChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO link_changes (`+linkChangeColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NOW())`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, change := range changes {
+		before, err := encodeDiff(change.Before)
+		if err != nil {
+			return err
+		}
+		after, err := encodeDiff(change.After)
+		if err != nil {
+			return err
+		}
+		if _, err := stmt.ExecContext(ctx, change.ID, change.LinkID, change.Domain, change.ShortID, change.Type,
+			change.ActorType, change.ActorID, change.IP, before, after); err != nil {
+			return err
+		}
+		if change.Previous != nil {
+			if err := saveVersion(ctx, tx, change); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// withChanges runs fn and records changes in the same transaction.
+func withChanges(ctx context.Context, db *sql.DB, changes []domain.LinkChange, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := recordChanges(ctx, tx, changes...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *postgresRepo) ListChanges(ctx context.Context, filter domain.LinkChangeFilter) (domain.LinkChangePage, error) {
+	var args []any
+	where := []string{"TRUE"}
+	add := func(cond string, value any) {
+		args = append(args, value)
+		where = append(where, fmt.Sprintf(cond, len(args)))
+	}
+	if filter.LinkID != "" {
+		add("link_id = $%d", filter.LinkID)
+	}
+	if filter.ActorID != "" {
+		add("actor_id = $%d", filter.ActorID)
+	}
+	if filter.Type != "" {
+		add("type = $%d", filter.Type)
+	}
+	if !filter.From.IsZero() {
+		add(`"createdAt" >= $%d`, filter.From)
+	}
+	if !filter.To.IsZero() {
+		add(`"createdAt" < $%d`, filter.To)
+	}
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.DB.QueryContext(ctx, fmt.Sprintf(`
+		SELECT %s, COUNT(*) OVER ()
+		FROM link_changes
+		WHERE %s
+		ORDER BY "createdAt" DESC, id
+		LIMIT $%d OFFSET $%d`, linkChangeColumns, strings.Join(where, " AND "), len(args)-1, len(args)), args...)
+	if err != nil {
+		return domain.LinkChangePage{}, err
+	}
+	defer rows.Close()
+
+	page := domain.LinkChangePage{Changes: make([]domain.LinkChange, 0)}
+	for rows.Next() {
+		var c domain.LinkChange
+		var before, after []byte
+		if err := rows.Scan(&c.ID, &c.LinkID, &c.Domain, &c.ShortID, &c.Type, &c.ActorType, &c.ActorID, &c.IP, &before, &after, &c.CreatedAt, &page.Total); err != nil {
+			return domain.LinkChangePage{}, err
+		}
+		if before != nil {
+			if err := json.Unmarshal(before, &c.Before); err != nil {
+				return domain.LinkChangePage{}, err
+			}
+		}
+		if after != nil {
+			if err := json.Unmarshal(after, &c.After); err != nil {
+				return domain.LinkChangePage{}, err
+			}
+		}
+		page.Changes = append(page.Changes, c)
+	}
+	return page, rows.Err()
+}
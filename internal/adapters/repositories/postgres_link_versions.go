@@ -0,0 +1,249 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+)
+
+// scheduledChangeColumns is the column list read by scanScheduledChange; it
+// expects link_scheduled_changes to be aliased as s and urls as u.
+const scheduledChangeColumns = `s.id, s.link_id, u.domain, u."shortId", s.run_at, COALESCE(s.target_url, ''), COALESCE(s.version, 0), s.status, s.error, s.created_by, s."createdAt", s.executed_at, s.claimed_at`
+
+func scanScheduledChange(row rowScanner) (domain.ScheduledChange, error) {
+	var c domain.ScheduledChange
+	err := row.Scan(&c.ID, &c.LinkID, &c.Domain, &c.ShortID, &c.RunAt, &c.TargetURL, &c.Version, &c.Status, &c.Error, &c.CreatedBy, &c.CreatedAt, &c.ExecutedAt, &c.ClaimedAt)
+	if err == sql.ErrNoRows {
+		return domain.ScheduledChange{}, domain.ErrScheduledChangeNotFound
+	}
+	return c, err
+}
+
+// saveVersion stores the settings a change replaced under the link's next
+// version number. The change has already updated the link's row, whose lock
+// keeps concurrent changes from taking the same number.
+func saveVersion(ctx context.Context, tx *sql.Tx, change domain.LinkChange) error {
+	raw, err := json.Marshal(change.Previous)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO link_versions (link_id, number, settings, change_id, "createdAt")
+		SELECT $1, COALESCE(MAX(number), 0) + 1, $2, $3, NOW()
+		FROM link_versions
+		WHERE link_id = $1`, change.LinkID, string(raw), change.ID)
+	return err
+}
+
+func scanVersion(row rowScanner) (domain.LinkVersion, error) {
+	var v domain.LinkVersion
+	var settings []byte
+	if err := row.Scan(&v.Number, &settings, &v.ChangeID, &v.ReplacedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return domain.LinkVersion{}, domain.ErrVersionNotFound
+		}
+		return domain.LinkVersion{}, err
+	}
+	if err := json.Unmarshal(settings, &v.Settings); err != nil {
+		return domain.LinkVersion{}, err
+	}
+	return v, nil
+}
+
+func (r *postgresRepo) ListVersions(ctx context.Context, linkID string) ([]domain.LinkVersion, error) {
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT number, settings, change_id, "createdAt"
+		FROM link_versions
+		WHERE link_id = $1
+		ORDER BY number DESC`, linkID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make([]domain.LinkVersion, 0)
+	for rows.Next() {
+		v, err := scanVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+func (r *postgresRepo) GetVersion(ctx context.Context, linkID string, number int) (domain.LinkVersion, error) {
+	return scanVersion(r.DB.QueryRowContext(ctx, `
+		SELECT number, settings, change_id, "createdAt"
+		FROM link_versions
+		WHERE link_id = $1 AND number = $2`, linkID, number))
+}
+
+func (r *postgresRepo) UpdateSettings(ctx context.Context, link domain.Link, change domain.LinkChange) error {
+	rules, err := encodeJSONArray(link.Rules)
+	if err != nil {
+		return err
+	}
+	variants, err := encodeJSONArray(link.Variants)
+	if err != nil {
+		return err
+	}
+	queryOptions, err := json.Marshal(link.QueryOptions)
+	if err != nil {
+		return err
+	}
+	interstitial, err := json.Marshal(link.Interstitial)
+	if err != nil {
+		return err
+	}
+	return withChanges(ctx, r.DB, []domain.LinkChange{change}, func(tx *sql.Tx) error {
+		// uses_left is read before max_uses is assigned, so an unchanged limit
+		// keeps the uses already spent.
+		res, err := tx.ExecContext(ctx, `
+			UPDATE urls SET
+				target_url = $2, target_hash = $3, rules = $4, variants = $5, postback_token = NULLIF($6, ''),
+				forward_path = $7, query_options = $8, active_from = $9, active_until = $10, not_live_url = $11, ended_url = $12,
+				uses_left = CASE WHEN max_uses IS NOT DISTINCT FROM $13::integer THEN uses_left ELSE $13::integer END, max_uses = $13,
+				interstitial = $14, title = $15, description = $16, notes = $17, og_title = $18, og_description = $19, og_image_url = $20
+			WHERE id = $1`,
+			link.ID, link.TargetURL, domain.TargetHash(link.TargetURL), rules, variants, link.PostbackToken,
+			link.ForwardPath, string(queryOptions), link.Schedule.ActiveFrom, link.Schedule.ActiveUntil, link.Schedule.NotLiveURL, link.Schedule.EndedURL,
+			link.MaxUses, string(interstitial), link.Title, link.Description, link.Notes, link.OGTitle, link.OGDescription, link.OGImageURL)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			if err == nil {
+				err = domain.ErrLinkNotFound
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+func (r *postgresRepo) ScheduleChange(ctx context.Context, change domain.ScheduledChange) (domain.ScheduledChange, error) {
+	var targetURL *string
+	var version *int
+	if change.Version > 0 {
+		version = &change.Version
+	} else {
+		targetURL = &change.TargetURL
+	}
+	err := r.DB.QueryRowContext(ctx, `
+		INSERT INTO link_scheduled_changes (id, link_id, run_at, target_url, version, status, created_by, "createdAt")
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		RETURNING "createdAt"`,
+		change.ID, change.LinkID, change.RunAt, targetURL, version, change.Status, change.CreatedBy).Scan(&change.CreatedAt)
+	return change, err
+}
+
+func (r *postgresRepo) ListScheduledChanges(ctx context.Context, linkID string) ([]domain.ScheduledChange, error) {
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT `+scheduledChangeColumns+`
+		FROM link_scheduled_changes s JOIN urls u ON u.id = s.link_id
+		WHERE s.link_id = $1
+		ORDER BY s.run_at, s.id`, linkID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := make([]domain.ScheduledChange, 0)
+	for rows.Next() {
+		c, err := scanScheduledChange(rows)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
+
+func (r *postgresRepo) CancelScheduledChange(ctx context.Context, linkID string, id string) (domain.ScheduledChange, error) {
+	change, err := scanScheduledChange(r.DB.QueryRowContext(ctx, `
+		UPDATE link_scheduled_changes s
+		SET status = 'CANCELED'
+		FROM urls u
+		WHERE u.id = s.link_id AND s.id = $1 AND s.link_id = $2 AND s.status = 'PENDING'
+		RETURNING `+scheduledChangeColumns, id, linkID))
+	if err != domain.ErrScheduledChangeNotFound {
+		return change, err
+	}
+
+	var exists bool
+	if err := r.DB.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM link_scheduled_changes WHERE id = $1 AND link_id = $2)`, id, linkID).Scan(&exists); err != nil {
+		return domain.ScheduledChange{}, err
+	}
+	if exists {
+		return domain.ScheduledChange{}, domain.ErrScheduledChangeClosed
+	}
+	return domain.ScheduledChange{}, domain.ErrScheduledChangeNotFound
+}
+
+func (r *postgresRepo) ClaimDueChanges(ctx context.Context, now time.Time, limit int) ([]domain.ScheduledChange, error) {
+	rows, err := r.DB.QueryContext(ctx, `
+		UPDATE link_scheduled_changes s
+		SET status = 'RUNNING', claimed_at = NOW()
+		FROM urls u
+		WHERE u.id = s.link_id AND s.id IN (
+			SELECT id FROM link_scheduled_changes
+			WHERE run_at <= $1
+				AND (status = 'PENDING' OR (status = 'RUNNING' AND claimed_at < NOW() - make_interval(secs => $3)))
+			ORDER BY run_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED)
+		RETURNING `+scheduledChangeColumns, now.UTC(), limit, domain.ScheduledClaimTimeout.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := make([]domain.ScheduledChange, 0)
+	for rows.Next() {
+		c, err := scanScheduledChange(rows)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// RETURNING does not keep the subquery's order.
+	slices.SortFunc(changes, func(a, b domain.ScheduledChange) int {
+		return a.RunAt.Compare(b.RunAt)
+	})
+	return changes, nil
+}
+
+func (r *postgresRepo) FinishScheduledChange(ctx context.Context, change domain.ScheduledChange, status domain.ScheduledChangeStatus, message string) error {
+	res, err := r.DB.ExecContext(ctx, `
+		UPDATE link_scheduled_changes
+		SET status = $2, error = $3, executed_at = NOW()
+		WHERE id = $1 AND status = 'RUNNING' AND claimed_at = $4`, change.ID, status, message, change.ClaimedAt)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		if err == nil {
+			err = domain.ErrScheduledClaimLost
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *postgresRepo) NextScheduledRun(ctx context.Context) (time.Time, error) {
+	var next sql.NullTime
+	if err := r.DB.QueryRowContext(ctx, `
+		SELECT MIN(run_at) FROM link_scheduled_changes WHERE status = 'PENDING'`).Scan(&next); err != nil {
+		return time.Time{}, err
+	}
+	return next.Time, nil
+}
@@ -0,0 +1,322 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+)
+
+// reportColumns is the column list read by scanReport; it expects
+// abuse_reports to be aliased as r and urls as u.
+const reportColumns = `r.id, r.link_id, COALESCE(u.domain, ''), COALESCE(u."shortId", ''), r.target_url, r.reason, r.details, r.email, r.status, r."createdAt", r.reviewed_by, r.reviewed_at`
+
+const actionColumns = `id, moderator_id, action, link_id, "userId", report_id, note, "createdAt"`
+
+type postgresModerationRepo struct {
+	DB *sql.DB
+}
+
+func NewPostgresModerationRepo(db *sql.DB) ports.ModerationRepository {
+	return &postgresModerationRepo{DB: db}
+}
+
+func scanReport(row rowScanner, extra ...any) (domain.AbuseReport, error) {
+	var r domain.AbuseReport
+	dest := append([]any{&r.ID, &r.LinkID, &r.Domain, &r.ShortID, &r.TargetURL, &r.Reason, &r.Details, &r.Email, &r.Status, &r.CreatedAt, &r.ReviewedBy, &r.ReviewedAt}, extra...)
+	err := row.Scan(dest...)
+	if err == sql.ErrNoRows {
+		return domain.AbuseReport{}, domain.ErrReportNotFound
+	}
+	return r, err
+}
+
+// recordAction appends an entry to the moderation audit log inside tx.
+func recordAction(ctx context.Context, tx *sql.Tx, action domain.ModerationAction) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO moderation_actions (id, moderator_id, action, link_id, "userId", report_id, note, "createdAt")
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())`,
+		action.ID, action.ModeratorID, action.Action, action.LinkID, action.UserID, action.ReportID, action.Note)
+	return err
+}
+
+// withAction runs fn and records action in the same transaction.
+func (r *postgresModerationRepo) withAction(ctx context.Context, action domain.ModerationAction, fn func(tx *sql.Tx) error) error {
+	tx, err := r.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := recordAction(ctx, tx, action); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *postgresModerationRepo) CreateReport(ctx context.Context, report domain.AbuseReport) (domain.AbuseReport, error) {
+	err := r.DB.QueryRowContext(ctx, `
+		INSERT INTO abuse_reports (id, link_id, target_url, reason, details, email, status, "createdAt")
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		RETURNING "createdAt"`,
+		report.ID, report.LinkID, report.TargetURL, report.Reason, report.Details, report.Email, report.Status).Scan(&report.CreatedAt)
+	return report, err
+}
+
+func (r *postgresModerationRepo) GetReport(ctx context.Context, id string) (domain.AbuseReport, error) {
+	return scanReport(r.DB.QueryRowContext(ctx, `
+		SELECT `+reportColumns+`
+		FROM abuse_reports r LEFT JOIN urls u ON u.id = r.link_id
+		WHERE r.id = $1`, id))
+}
+
+func (r *postgresModerationRepo) ListReports(ctx context.Context, filter domain.ReportFilter) (domain.ReportPage, error) {
+	args := []any{filter.Status}
+	where := []string{"r.status = $1"}
+	if filter.LinkID != "" {
+		args = append(args, filter.LinkID)
+		where = append(where, fmt.Sprintf("r.link_id = $%d", len(args)))
+	}
+	args = append(args, filter.Limit, filter.Offset)
+
+	// Open reports are a queue and come oldest first; closed ones are
+	// history and come newest first.
+	order := `r."createdAt" DESC`
+	if filter.Status == domain.ReportOpen {
+		order = `r."createdAt"`
+	}
+
+	rows, err := r.DB.QueryContext(ctx, fmt.Sprintf(`
+		SELECT %s, COUNT(*) OVER ()
+		FROM abuse_reports r LEFT JOIN urls u ON u.id = r.link_id
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d`, reportColumns, strings.Join(where, " AND "), order, len(args)-1, len(args)), args...)
+	if err != nil {
+		return domain.ReportPage{}, err
+	}
+	defer rows.Close()
+
+	page := domain.ReportPage{Reports: make([]domain.AbuseReport, 0)}
+	for rows.Next() {
+		report, err := scanReport(rows, &page.Total)
+		if err != nil {
+			return domain.ReportPage{}, err
+		}
+		page.Reports = append(page.Reports, report)
+	}
+	return page, rows.Err()
+}
+
+func (r *postgresModerationRepo) DismissReport(ctx context.Context, id string, action domain.ModerationAction) (domain.AbuseReport, error) {
+	err := r.withAction(ctx, action, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `
+			UPDATE abuse_reports
+			SET status = $2, reviewed_by = $3, reviewed_at = NOW()
+			WHERE id = $1 AND status = $4`, id, domain.ReportDismissed, action.ModeratorID, domain.ReportOpen)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil || n == 0 {
+			return reportMissingOrClosed(ctx, tx, id, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return domain.AbuseReport{}, err
+	}
+	return r.GetReport(ctx, id)
+}
+
+// reportMissingOrClosed tells apart the two reasons an open report could not
+// be updated.
+func reportMissingOrClosed(ctx context.Context, tx *sql.Tx, id string, err error) error {
+	if err != nil {
+		return err
+	}
+	var exists bool
+	if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM abuse_reports WHERE id = $1)`, id).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return domain.ErrReportClosed
+	}
+	return domain.ErrReportNotFound
+}
+
+// blockLinks sets the status of links to BLOCKED and closes their open
+// reports as actioned. The links are returned with their previous status.
+func blockLinks(ctx context.Context, tx *sql.Tx, moderatorID string, where string, arg any) ([]domain.Link, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, domain, "shortId", status
+		FROM urls
+		WHERE `+where+` AND status <> $2
+		FOR UPDATE`, arg, domain.StatusBlocked)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var blocked []domain.Link
+	var ids []string
+	for rows.Next() {
+		var link domain.Link
+		if err := rows.Scan(&link.ID, &link.Domain, &link.ShortID, &link.Status); err != nil {
+			return nil, err
+		}
+		blocked = append(blocked, link)
+		ids = append(ids, link.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE urls SET status = $2 WHERE id = ANY($1::text[])`, ids, domain.StatusBlocked); err != nil {
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, `
+		UPDATE abuse_reports
+		SET status = $2, reviewed_by = $3, reviewed_at = NOW()
+		WHERE link_id = ANY($1::text[]) AND status = $4`, ids, domain.ReportActioned, moderatorID, domain.ReportOpen)
+	return blocked, err
+}
+
+func (r *postgresModerationRepo) SetLinkStatus(ctx context.Context, linkID string, status domain.LinkStatus, action domain.ModerationAction, change domain.LinkChange) error {
+	return r.withAction(ctx, action, func(tx *sql.Tx) error {
+		var err error
+		if status == domain.StatusBlocked {
+			_, err = blockLinks(ctx, tx, action.ModeratorID, "id = $1", linkID)
+		} else {
+			_, err = tx.ExecContext(ctx, `UPDATE urls SET status = $2 WHERE id = $1`, linkID, status)
+		}
+		if err != nil {
+			return err
+		}
+		return recordChanges(ctx, tx, change)
+	})
+}
+
+func (r *postgresModerationRepo) DeleteLink(ctx context.Context, linkID string, action domain.ModerationAction, change domain.LinkChange) error {
+	return r.withAction(ctx, action, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `DELETE FROM urls WHERE id = $1`, linkID)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if err == nil && n == 0 {
+			err = domain.ErrLinkNotFound
+		}
+		if err != nil {
+			return err
+		}
+		return recordChanges(ctx, tx, change)
+	})
+}
+
+func (r *postgresModerationRepo) SetLinkFlagged(ctx context.Context, linkID string, flagged bool, action domain.ModerationAction, change domain.LinkChange) error {
+	return r.withAction(ctx, action, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `UPDATE urls SET flagged = $2 WHERE id = $1`, linkID, flagged); err != nil {
+			return err
+		}
+		return recordChanges(ctx, tx, change)
+	})
+}
+
+func (r *postgresModerationRepo) BanUser(ctx context.Context, ban domain.UserBan, blockOwned bool, action domain.ModerationAction, blockChange func(before domain.Link) domain.LinkChange) (domain.UserBan, []domain.Link, error) {
+	var blocked []domain.Link
+	err := r.withAction(ctx, action, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO user_bans ("userId", reason, banned_by, "createdAt")
+			VALUES ($1, $2, $3, NOW())
+			ON CONFLICT ("userId") DO UPDATE SET reason = EXCLUDED.reason, banned_by = EXCLUDED.banned_by, "createdAt" = EXCLUDED."createdAt"
+			RETURNING "createdAt"`, ban.UserID, ban.Reason, ban.BannedBy).Scan(&ban.CreatedAt)
+		if err != nil {
+			return err
+		}
+		if !blockOwned {
+			return nil
+		}
+		if blocked, err = blockLinks(ctx, tx, action.ModeratorID, `"userId" = $1`, ban.UserID); err != nil {
+			return err
+		}
+		changes := make([]domain.LinkChange, len(blocked))
+		for i := range blocked {
+			changes[i] = blockChange(blocked[i])
+			blocked[i].Status = domain.StatusBlocked
+		}
+		return recordChanges(ctx, tx, changes...)
+	})
+	if err != nil {
+		return domain.UserBan{}, nil, err
+	}
+	return ban, blocked, nil
+}
+
+func (r *postgresModerationRepo) UnbanUser(ctx context.Context, userID string, action domain.ModerationAction) error {
+	return r.withAction(ctx, action, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, `DELETE FROM user_bans WHERE "userId" = $1`, userID)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if err == nil && n == 0 {
+			err = domain.ErrUserNotBanned
+		}
+		return err
+	})
+}
+
+func (r *postgresModerationRepo) IsBanned(ctx context.Context, userID string) (bool, error) {
+	var banned bool
+	err := r.DB.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM user_bans WHERE "userId" = $1)`, userID).Scan(&banned)
+	return banned, err
+}
+
+func (r *postgresModerationRepo) ListActions(ctx context.Context, filter domain.ModerationActionFilter) (domain.ModerationActionPage, error) {
+	var args []any
+	where := []string{"TRUE"}
+	for _, f := range []struct {
+		column string
+		value  string
+	}{
+		{"moderator_id", filter.ModeratorID},
+		{"link_id", filter.LinkID},
+		{`"userId"`, filter.UserID},
+	} {
+		if f.value != "" {
+			args = append(args, f.value)
+			where = append(where, fmt.Sprintf("%s = $%d", f.column, len(args)))
+		}
+	}
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := r.DB.QueryContext(ctx, fmt.Sprintf(`
+		SELECT %s, COUNT(*) OVER ()
+		FROM moderation_actions
+		WHERE %s
+		ORDER BY "createdAt" DESC
+		LIMIT $%d OFFSET $%d`, actionColumns, strings.Join(where, " AND "), len(args)-1, len(args)), args...)
+	if err != nil {
+		return domain.ModerationActionPage{}, err
+	}
+	defer rows.Close()
+
+	page := domain.ModerationActionPage{Actions: make([]domain.ModerationAction, 0)}
+	for rows.Next() {
+		var a domain.ModerationAction
+		if err := rows.Scan(&a.ID, &a.ModeratorID, &a.Action, &a.LinkID, &a.UserID, &a.ReportID, &a.Note, &a.CreatedAt, &page.Total); err != nil {
+			return domain.ModerationActionPage{}, err
+		}
+		page.Actions = append(page.Actions, a)
+	}
+	return page, rows.Err()
+}
@@ -3,8 +3,12 @@ package repositories
 import (
 	"context"
 	"database/sql"
-	"errors"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"sync"
+	"time"
+	"unicode"
 
 	"github.com/esdrassantos06/go-shortener/internal/core/domain"
 	"github.com/esdrassantos06/go-shortener/internal/core/ports"
@@ -12,10 +16,68 @@ import (
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
+// linkColumns is the column list read by scanLink.
+// The subquery is uncorrelated, so id resolves against the outer query
+// whatever the urls table is aliased as.
+const linkColumns = `id, "shortId", domain, target_url, status, "createdAt", clicks, "userId", folder_id, title, description, notes, og_title, og_description, og_image_url, forward_path, rules, variants, query_options, active_from, active_until, not_live_url, ended_url, max_uses, uses_left, interstitial, flagged, COALESCE(postback_token, ''),
+	id IN (SELECT link_id FROM link_scheduled_changes WHERE status IN ('PENDING', 'RUNNING'))`
+
+// linkTagsColumn selects a link's tag names as a JSON array; it expects the
+// urls table to be aliased as u.
+const linkTagsColumn = `COALESCE((
+	SELECT json_agg(t.name ORDER BY t.name)
+	FROM link_tags lt JOIN tags t ON t.id = lt.tag_id
+	WHERE lt.link_id = u.id), '[]')`
+
+// scanLink scans linkColumns followed by any extra destinations.
+func scanLink(row rowScanner, extra ...any) (domain.Link, error) {
+	var link domain.Link
+	var rules, variants, queryOptions, interstitial []byte
+	dest := append([]any{&link.ID, &link.ShortID, &link.Domain, &link.TargetURL, &link.Status, &link.CreatedAt, &link.Clicks, &link.UserID, &link.FolderID, &link.Title, &link.Description, &link.Notes, &link.OGTitle, &link.OGDescription, &link.OGImageURL, &link.ForwardPath, &rules, &variants, &queryOptions, &link.Schedule.ActiveFrom, &link.Schedule.ActiveUntil, &link.Schedule.NotLiveURL, &link.Schedule.EndedURL, &link.MaxUses, &link.UsesLeft, &interstitial, &link.Flagged, &link.PostbackToken, &link.ChangePending}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		if err == sql.ErrNoRows {
+			return domain.Link{}, domain.ErrLinkNotFound
+		}
+		return domain.Link{}, err
+	}
+	if err := json.Unmarshal(rules, &link.Rules); err != nil {
+		return domain.Link{}, err
+	}
+	if err := json.Unmarshal(variants, &link.Variants); err != nil {
+		return domain.Link{}, err
+	}
+	if err := json.Unmarshal(queryOptions, &link.QueryOptions); err != nil {
+		return domain.Link{}, err
+	}
+	if err := json.Unmarshal(interstitial, &link.Interstitial); err != nil {
+		return domain.Link{}, err
+	}
+	return link, nil
+}
+
+// encodeJSONArray marshals a slice for a JSONB array column, storing nil as
+// an empty array.
+func encodeJSONArray[T any](items []T) (string, error) {
+	if len(items) == 0 {
+		return "[]", nil
+	}
+	raw, err := json.Marshal(items)
+	return string(raw), err
+}
+
+func decodeTags(raw []byte) ([]string, error) {
+	var tags []string
+	if err := json.Unmarshal(raw, &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
 type postgresRepo struct {
 	DB                  *sql.DB
 	saveStmt            *sql.Stmt
 	getByShortIDStmt    *sql.Stmt
+	findByTargetStmt    *sql.Stmt
 	incrementClicksStmt *sql.Stmt
 	initOnce            sync.Once
 }
@@ -30,50 +92,494 @@ func (r *postgresRepo) initStatements() {
 	var err error
 
 	r.saveStmt, err = r.DB.Prepare(`
-		INSERT INTO urls (id, "shortId", target_url, "userId", status, "createdAt", clicks)
-		VALUES ($1, $2, $3, $4, $5, NOW(), 0)
+		INSERT INTO urls (id, "shortId", domain, target_url, target_hash, "userId", status, title, description, notes, og_title, og_description, og_image_url, forward_path, rules, query_options, active_from, active_until, not_live_url, ended_url, max_uses, uses_left, interstitial, "createdAt", clicks)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $21, $22, NOW(), 0)
 		RETURNING "createdAt", clicks`)
 	if err != nil {
 		panic("failed to prepare save statement: " + err.Error())
 	}
 
 	r.getByShortIDStmt, err = r.DB.Prepare(`
-		SELECT id, "shortId", target_url, status, "createdAt", clicks, "userId" 
+		SELECT ` + linkColumns + ` 
 		FROM urls 
-		WHERE "shortId" = $1 
+		WHERE domain = $1 AND "shortId" = $2 
 		LIMIT 1`)
 	if err != nil {
 		panic("failed to prepare getByShortID statement: " + err.Error())
 	}
 
+	r.findByTargetStmt, err = r.DB.Prepare(`
+		SELECT ` + linkColumns + `
+		FROM urls
+		WHERE "userId" = $1 AND target_hash = $2 AND domain = $3 AND status = 'ACTIVE' AND max_uses IS NULL
+		ORDER BY "createdAt"
+		LIMIT 1`)
+	if err != nil {
+		panic("failed to prepare findByTargetHash statement: " + err.Error())
+	}
+
 	r.incrementClicksStmt, err = r.DB.Prepare(`
 		UPDATE urls 
 		SET clicks = clicks + 1 
-		WHERE "shortId" = $1`)
+		WHERE domain = $1 AND "shortId" = $2`)
 	if err != nil {
 		panic("failed to prepare incrementClicks statement: " + err.Error())
 	}
 }
 
-func (r *postgresRepo) Save(ctx context.Context, link domain.Link) (domain.Link, error) {
-	err := r.saveStmt.QueryRowContext(ctx, link.ID, link.ShortID, link.TargetURL, link.UserID, link.Status).Scan(&link.CreatedAt, &link.Clicks)
+func (r *postgresRepo) Save(ctx context.Context, link domain.Link, change domain.LinkChange) (domain.Link, error) {
+	rules, err := encodeJSONArray(link.Rules)
+	if err != nil {
+		return link, err
+	}
+	queryOptions, err := json.Marshal(link.QueryOptions)
+	if err != nil {
+		return link, err
+	}
+	interstitial, err := json.Marshal(link.Interstitial)
+	if err != nil {
+		return link, err
+	}
+	err = withChanges(ctx, r.DB, []domain.LinkChange{change}, func(tx *sql.Tx) error {
+		return tx.StmtContext(ctx, r.saveStmt).QueryRowContext(ctx, link.ID, link.ShortID, link.Domain, link.TargetURL, link.TargetHash, link.UserID, link.Status, link.Title, link.Description, link.Notes, link.OGTitle, link.OGDescription, link.OGImageURL, link.ForwardPath, rules, string(queryOptions), link.Schedule.ActiveFrom, link.Schedule.ActiveUntil, link.Schedule.NotLiveURL, link.Schedule.EndedURL, link.MaxUses, string(interstitial)).Scan(&link.CreatedAt, &link.Clicks)
+	})
 	return link, err
 }
 
-func (r *postgresRepo) GetByShortID(ctx context.Context, shortID string) (domain.Link, error) {
-	var link domain.Link
+func (r *postgresRepo) SaveMany(ctx context.Context, links []domain.Link, atomic bool, changes []domain.LinkChange) ([]domain.Link, []domain.Link, error) {
+	if len(links) == 0 {
+		return nil, nil, nil
+	}
+
+	var query strings.Builder
+	query.WriteString(`INSERT INTO urls (id, "shortId", domain, target_url, target_hash, "userId", status, title, description, notes, og_title, og_description, og_image_url, forward_path, rules, variants, postback_token, query_options, active_from, active_until, not_live_url, ended_url, max_uses, uses_left, interstitial, "createdAt", clicks) VALUES `)
+	args := make([]any, 0, len(links)*26)
+	for i, link := range links {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		// Imported links carry their original creation time and click count.
+		// "createdAt" has no time zone and holds UTC, so the source offset is
+		// applied before binding.
+		var createdAt *time.Time
+		if !link.CreatedAt.IsZero() {
+			utc := link.CreatedAt.UTC()
+			createdAt = &utc
+		}
+		rules, err := encodeJSONArray(link.Rules)
+		if err != nil {
+			return nil, nil, err
+		}
+		variants, err := encodeJSONArray(link.Variants)
+		if err != nil {
+			return nil, nil, err
+		}
+		queryOptions, err := json.Marshal(link.QueryOptions)
+		if err != nil {
+			return nil, nil, err
+		}
+		interstitial, err := json.Marshal(link.Interstitial)
+		if err != nil {
+			return nil, nil, err
+		}
+		n := len(args)
+		fmt.Fprintf(&query, "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, NULLIF($%d, ''), $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, COALESCE($%d::timestamp, NOW()), $%d)",
+			n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8, n+9, n+10, n+11, n+12, n+13, n+14, n+15, n+16, n+17, n+18, n+19, n+20, n+21, n+22, n+23, n+23, n+24, n+25, n+26)
+		args = append(args, link.ID, link.ShortID, link.Domain, link.TargetURL, link.TargetHash, link.UserID, link.Status,
+			link.Title, link.Description, link.Notes, link.OGTitle, link.OGDescription, link.OGImageURL, link.ForwardPath, rules, variants, link.PostbackToken, string(queryOptions),
+			link.Schedule.ActiveFrom, link.Schedule.ActiveUntil, link.Schedule.NotLiveURL, link.Schedule.EndedURL, link.MaxUses, string(interstitial), createdAt, link.Clicks)
+	}
+	query.WriteString(` ON CONFLICT (domain, "shortId") DO NOTHING RETURNING id, "createdAt"`)
 
-	err := r.getByShortIDStmt.QueryRowContext(ctx, shortID).Scan(&link.ID, &link.ShortID, &link.TargetURL, &link.Status, &link.CreatedAt, &link.Clicks, &link.UserID)
+	tx, err := r.DB.BeginTx(ctx, nil)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return domain.Link{}, errors.New("link not found")
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, query.String(), args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	createdAt := make(map[string]time.Time, len(links))
+	for rows.Next() {
+		var id string
+		var ts time.Time
+		if err := rows.Scan(&id, &ts); err != nil {
+			rows.Close()
+			return nil, nil, err
+		}
+		createdAt[id] = ts
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	saved := make([]domain.Link, 0, len(createdAt))
+	var conflicts []domain.Link
+	for _, link := range links {
+		ts, ok := createdAt[link.ID]
+		if !ok {
+			conflicts = append(conflicts, link)
+			continue
+		}
+		link.CreatedAt = ts
+		saved = append(saved, link)
+	}
+
+	if atomic && len(conflicts) > 0 {
+		return nil, conflicts, domain.ErrSlugTaken
+	}
+
+	recorded := make([]domain.LinkChange, 0, len(saved))
+	for _, change := range changes {
+		if _, ok := createdAt[change.LinkID]; ok {
+			recorded = append(recorded, change)
 		}
+	}
+	if err := recordChanges(ctx, tx, recorded...); err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+	return saved, conflicts, nil
+}
+
+func (r *postgresRepo) GetByShortID(ctx context.Context, linkDomain string, shortID string) (domain.Link, error) {
+	return scanLink(r.getByShortIDStmt.QueryRowContext(ctx, linkDomain, shortID))
+}
+
+func (r *postgresRepo) FindByTargetHash(ctx context.Context, userID string, linkDomain string, targetHash string) (domain.Link, error) {
+	link, err := scanLink(r.findByTargetStmt.QueryRowContext(ctx, userID, targetHash, linkDomain))
+	if err != nil {
 		return domain.Link{}, err
 	}
+	link.TargetHash = targetHash
 	return link, nil
 }
 
-func (r *postgresRepo) IncrementClicks(ctx context.Context, shortID string) error {
-	_, err := r.incrementClicksStmt.ExecContext(ctx, shortID)
+func (r *postgresRepo) IncrementClicks(ctx context.Context, linkDomain string, shortID string) error {
+	_, err := r.incrementClicksStmt.ExecContext(ctx, linkDomain, shortID)
+	return err
+}
+
+func (r *postgresRepo) StreamByUser(ctx context.Context, userID string, fn func(domain.Link) error) error {
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT `+linkColumns+`, `+linkTagsColumn+`
+		FROM urls u
+		WHERE "userId" = $1
+		ORDER BY "createdAt"`, userID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tags []byte
+		link, err := scanLink(rows, &tags)
+		if err != nil {
+			return err
+		}
+		if link.Tags, err = decodeTags(tags); err != nil {
+			return err
+		}
+		if err := fn(link); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *postgresRepo) List(ctx context.Context, userID string, filter domain.LinkFilter) (domain.LinkPage, error) {
+	where := []string{`u."userId" = $1`}
+	args := []any{userID}
+	if filter.FolderID != "" {
+		args = append(args, filter.FolderID)
+		where = append(where, fmt.Sprintf("u.folder_id = $%d", len(args)))
+	}
+	if filter.Tag != "" {
+		args = append(args, filter.Tag)
+		where = append(where, fmt.Sprintf(`EXISTS (
+			SELECT 1 FROM link_tags lt JOIN tags t ON t.id = lt.tag_id
+			WHERE lt.link_id = u.id AND lower(t.name) = lower($%d))`, len(args)))
+	}
+	args = append(args, filter.Limit, filter.Offset)
+
+	query := fmt.Sprintf(`
+		SELECT %s, %s, COUNT(*) OVER ()
+		FROM urls u
+		WHERE %s
+		ORDER BY u."createdAt" DESC
+		LIMIT $%d OFFSET $%d`, linkColumns, linkTagsColumn, strings.Join(where, " AND "), len(args)-1, len(args))
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return domain.LinkPage{}, err
+	}
+	defer rows.Close()
+
+	page := domain.LinkPage{Links: make([]domain.Link, 0)}
+	for rows.Next() {
+		var tags []byte
+		link, err := scanLink(rows, &tags, &page.Total)
+		if err != nil {
+			return domain.LinkPage{}, err
+		}
+		if link.Tags, err = decodeTags(tags); err != nil {
+			return domain.LinkPage{}, err
+		}
+		page.Links = append(page.Links, link)
+	}
+	return page, rows.Err()
+}
+
+func (r *postgresRepo) Search(ctx context.Context, userID *string, search domain.LinkSearch) (domain.LinkPage, error) {
+	args := []any{userID, search.Query, prefixTSQuery(search.Query), "%" + escapeLike(search.Query) + "%"}
+	where := []string{
+		`($1::text IS NULL OR u."userId" = $1)`,
+		`(u.search_vector @@ to_tsquery('simple', NULLIF($3, '')) OR u."shortId" ILIKE $4 OR u."shortId" % $2)`,
+	}
+	if search.Status != "" {
+		args = append(args, search.Status)
+		where = append(where, fmt.Sprintf("u.status = $%d", len(args)))
+	}
+	if !search.From.IsZero() {
+		args = append(args, search.From)
+		where = append(where, fmt.Sprintf(`u."createdAt" >= $%d`, len(args)))
+	}
+	if !search.To.IsZero() {
+		args = append(args, search.To)
+		where = append(where, fmt.Sprintf(`u."createdAt" < $%d`, len(args)))
+	}
+	args = append(args, search.Limit, search.Offset)
+
+	query := fmt.Sprintf(`
+		SELECT %s, %s, COUNT(*) OVER ()
+		FROM urls u
+		WHERE %s
+		ORDER BY COALESCE(ts_rank(u.search_vector, to_tsquery('simple', NULLIF($3, ''))), 0) + similarity(u."shortId", $2) DESC,
+			u."createdAt" DESC
+		LIMIT $%d OFFSET $%d`, linkColumns, linkTagsColumn, strings.Join(where, " AND "), len(args)-1, len(args))
+
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return domain.LinkPage{}, err
+	}
+	defer rows.Close()
+
+	page := domain.LinkPage{Links: make([]domain.Link, 0)}
+	for rows.Next() {
+		var tags []byte
+		link, err := scanLink(rows, &tags, &page.Total)
+		if err != nil {
+			return domain.LinkPage{}, err
+		}
+		if link.Tags, err = decodeTags(tags); err != nil {
+			return domain.LinkPage{}, err
+		}
+		page.Links = append(page.Links, link)
+	}
+	return page, rows.Err()
+}
+
+func (r *postgresRepo) CountCreatedByDay(ctx context.Context, from time.Time, to time.Time) ([]domain.DailyCount, error) {
+	return countByDay(ctx, r.DB, `
+		SELECT to_char("createdAt", 'YYYY-MM-DD'), COUNT(*)
+		FROM urls
+		WHERE "createdAt" >= $1 AND "createdAt" < $2
+		GROUP BY 1
+		ORDER BY 1`, from, to)
+}
+
+// prefixTSQuery turns free text into a tsquery matching every word as a
+// prefix, e.g. "spring sal" becomes "spring:* & sal:*". Only letters and
+// digits are kept, so the result is always valid to_tsquery input.
+func prefixTSQuery(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(text string) string {
+	return likeEscaper.Replace(text)
+}
+
+func (r *postgresRepo) StreamByTargetHost(ctx context.Context, userID *string, host string, includeSubdomains bool, fn func(domain.Link) error) error {
+	args := []any{host}
+	match := "u.target_host = $1"
+	if includeSubdomains {
+		args = append(args, escapeLike(reverseString(host))+".%")
+		match = "(u.target_host = $1 OR reverse(u.target_host) LIKE $2)"
+	}
+	where := []string{match}
+	if userID != nil {
+		args = append(args, *userID)
+		where = append(where, fmt.Sprintf(`u."userId" = $%d`, len(args)))
+	}
+
+	rows, err := r.DB.QueryContext(ctx, fmt.Sprintf(`
+		SELECT %s, %s
+		FROM urls u
+		WHERE %s
+		ORDER BY u."createdAt"`, linkColumns, linkTagsColumn, strings.Join(where, " AND ")), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tags []byte
+		link, err := scanLink(rows, &tags)
+		if err != nil {
+			return err
+		}
+		if link.Tags, err = decodeTags(tags); err != nil {
+			return err
+		}
+		if err := fn(link); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func reverseString(s string) string {
+	b := []byte(s)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b)
+}
+
+func (r *postgresRepo) UpdateTargets(ctx context.Context, changes []domain.TargetChange, history []domain.LinkChange) error {
+	return withChanges(ctx, r.DB, history, func(tx *sql.Tx) error {
+		stmt, err := tx.PrepareContext(ctx, `UPDATE urls SET target_url = $2, target_hash = $3 WHERE id = $1`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for _, change := range changes {
+			if _, err := stmt.ExecContext(ctx, change.Link.ID, change.NewTarget, domain.TargetHash(change.NewTarget)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// update runs a single UPDATE of urls and records change with it.
+func (r *postgresRepo) update(ctx context.Context, change domain.LinkChange, query string, args ...any) error {
+	return withChanges(ctx, r.DB, []domain.LinkChange{change}, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query, args...)
+		return err
+	})
+}
+
+func (r *postgresRepo) SetFolder(ctx context.Context, linkID string, folderID *string, change domain.LinkChange) error {
+	return r.update(ctx, change, `UPDATE urls SET folder_id = $2 WHERE id = $1`, linkID, folderID)
+}
+
+func (r *postgresRepo) UpdateMetadata(ctx context.Context, link domain.Link, change domain.LinkChange) error {
+	return r.update(ctx, change, `
+		UPDATE urls SET title = $2, description = $3, notes = $4, og_title = $5, og_description = $6, og_image_url = $7
+		WHERE id = $1`, link.ID, link.Title, link.Description, link.Notes, link.OGTitle, link.OGDescription, link.OGImageURL)
+}
+
+func (r *postgresRepo) SetRules(ctx context.Context, linkID string, rules []domain.RoutingRule, change domain.LinkChange) error {
+	raw, err := encodeJSONArray(rules)
+	if err != nil {
+		return err
+	}
+	return r.update(ctx, change, `UPDATE urls SET rules = $2 WHERE id = $1`, linkID, raw)
+}
+
+func (r *postgresRepo) SetForwardPath(ctx context.Context, linkID string, enabled bool, change domain.LinkChange) error {
+	return r.update(ctx, change, `UPDATE urls SET forward_path = $2 WHERE id = $1`, linkID, enabled)
+}
+
+func (r *postgresRepo) StreamPathSlugs(ctx context.Context, fn func(linkDomain string, shortID string, forwardPath bool) error) error {
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT domain, "shortId", forward_path
+		FROM urls
+		WHERE forward_path OR "shortId" LIKE '%/%'`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var linkDomain, shortID string
+		var forwardPath bool
+		if err := rows.Scan(&linkDomain, &shortID, &forwardPath); err != nil {
+			return err
+		}
+		if err := fn(linkDomain, shortID, forwardPath); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *postgresRepo) SetQueryOptions(ctx context.Context, linkID string, options domain.QueryOptions, change domain.LinkChange) error {
+	raw, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+	return r.update(ctx, change, `UPDATE urls SET query_options = $2 WHERE id = $1`, linkID, string(raw))
+}
+
+func (r *postgresRepo) SetSchedule(ctx context.Context, linkID string, schedule domain.LinkSchedule, change domain.LinkChange) error {
+	return r.update(ctx, change, `UPDATE urls SET active_from = $2, active_until = $3, not_live_url = $4, ended_url = $5 WHERE id = $1`,
+		linkID, schedule.ActiveFrom, schedule.ActiveUntil, schedule.NotLiveURL, schedule.EndedURL)
+}
+
+func (r *postgresRepo) SetMaxUses(ctx context.Context, linkID string, maxUses *int, change domain.LinkChange) error {
+	return r.update(ctx, change, `UPDATE urls SET max_uses = $2, uses_left = $2 WHERE id = $1`, linkID, maxUses)
+}
+
+func (r *postgresRepo) ConsumeUse(ctx context.Context, linkID string) (int, error) {
+	var left int
+	err := r.DB.QueryRowContext(ctx, `
+		UPDATE urls
+		SET uses_left = uses_left - 1
+		WHERE id = $1 AND uses_left > 0
+		RETURNING uses_left`, linkID).Scan(&left)
+	if err == sql.ErrNoRows {
+		return 0, domain.ErrLinkUsedUp
+	}
+	return left, err
+}
+
+func (r *postgresRepo) SetInterstitial(ctx context.Context, linkID string, interstitial domain.Interstitial, change domain.LinkChange) error {
+	raw, err := json.Marshal(interstitial)
+	if err != nil {
+		return err
+	}
+	return r.update(ctx, change, `UPDATE urls SET interstitial = $2 WHERE id = $1`, linkID, string(raw))
+}
+
+func (r *postgresRepo) SetVariants(ctx context.Context, linkID string, variants []domain.Variant, postbackToken string, change domain.LinkChange) error {
+	raw, err := encodeJSONArray(variants)
+	if err != nil {
+		return err
+	}
+	return r.update(ctx, change, `UPDATE urls SET variants = $2, postback_token = NULLIF($3, '') WHERE id = $1`, linkID, raw, postbackToken)
+}
+
+func (r *postgresRepo) GetByPostbackToken(ctx context.Context, token string) (domain.Link, error) {
+	return scanLink(r.DB.QueryRowContext(ctx, `SELECT `+linkColumns+` FROM urls WHERE postback_token = $1`, token))
+}
+
+func (r *postgresRepo) SetTitleIfEmpty(ctx context.Context, linkID string, title string) error {
+	_, err := r.DB.ExecContext(ctx, `UPDATE urls SET title = $2 WHERE id = $1 AND title = ''`, linkID, title)
 	return err
 }
@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type postgresTagRepo struct {
+	DB *sql.DB
+}
+
+func NewPostgresTagRepo(db *sql.DB) ports.TagRepository {
+	return &postgresTagRepo{DB: db}
+}
+
+func (r *postgresTagRepo) Create(ctx context.Context, tag domain.Tag) (domain.Tag, error) {
+	err := r.DB.QueryRowContext(ctx, `
+		INSERT INTO tags (id, "userId", name, "createdAt")
+		VALUES ($1, $2, $3, NOW())
+		RETURNING "createdAt"`, tag.ID, tag.UserID, tag.Name).Scan(&tag.CreatedAt)
+	return tag, err
+}
+
+func (r *postgresTagRepo) Rename(ctx context.Context, userID string, id string, name string) (domain.Tag, error) {
+	var tag domain.Tag
+	err := r.DB.QueryRowContext(ctx, `
+		UPDATE tags SET name = $3
+		WHERE id = $1 AND "userId" = $2
+		RETURNING id, "userId", name, "createdAt"`, id, userID, name).Scan(&tag.ID, &tag.UserID, &tag.Name, &tag.CreatedAt)
+	if err == sql.ErrNoRows {
+		return domain.Tag{}, domain.ErrTagNotFound
+	}
+	return tag, err
+}
+
+func (r *postgresTagRepo) ListWithStats(ctx context.Context, userID string) ([]domain.TagStats, error) {
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT t.id, t."userId", t.name, t."createdAt", COUNT(u.id), COALESCE(SUM(u.clicks), 0)
+		FROM tags t
+		LEFT JOIN link_tags lt ON lt.tag_id = t.id
+		LEFT JOIN urls u ON u.id = lt.link_id
+		WHERE t."userId" = $1
+		GROUP BY t.id
+		ORDER BY lower(t.name)`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := make([]domain.TagStats, 0)
+	for rows.Next() {
+		var s domain.TagStats
+		if err := rows.Scan(&s.ID, &s.UserID, &s.Name, &s.CreatedAt, &s.LinkCount, &s.Clicks); err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
+
+func (r *postgresTagRepo) EnsureTags(ctx context.Context, userID string, names []string) ([]domain.Tag, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, len(names))
+	lowered := make([]string, len(names))
+	for i, name := range names {
+		ids[i] = uuid.New().String()
+		lowered[i] = strings.ToLower(name)
+	}
+
+	if _, err := r.DB.ExecContext(ctx, `
+		INSERT INTO tags (id, "userId", name, "createdAt")
+		SELECT x.id, $1, x.name, NOW()
+		FROM unnest($2::text[], $3::text[]) AS x(id, name)
+		ON CONFLICT DO NOTHING`, userID, ids, names); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT id, "userId", name, "createdAt"
+		FROM tags
+		WHERE "userId" = $1 AND lower(name) = ANY($2::text[])`, userID, lowered)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := make([]domain.Tag, 0, len(names))
+	for rows.Next() {
+		var tag domain.Tag
+		if err := rows.Scan(&tag.ID, &tag.UserID, &tag.Name, &tag.CreatedAt); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+func (r *postgresTagRepo) LinkTags(ctx context.Context, linkID string) ([]string, error) {
+	rows, err := r.DB.QueryContext(ctx, `
+		SELECT t.name
+		FROM link_tags lt JOIN tags t ON t.id = lt.tag_id
+		WHERE lt.link_id = $1
+		ORDER BY t.name`, linkID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+func (r *postgresTagRepo) SetLinkTags(ctx context.Context, linkID string, tagIDs []string, change domain.LinkChange) error {
+	return withChanges(ctx, r.DB, []domain.LinkChange{change}, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM link_tags WHERE link_id = $1`, linkID); err != nil {
+			return err
+		}
+		if len(tagIDs) == 0 {
+			return nil
+		}
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO link_tags (link_id, tag_id)
+			SELECT $1, unnest($2::text[])
+			ON CONFLICT DO NOTHING`, linkID, tagIDs)
+		return err
+	})
+}
+
+func (r *postgresTagRepo) AttachTags(ctx context.Context, linkIDs []string, tagIDs []string) error {
+	if len(linkIDs) == 0 {
+		return nil
+	}
+	_, err := r.DB.ExecContext(ctx, `
+		INSERT INTO link_tags (link_id, tag_id)
+		SELECT * FROM unnest($1::text[], $2::text[])
+		ON CONFLICT DO NOTHING`, linkIDs, tagIDs)
+	return err
+}
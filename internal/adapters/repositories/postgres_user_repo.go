@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+)
+
+// postgresUserRepo reads the "user" table maintained by Better Auth. The role
+// column only exists with Better Auth's admin plugin, so it is read only when
+// withRoles is set.
+type postgresUserRepo struct {
+	DB        *sql.DB
+	withRoles bool
+}
+
+func NewPostgresUserRepo(db *sql.DB, withRoles bool) ports.UserRepository {
+	return &postgresUserRepo{DB: db, withRoles: withRoles}
+}
+
+// userSummaryQuery selects UserSummary columns for the users matching where,
+// followed by the total number of matches.
+func (r *postgresUserRepo) userSummaryQuery(where string, limitArg int) string {
+	role := `''`
+	if r.withRoles {
+		role = `COALESCE(u.role, '')`
+	}
+	return fmt.Sprintf(`
+		SELECT u.id, COALESCE(u.name, ''), COALESCE(u.email, ''), %s, u."createdAt",
+			COUNT(l.id), COALESCE(SUM(l.clicks), 0),
+			EXISTS (SELECT 1 FROM user_bans b WHERE b."userId" = u.id),
+			COUNT(*) OVER ()
+		FROM "user" u
+		LEFT JOIN urls l ON l."userId" = u.id
+		WHERE %s
+		GROUP BY u.id
+		ORDER BY u."createdAt" DESC
+		LIMIT $%d OFFSET $%d`, role, where, limitArg, limitArg+1)
+}
+
+func (r *postgresUserRepo) list(ctx context.Context, query string, args ...any) (domain.UserPage, error) {
+	rows, err := r.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return domain.UserPage{}, err
+	}
+	defer rows.Close()
+
+	page := domain.UserPage{Users: make([]domain.UserSummary, 0)}
+	for rows.Next() {
+		var u domain.UserSummary
+		if err := rows.Scan(&u.ID, &u.Name, &u.Email, &u.Role, &u.CreatedAt, &u.Links, &u.Clicks, &u.Banned, &page.Total); err != nil {
+			return domain.UserPage{}, err
+		}
+		page.Users = append(page.Users, u)
+	}
+	return page, rows.Err()
+}
+
+func (r *postgresUserRepo) Get(ctx context.Context, id string) (domain.UserSummary, error) {
+	page, err := r.list(ctx, r.userSummaryQuery("u.id = $1", 2), id, 1, 0)
+	if err != nil {
+		return domain.UserSummary{}, err
+	}
+	if len(page.Users) == 0 {
+		return domain.UserSummary{}, domain.ErrUserNotFound
+	}
+	return page.Users[0], nil
+}
+
+func (r *postgresUserRepo) Search(ctx context.Context, search domain.UserSearch) (domain.UserPage, error) {
+	return r.list(ctx, r.userSummaryQuery(`(u.id = $1 OR u.email ILIKE $2 OR u.name ILIKE $2)`, 3),
+		search.Query, "%"+escapeLike(search.Query)+"%", search.Limit, search.Offset)
+}
+
+func (r *postgresUserRepo) GetRole(ctx context.Context, userID string) (string, error) {
+	if !r.withRoles {
+		return "", nil
+	}
+	var role string
+	err := r.DB.QueryRowContext(ctx, `SELECT COALESCE(role, '') FROM "user" WHERE id = $1`, userID).Scan(&role)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return role, err
+}
@@ -23,6 +23,56 @@ func (r *RedisRepo) Set(ctx context.Context, key string, value string, ttlSecond
 	return r.Client.Set(ctx, key, value, time.Duration(ttlSeconds)*time.Second).Err()
 }
 
+func (r *RedisRepo) SetNX(ctx context.Context, key string, value string, ttlSeconds int) (bool, error) {
+	return r.Client.SetNX(ctx, key, value, time.Duration(ttlSeconds)*time.Second).Result()
+}
+
+func (r *RedisRepo) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return r.Client.Del(ctx, keys...).Err()
+}
+
+// decrementIfPositiveScript decrements a counter unless it is missing (-2)
+// or already spent (-1), in one step so concurrent callers never take it
+// below zero.
+var decrementIfPositiveScript = redis.NewScript(`
+local left = redis.call('GET', KEYS[1])
+if not left then
+	return -2
+end
+if tonumber(left) <= 0 then
+	return -1
+end
+return redis.call('DECR', KEYS[1])
+`)
+
+func (r *RedisRepo) DecrementIfPositive(ctx context.Context, key string) (int64, bool, error) {
+	left, err := decrementIfPositiveScript.Run(ctx, r.Client, []string{key}).Int64()
+	if err != nil {
+		return 0, false, err
+	}
+	if left == -2 {
+		return 0, false, nil
+	}
+	return left, true, nil
+}
+
 func (r *RedisRepo) IncrementCounter(ctx context.Context, key string) error {
 	return r.Client.Incr(ctx, key).Err()
 }
+
+// incrementWindowScript increments a counter and starts its expiry when it
+// is created, in one step so a counter can never be left without a TTL.
+var incrementWindowScript = redis.NewScript(`
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+	redis.call('EXPIRE', KEYS[1], ARGV[1])
+end
+return count
+`)
+
+func (r *RedisRepo) IncrementWindow(ctx context.Context, key string, ttlSeconds int) (int64, error) {
+	return incrementWindowScript.Run(ctx, r.Client, []string{key}, ttlSeconds).Int64()
+}
@@ -0,0 +1,33 @@
+package storage
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+)
+
+// LocalStorage keeps export files in a directory on local disk.
+type LocalStorage struct {
+	Dir string
+}
+
+func NewLocalStorage(dir string) (ports.ExportStorage, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	return &LocalStorage{Dir: dir}, nil
+}
+
+func (s *LocalStorage) path(name string) string {
+	return filepath.Join(s.Dir, filepath.Base(name))
+}
+
+func (s *LocalStorage) Create(name string) (io.WriteCloser, error) {
+	return os.OpenFile(s.path(name), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o640)
+}
+
+func (s *LocalStorage) Open(name string) (io.ReadCloser, error) {
+	return os.Open(s.path(name))
+}
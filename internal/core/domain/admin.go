@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+// AdminRole is the Better Auth role that grants access to the /api/admin
+// routes, besides the user IDs in ADMIN_USER_IDS.
+const AdminRole = "admin"
+
+// MaxStatsDays caps the period covered by platform statistics.
+const MaxStatsDays = 365
+
+// UserSummary is an admin's view of a user account, with totals over the
+// links it owns.
+type UserSummary struct {
+	ID        string    `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	Email     string    `json:"email" db:"email"`
+	Role      string    `json:"role,omitempty" db:"role"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	Links     int       `json:"links" db:"links"`
+	Clicks    int       `json:"clicks" db:"clicks"`
+	Banned    bool      `json:"banned" db:"banned"`
+}
+
+// UserSearch finds users whose ID is Query, or whose email or name contains
+// it.
+type UserSearch struct {
+	Query  string
+	Limit  int
+	Offset int
+}
+
+type UserPage struct {
+	Users []UserSummary `json:"users"`
+	Total int           `json:"total"`
+}
+
+// DailyCount is a count for one UTC day, formatted as YYYY-MM-DD.
+type DailyCount struct {
+	Day   string `json:"day" example:"2025-01-26"`
+	Count int    `json:"count"`
+}
+
+// LinkClicks is a link with the number of clicks it got in a period.
+type LinkClicks struct {
+	ID        string  `json:"id"`
+	Domain    string  `json:"domain,omitempty"`
+	ShortID   string  `json:"short_id"`
+	TargetURL string  `json:"target_url"`
+	UserID    *string `json:"user_id,omitempty"`
+	Clicks    int     `json:"clicks"`
+}
+
+// PlatformStats covers every user's links over the days from From up to To.
+// Days without links or clicks are included with a zero count.
+type PlatformStats struct {
+	From         time.Time    `json:"from"`
+	To           time.Time    `json:"to"`
+	LinksPerDay  []DailyCount `json:"links_per_day"`
+	ClicksPerDay []DailyCount `json:"clicks_per_day"`
+	TopLinks     []LinkClicks `json:"top_links"`
+}
@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// Visitor describes the request that resolved a link.
+type Visitor struct {
+	IP        string
+	UserAgent string
+	Referrer  string
+	// Country is the visitor's ISO country code, when it could be located.
+	Country string
+	// Query is the raw query string of the short URL request.
+	Query string
+	// Confirmed is set when the visitor explicitly asked to open the link,
+	// which limited links require before spending a use.
+	Confirmed bool
+}
+
+// ClickEvent is one recorded resolve of a link. Visitor IPs are not stored.
+type ClickEvent struct {
+	LinkID    string    `json:"link_id" db:"link_id"`
+	ShortID   string    `json:"short_id" db:"short_id"`
+	Domain    string    `json:"domain,omitempty" db:"domain"`
+	ClickedAt time.Time `json:"clicked_at" db:"clicked_at"`
+	Referrer  string    `json:"referrer,omitempty" db:"referrer"`
+	UserAgent string    `json:"user_agent,omitempty" db:"user_agent"`
+	// RuleIndex is the routing rule that picked the target, or nil when the
+	// visitor got the link's default target.
+	RuleIndex *int `json:"rule_index,omitempty" db:"rule_index"`
+	// VariantID is the split-test variant the visitor was sent to.
+	VariantID string `json:"variant_id,omitempty" db:"variant_id"`
+}
+
+// Conversion is a split-test goal completion reported through a postback.
+type Conversion struct {
+	LinkID      string    `json:"link_id" db:"link_id"`
+	VariantID   string    `json:"variant_id" db:"variant_id"`
+	ConvertedAt time.Time `json:"converted_at" db:"converted_at"`
+}
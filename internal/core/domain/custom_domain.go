@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"net"
+	"strings"
+	"time"
+)
+
+const verificationRecordPrefix = "_zipway-verification."
+
+type CustomDomain struct {
+	ID                string     `json:"id" db:"id"`
+	Hostname          string     `json:"hostname" db:"hostname"`
+	UserID            string     `json:"user_id" db:"user_id"`
+	VerificationToken string     `json:"verification_token" db:"verification_token"`
+	VerifiedAt        *time.Time `json:"verified_at,omitempty" db:"verified_at"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+}
+
+func (d CustomDomain) Verified() bool {
+	return d.VerifiedAt != nil
+}
+
+// VerificationRecordName is the DNS name that must hold the TXT record.
+func (d CustomDomain) VerificationRecordName() string {
+	return verificationRecordPrefix + d.Hostname
+}
+
+// VerificationRecordValue is the TXT value expected at VerificationRecordName.
+func (d CustomDomain) VerificationRecordValue() string {
+	return "zipway-verification=" + d.VerificationToken
+}
+
+// NormalizeHostname lowercases a hostname, strips any port and trailing dot,
+// and rejects values that are not plain multi-label DNS names.
+func NormalizeHostname(raw string) (string, error) {
+	host := strings.ToLower(strings.TrimSpace(raw))
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.TrimSuffix(host, ".")
+
+	if host == "" || len(host) > 253 || !strings.Contains(host, ".") {
+		return "", ErrInvalidHostname
+	}
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return "", ErrInvalidHostname
+		}
+		for _, r := range label {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+				return "", ErrInvalidHostname
+			}
+		}
+	}
+	return host, nil
+}
@@ -0,0 +1,61 @@
+package domain
+
+import "errors"
+
+var (
+	ErrLinkNotFound            = errors.New("link not found")
+	ErrDomainNotFound          = errors.New("domain not found")
+	ErrDomainTaken             = errors.New("domain is already registered")
+	ErrDomainNotVerified       = errors.New("domain is not verified")
+	ErrInvalidHostname         = errors.New("invalid hostname")
+	ErrVerificationFailed      = errors.New("verification record not found")
+	ErrInvalidTargetURL        = errors.New("target URL must be an absolute http or https URL")
+	ErrInvalidSlug             = errors.New("slug contains invalid characters")
+	ErrReservedSlug            = errors.New("slug is reserved")
+	ErrSlugTaken               = errors.New("slug is already in use")
+	ErrDuplicateInBatch        = errors.New("slug appears more than once in the batch")
+	ErrImportNotFound          = errors.New("import not found")
+	ErrUnsupportedFormat       = errors.New("unsupported import format")
+	ErrEmptyImport             = errors.New("import file contains no links")
+	ErrInvalidImportFile       = errors.New("invalid import file")
+	ErrExportNotFound          = errors.New("export not found")
+	ErrExportNotReady          = errors.New("export has not finished yet")
+	ErrInvalidExport           = errors.New("invalid export format or dataset")
+	ErrInvalidTagName          = errors.New("tag name must be 1 to 50 characters")
+	ErrInvalidFolderName       = errors.New("folder name must be 1 to 100 characters")
+	ErrTagNotFound             = errors.New("tag not found")
+	ErrFolderNotFound          = errors.New("folder not found")
+	ErrMetadataTooLong         = errors.New("title, description or notes is too long")
+	ErrInvalidImageURL         = errors.New("image URL must be an absolute http or https URL")
+	ErrEmptySearch             = errors.New("search query must not be empty")
+	ErrInvalidLookup           = errors.New("exactly one of url or domain is required")
+	ErrTooManyMatches          = errors.New("too many links match the prefix")
+	ErrInvalidQROptions        = errors.New("invalid QR code options")
+	ErrInvalidRoutingRule      = errors.New("invalid routing rule")
+	ErrInvalidVariant          = errors.New("invalid split test variant")
+	ErrVariantNotFound         = errors.New("variant not found")
+	ErrInvalidQueryOptions     = errors.New("invalid query options")
+	ErrInvalidSchedule         = errors.New("invalid link schedule")
+	ErrLinkNotLive             = errors.New("link is not live yet")
+	ErrLinkEnded               = errors.New("link has ended")
+	ErrInvalidMaxUses          = errors.New("invalid use limit")
+	ErrLinkUsedUp              = errors.New("link has been used up")
+	ErrConfirmationNeeded      = errors.New("opening this link needs confirmation")
+	ErrInvalidInterstitial     = errors.New("invalid interstitial settings")
+	ErrTargetBlocked           = errors.New("target URL is on a blocklist")
+	ErrLinkBlocked             = errors.New("link has been blocked by a moderator")
+	ErrInvalidReport           = errors.New("invalid abuse report")
+	ErrAlreadyReported         = errors.New("link was already reported from this address")
+	ErrReportNotFound          = errors.New("report not found")
+	ErrReportClosed            = errors.New("report has already been reviewed")
+	ErrInvalidReview           = errors.New("invalid moderation review")
+	ErrUserNotBanned           = errors.New("user is not banned")
+	ErrUserNotFound            = errors.New("user not found")
+	ErrInvalidLinkStatus       = errors.New("status must be ACTIVE, PAUSED or BLOCKED")
+	ErrInvalidLogo             = errors.New("logo must be a PNG, JPEG or GIF image under 1 MB")
+	ErrVersionNotFound         = errors.New("link version not found")
+	ErrInvalidScheduledChange  = errors.New("invalid scheduled change")
+	ErrScheduledChangeNotFound = errors.New("scheduled change not found")
+	ErrScheduledChangeClosed   = errors.New("scheduled change has already run or been canceled")
+	ErrScheduledClaimLost      = errors.New("scheduled change was taken over by another instance")
+)
@@ -0,0 +1,81 @@
+package domain
+
+import "time"
+
+type ExportFormat string
+
+const (
+	ExportFormatCSV     ExportFormat = "csv"
+	ExportFormatJSONL   ExportFormat = "jsonl"
+	ExportFormatParquet ExportFormat = "parquet"
+)
+
+func (f ExportFormat) Valid() bool {
+	switch f {
+	case ExportFormatCSV, ExportFormatJSONL, ExportFormatParquet:
+		return true
+	}
+	return false
+}
+
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportFormatCSV:
+		return "text/csv"
+	case ExportFormatJSONL:
+		return "application/x-ndjson"
+	default:
+		return "application/vnd.apache.parquet"
+	}
+}
+
+type ExportDataset string
+
+const (
+	ExportDatasetLinks  ExportDataset = "links"
+	ExportDatasetClicks ExportDataset = "clicks"
+)
+
+func (d ExportDataset) Valid() bool {
+	return d == ExportDatasetLinks || d == ExportDatasetClicks
+}
+
+// ExportRequest selects what to export. From and To bound click events by
+// ClickedAt; a zero value leaves that side of the range open.
+type ExportRequest struct {
+	Dataset ExportDataset
+	Format  ExportFormat
+	From    time.Time
+	To      time.Time
+}
+
+// FileName is the download name for the export, e.g. "links.csv".
+func (r ExportRequest) FileName() string {
+	return string(r.Dataset) + "." + string(r.Format)
+}
+
+type ExportJob struct {
+	ID         string        `json:"id" db:"id"`
+	UserID     string        `json:"user_id" db:"user_id"`
+	Dataset    ExportDataset `json:"dataset" db:"dataset"`
+	Format     ExportFormat  `json:"format" db:"format"`
+	From       *time.Time    `json:"from,omitempty" db:"from_time"`
+	To         *time.Time    `json:"to,omitempty" db:"to_time"`
+	Status     JobStatus     `json:"status" db:"status"`
+	Rows       int           `json:"rows" db:"rows"`
+	Error      string        `json:"error,omitempty" db:"error"`
+	CreatedAt  time.Time     `json:"created_at" db:"created_at"`
+	FinishedAt *time.Time    `json:"finished_at,omitempty" db:"finished_at"`
+}
+
+// Request rebuilds the export parameters the job was started with.
+func (j ExportJob) Request() ExportRequest {
+	req := ExportRequest{Dataset: j.Dataset, Format: j.Format}
+	if j.From != nil {
+		req.From = *j.From
+	}
+	if j.To != nil {
+		req.To = *j.To
+	}
+	return req
+}
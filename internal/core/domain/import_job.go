@@ -0,0 +1,52 @@
+package domain
+
+import "time"
+
+type ImportFormat string
+
+const (
+	ImportFormatBitly     ImportFormat = "bitly"
+	ImportFormatRebrandly ImportFormat = "rebrandly"
+	ImportFormatCSV       ImportFormat = "csv"
+)
+
+// ImportRecord is one link read from an export file. Row is the 1-based line
+// number in the file, header included. Settings besides the metadata are only
+// found in this API's own link exports.
+type ImportRecord struct {
+	Row          int
+	Slug         string
+	TargetURL    string
+	CreatedAt    time.Time
+	Clicks       int
+	Tags         []string
+	ForwardPath  bool
+	Rules        []RoutingRule
+	Variants     []Variant
+	QueryOptions QueryOptions
+	Schedule     LinkSchedule
+	MaxUses      int
+	Interstitial Interstitial
+	LinkMetadata
+}
+
+type ImportIssue struct {
+	Row   int    `json:"row"`
+	Slug  string `json:"slug"`
+	Error string `json:"error"`
+}
+
+type ImportJob struct {
+	ID         string        `json:"id" db:"id"`
+	UserID     string        `json:"user_id" db:"user_id"`
+	Format     ImportFormat  `json:"format" db:"format"`
+	Domain     string        `json:"domain,omitempty" db:"domain"`
+	Status     JobStatus     `json:"status" db:"status"`
+	Total      int           `json:"total" db:"total"`
+	Processed  int           `json:"processed" db:"processed"`
+	Imported   int           `json:"imported" db:"imported"`
+	Conflicts  []ImportIssue `json:"conflicts" db:"conflicts"`
+	Errors     []ImportIssue `json:"errors" db:"errors"`
+	CreatedAt  time.Time     `json:"created_at" db:"created_at"`
+	FinishedAt *time.Time    `json:"finished_at,omitempty" db:"finished_at"`
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "fmt"
+
+// MaxInterstitialCountdown caps how long the interstitial page waits before
+// continuing on its own.
+const MaxInterstitialCountdown = 30
+
+// Interstitial makes a link show a page naming the destination, with a
+// continue button, instead of redirecting straight away. With a Countdown the
+// page continues by itself after that many seconds.
+type Interstitial struct {
+	Enabled   bool `json:"enabled"`
+	Countdown int  `json:"countdown,omitempty" example:"5"`
+}
+
+// IsZero reports whether the link redirects without an interstitial.
+func (i Interstitial) IsZero() bool {
+	return i == Interstitial{}
+}
+
+// Normalize checks the countdown and clears it when the page is disabled.
+func (i Interstitial) Normalize() (Interstitial, error) {
+	if i.Countdown < 0 || i.Countdown > MaxInterstitialCountdown {
+		return Interstitial{}, fmt.Errorf("%w: countdown must be between 0 and %d seconds", ErrInvalidInterstitial, MaxInterstitialCountdown)
+	}
+	if !i.Enabled {
+		return Interstitial{}, nil
+	}
+	return i, nil
+}
+
+// ShowsInterstitial reports whether visitors see the interstitial page, either
+// because the owner enabled it or because an admin flagged the link.
+func (l Link) ShowsInterstitial() bool {
+	return l.Interstitial.Enabled || l.Flagged
+}
@@ -1,20 +1,188 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 type LinkStatus string
 
 const (
 	StatusActive LinkStatus = "ACTIVE"
+	// StatusPaused stops a link at its owner's request.
 	StatusPaused LinkStatus = "PAUSED"
+	// StatusBlocked is set by moderators; owners cannot lift it.
+	StatusBlocked LinkStatus = "BLOCKED"
 )
 
 type Link struct {
 	ID        string     `json:"id" db:"id"`
 	ShortID   string     `json:"short_id" db:"short_id"`
+	Domain    string     `json:"domain,omitempty" db:"domain"`
 	Clicks    int        `json:"clicks" db:"clicks"`
 	CreatedAt time.Time  `json:"created_at" db:"created_at"`
 	UserID    *string    `json:"user_id,omitempty" db:"user_id"`
 	TargetURL string     `json:"target_url" db:"target_url"`
 	Status    LinkStatus `json:"status" db:"status"`
+	FolderID  *string    `json:"folder_id,omitempty" db:"folder_id"`
+	Tags      []string   `json:"tags,omitempty" db:"-"`
+	// Rules send matching visitors somewhere other than TargetURL; the first
+	// matching rule wins.
+	Rules []RoutingRule `json:"rules,omitempty" db:"rules"`
+	// Variants split visitors who match no rule across several targets.
+	Variants []Variant `json:"variants,omitempty" db:"variants"`
+	// ForwardPath makes the link a prefix: "<slug>/a/b" redirects to
+	// "<target>/a/b".
+	ForwardPath bool `json:"forward_path,omitempty" db:"forward_path"`
+	// QueryOptions add UTM parameters and the short URL's query string to
+	// whichever target the visitor is sent to.
+	QueryOptions QueryOptions `json:"query_options,omitzero" db:"query_options"`
+	// Schedule limits when the link is live.
+	Schedule LinkSchedule `json:"schedule,omitzero" db:"-"`
+	// MaxUses is the number of times a limited link can be opened, and
+	// UsesLeft how many of those remain. Both are nil for unlimited links.
+	MaxUses  *int `json:"max_uses,omitempty" db:"max_uses"`
+	UsesLeft *int `json:"uses_left,omitempty" db:"uses_left"`
+	// Interstitial shows visitors where the link goes before they continue.
+	Interstitial Interstitial `json:"interstitial,omitzero" db:"interstitial"`
+	// Flagged is set by admins on suspicious links and forces a warning
+	// interstitial, whatever the owner chose.
+	Flagged bool `json:"flagged,omitempty" db:"flagged"`
+	// ChangePending is set while a scheduled change of the link is waiting
+	// to run, so redirects are not cached past it.
+	ChangePending bool `json:"change_pending,omitempty" db:"-"`
+	// PostbackToken authenticates conversion postbacks for the split test.
+	PostbackToken string `json:"postback_token,omitempty" db:"postback_token"`
+
+	LinkMetadata
+
+	// TargetHash is TargetHash(TargetURL), stored for deduplication.
+	TargetHash string `json:"-" db:"target_hash"`
+}
+
+// Targets returns every URL the link can send visitors to: its target, the
+// targets of its rules and variants, and its schedule's fallback URLs.
+func (l Link) Targets() []string {
+	targets := []string{l.TargetURL}
+	for _, rule := range l.Rules {
+		targets = append(targets, rule.TargetURL)
+	}
+	for _, v := range l.Variants {
+		targets = append(targets, v.TargetURL)
+	}
+	for _, u := range []string{l.Schedule.NotLiveURL, l.Schedule.EndedURL} {
+		if u != "" {
+			targets = append(targets, u)
+		}
+	}
+	return targets
+}
+
+// LinkInput holds the caller-supplied fields for a new link. ReuseExisting
+// returns the user's existing active link for the same normalized target on
+// the same domain instead of creating a new one; it is ignored when a custom
+// slug is given.
+type LinkInput struct {
+	TargetURL     string
+	CustomSlug    string
+	Domain        string
+	ReuseExisting bool
+	ForwardPath   bool
+	Rules         []RoutingRule
+	QueryOptions  QueryOptions
+	Schedule      LinkSchedule
+	// MaxUses limits how many times the link can be opened; 0 is unlimited.
+	MaxUses      int
+	Interstitial Interstitial
+	LinkMetadata
+}
+
+// HasSettings reports whether the input configures more than the link's
+// target: an existing link for the same target cannot stand in for it then.
+func (in LinkInput) HasSettings() bool {
+	return in.ForwardPath || len(in.Rules) > 0 || !in.QueryOptions.IsZero() || in.Schedule != (LinkSchedule{}) ||
+		in.MaxUses > 0 || !in.Interstitial.IsZero() || in.LinkMetadata != (LinkMetadata{})
+}
+
+var reservedSlugs = map[string]struct{}{
+	"api":         {},
+	"swagger":     {},
+	"shorten":     {},
+	"admin":       {},
+	"health":      {},
+	"metrics":     {},
+	"docs":        {},
+	"static":      {},
+	"assets":      {},
+	"favicon.ico": {},
+}
+
+// IsReservedSlug reports whether a slug, or the first segment of a nested
+// slug such as "guide/v2", clashes with a top-level route.
+func IsReservedSlug(slug string) bool {
+	slug, _, _ = strings.Cut(strings.ToLower(slug), "/")
+	_, exists := reservedSlugs[slug]
+	return exists
+}
+
+// LinkFilter narrows a user's link listing. Tag matches a tag name, ignoring
+// case; FolderID matches the link's folder.
+type LinkFilter struct {
+	Tag      string
+	FolderID string
+	Limit    int
+	Offset   int
+}
+
+// LinkSearch is a full-text search over a user's links. Status, From and To
+// are optional filters; From is inclusive and To exclusive on CreatedAt.
+type LinkSearch struct {
+	Query  string
+	Status LinkStatus
+	From   time.Time
+	To     time.Time
+	Limit  int
+	Offset int
+}
+
+// TargetLookup selects links by destination. URL matches normalized targets
+// at or under that URL; Domain matches target hosts equal to the domain or
+// one of its subdomains. Exactly one of them is set.
+type TargetLookup struct {
+	URL    string
+	Domain string
+}
+
+// TargetLookupResult lists the links matching a TargetLookup. Truncated is
+// set when more links matched than were returned.
+type TargetLookupResult struct {
+	Links     []Link `json:"links"`
+	Truncated bool   `json:"truncated"`
+}
+
+// TargetRewrite replaces the From prefix of matching targets with To.
+type TargetRewrite struct {
+	From   string
+	To     string
+	DryRun bool
+}
+
+// TargetChange is one link's target before and after a rewrite.
+type TargetChange struct {
+	Link      Link   `json:"link"`
+	OldTarget string `json:"old_target"`
+	NewTarget string `json:"new_target"`
+}
+
+// LinkPage is one page of a link listing; Total counts every matching link.
+type LinkPage struct {
+	Links []Link `json:"links"`
+	Total int    `json:"total"`
+}
+
+// BulkLinkResult is the outcome of one item of a bulk create, in input order.
+type BulkLinkResult struct {
+	Index int
+	Link  *Link
+	Err   error
 }
@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+type LinkChangeType string
+
+const (
+	ChangeCreate   LinkChangeType = "CREATE"
+	ChangeUpdate   LinkChangeType = "UPDATE"
+	ChangePause    LinkChangeType = "PAUSE"
+	ChangeActivate LinkChangeType = "ACTIVATE"
+	ChangeBlock    LinkChangeType = "BLOCK"
+	ChangeDelete   LinkChangeType = "DELETE"
+	// ChangeRestore puts back the settings of an earlier version.
+	ChangeRestore LinkChangeType = "RESTORE"
+)
+
+var statusChanges = map[LinkStatus]LinkChangeType{
+	StatusActive:  ChangeActivate,
+	StatusPaused:  ChangePause,
+	StatusBlocked: ChangeBlock,
+}
+
+// StatusChange returns the change type recorded when a link is set to status.
+func StatusChange(status LinkStatus) LinkChangeType {
+	if t, ok := statusChanges[status]; ok {
+		return t
+	}
+	return ChangeUpdate
+}
+
+type ActorType string
+
+const (
+	ActorUser  ActorType = "USER"
+	ActorAdmin ActorType = "ADMIN"
+	// ActorSystem covers changes made without a request, such as background
+	// jobs started by nobody in particular. Scheduled changes are made by the
+	// system actor with the scheduled change's ID.
+	ActorSystem ActorType = "SYSTEM"
+)
+
+// Actor is who a request acts as, recorded with every link change it makes.
+type Actor struct {
+	Type ActorType
+	ID   string
+	IP   string
+}
+
+type actorKey struct{}
+
+// ContextWithActor returns a copy of ctx carrying the actor.
+func ContextWithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor set by ContextWithActor, or the system
+// actor when there is none.
+func ActorFromContext(ctx context.Context) Actor {
+	if actor, ok := ctx.Value(actorKey{}).(Actor); ok {
+		return actor
+	}
+	return Actor{Type: ActorSystem}
+}
+
+// LinkChange is an entry of the append-only link audit log. Before and After
+// hold the fields that changed, keyed by their JSON name; a creation has no
+// Before and a deletion no After.
+type LinkChange struct {
+	ID        string         `json:"id"`
+	LinkID    string         `json:"link_id"`
+	Domain    string         `json:"domain,omitempty"`
+	ShortID   string         `json:"short_id"`
+	Type      LinkChangeType `json:"type" example:"UPDATE"`
+	ActorType ActorType      `json:"actor_type" example:"USER"`
+	ActorID   string         `json:"actor_id,omitempty"`
+	IP        string         `json:"ip,omitempty"`
+	Before    map[string]any `json:"before,omitempty"`
+	After     map[string]any `json:"after,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+	// Previous holds the settings the change replaced, when it replaced any;
+	// they are kept as a version of the link.
+	Previous *LinkSettings `json:"-"`
+}
+
+// LinkChangeFilter narrows the audit log. From is inclusive and To exclusive.
+type LinkChangeFilter struct {
+	LinkID  string
+	ActorID string
+	Type    LinkChangeType
+	From    time.Time
+	To      time.Time
+	Limit   int
+	Offset  int
+}
+
+type LinkChangePage struct {
+	Changes []LinkChange `json:"changes"`
+	Total   int          `json:"total"`
+}
+
+// DiffLinks returns the fields that differ between two versions of a link.
+// A zero before (a creation) or after (a deletion) yields every field of the
+// other version. Counters and timestamps are not edits and are left out, as
+// are postback tokens, since the log is kept for good.
+func DiffLinks(before Link, after Link) (map[string]any, map[string]any) {
+	if before.ID == "" {
+		return nil, linkFields(after)
+	}
+	if after.ID == "" {
+		return linkFields(before), nil
+	}
+
+	old, updated := linkFields(before), linkFields(after)
+	diffBefore, diffAfter := make(map[string]any), make(map[string]any)
+	for key, value := range old {
+		if !reflect.DeepEqual(value, updated[key]) {
+			diffBefore[key], diffAfter[key] = value, updated[key]
+		}
+	}
+	for key, value := range updated {
+		if _, ok := old[key]; !ok {
+			diffBefore[key], diffAfter[key] = nil, value
+		}
+	}
+	return diffBefore, diffAfter
+}
+
+// linkFields returns the fields of a link as the API shows them.
+func linkFields(link Link) map[string]any {
+	raw, err := json.Marshal(link)
+	if err != nil {
+		return nil
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil
+	}
+	for _, key := range []string{"clicks", "created_at", "postback_token"} {
+		delete(fields, key)
+	}
+	return fields
+}
@@ -0,0 +1,108 @@
+package domain
+
+import "unicode/utf8"
+
+const (
+	maxTitleLength       = 255
+	maxDescriptionLength = 1000
+	maxNotesLength       = 5000
+	maxImageURLLength    = 2048
+)
+
+// LinkMetadata is the owner-editable description of a link. The OG fields
+// override the Open Graph and Twitter Card tags served to link-preview
+// crawlers; when empty, Title and Description are used instead.
+type LinkMetadata struct {
+	Title       string `json:"title,omitempty" db:"title"`
+	Description string `json:"description,omitempty" db:"description"`
+	// Notes are private to the link's owner.
+	Notes         string `json:"notes,omitempty" db:"notes"`
+	OGTitle       string `json:"og_title,omitempty" db:"og_title"`
+	OGDescription string `json:"og_description,omitempty" db:"og_description"`
+	OGImageURL    string `json:"og_image_url,omitempty" db:"og_image_url"`
+}
+
+// Validate checks field lengths and that the preview image is an absolute
+// http or https URL.
+func (m LinkMetadata) Validate() error {
+	if utf8.RuneCountInString(m.Title) > maxTitleLength ||
+		utf8.RuneCountInString(m.OGTitle) > maxTitleLength ||
+		utf8.RuneCountInString(m.Description) > maxDescriptionLength ||
+		utf8.RuneCountInString(m.OGDescription) > maxDescriptionLength ||
+		utf8.RuneCountInString(m.Notes) > maxNotesLength {
+		return ErrMetadataTooLong
+	}
+	if m.OGImageURL != "" && (!isHTTPURL(m.OGImageURL) || len(m.OGImageURL) > maxImageURLLength) {
+		return ErrInvalidImageURL
+	}
+	return nil
+}
+
+// LinkUpdate changes a link's metadata. Nil fields are left unchanged and an
+// empty string clears the field.
+type LinkUpdate struct {
+	Title         *string
+	Description   *string
+	Notes         *string
+	OGTitle       *string
+	OGDescription *string
+	OGImageURL    *string
+}
+
+// Apply copies the set fields of the update onto m.
+func (u LinkUpdate) Apply(m *LinkMetadata) {
+	for _, f := range []struct {
+		src *string
+		dst *string
+	}{
+		{u.Title, &m.Title},
+		{u.Description, &m.Description},
+		{u.Notes, &m.Notes},
+		{u.OGTitle, &m.OGTitle},
+		{u.OGDescription, &m.OGDescription},
+		{u.OGImageURL, &m.OGImageURL},
+	} {
+		if f.src != nil {
+			*f.dst = *f.src
+		}
+	}
+}
+
+// TruncateTitle shortens a fetched page title to the maximum title length.
+func TruncateTitle(title string) string {
+	if utf8.RuneCountInString(title) <= maxTitleLength {
+		return title
+	}
+	return string([]rune(title)[:maxTitleLength])
+}
+
+// LinkPreview is what link-preview crawlers are shown for a link.
+type LinkPreview struct {
+	Title       string
+	Description string
+	ImageURL    string
+	ShortURL    string
+	TargetURL   string
+}
+
+// Preview resolves the preview fields of a link, falling back from the OG
+// overrides to the title and description, and finally to the target URL.
+func (l Link) Preview(shortURL string) LinkPreview {
+	p := LinkPreview{
+		Title:       l.OGTitle,
+		Description: l.OGDescription,
+		ImageURL:    l.OGImageURL,
+		ShortURL:    shortURL,
+		TargetURL:   l.TargetURL,
+	}
+	if p.Title == "" {
+		p.Title = l.Title
+	}
+	if p.Title == "" {
+		p.Title = l.TargetURL
+	}
+	if p.Description == "" {
+		p.Description = l.Description
+	}
+	return p
+}
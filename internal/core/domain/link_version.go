@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// LinkSettings are the parts of a link its owner configures: where it goes,
+// how it routes visitors and how it is described. Status, folder, tags and
+// owner are not settings, so restoring a version never lifts a block or
+// moves a link.
+type LinkSettings struct {
+	TargetURL    string        `json:"target_url"`
+	Rules        []RoutingRule `json:"rules,omitempty"`
+	Variants     []Variant     `json:"variants,omitempty"`
+	ForwardPath  bool          `json:"forward_path,omitempty"`
+	QueryOptions QueryOptions  `json:"query_options,omitzero"`
+	Schedule     LinkSchedule  `json:"schedule,omitzero"`
+	MaxUses      *int          `json:"max_uses,omitempty"`
+	Interstitial Interstitial  `json:"interstitial,omitzero"`
+
+	LinkMetadata
+}
+
+// Settings returns the current settings of the link.
+func (l Link) Settings() LinkSettings {
+	return LinkSettings{
+		TargetURL:    l.TargetURL,
+		Rules:        l.Rules,
+		Variants:     l.Variants,
+		ForwardPath:  l.ForwardPath,
+		QueryOptions: l.QueryOptions,
+		Schedule:     l.Schedule,
+		MaxUses:      l.MaxUses,
+		Interstitial: l.Interstitial,
+		LinkMetadata: l.LinkMetadata,
+	}
+}
+
+// WithSettings returns a copy of the link with its settings replaced. A
+// changed use limit resets the uses left, like setting it directly does.
+func (l Link) WithSettings(s LinkSettings) Link {
+	if !sameLimit(l.MaxUses, s.MaxUses) {
+		l.UsesLeft = nil
+		if s.MaxUses != nil {
+			maxUses, usesLeft := *s.MaxUses, *s.MaxUses
+			s.MaxUses, l.UsesLeft = &maxUses, &usesLeft
+		}
+	}
+	l.TargetURL = s.TargetURL
+	l.TargetHash = TargetHash(s.TargetURL)
+	l.Rules = s.Rules
+	l.Variants = s.Variants
+	l.ForwardPath = s.ForwardPath
+	l.QueryOptions = s.QueryOptions
+	l.Schedule = s.Schedule
+	l.MaxUses = s.MaxUses
+	l.Interstitial = s.Interstitial
+	l.LinkMetadata = s.LinkMetadata
+	return l
+}
+
+func sameLimit(a *int, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// SameSettings reports whether two versions of a link have the same
+// settings, comparing them as the API shows them so that a nil and an empty
+// list are equal.
+func SameSettings(a Link, b Link) bool {
+	rawA, errA := json.Marshal(a.Settings())
+	rawB, errB := json.Marshal(b.Settings())
+	return errA == nil && errB == nil && bytes.Equal(rawA, rawB)
+}
+
+// LinkVersion is a previous set of settings of a link, kept when a change
+// replaced them. Versions are numbered from 1 per link; ReplacedAt is when
+// the link stopped using them.
+type LinkVersion struct {
+	Number     int          `json:"number" example:"3"`
+	Settings   LinkSettings `json:"settings"`
+	ChangeID   string       `json:"change_id"`
+	ReplacedAt time.Time    `json:"replaced_at"`
+}
+
+type ScheduledChangeStatus string
+
+const (
+	ScheduledPending ScheduledChangeStatus = "PENDING"
+	// ScheduledRunning is set while an instance applies the change; a claim
+	// older than ScheduledClaimTimeout is taken over by another instance.
+	ScheduledRunning  ScheduledChangeStatus = "RUNNING"
+	ScheduledDone     ScheduledChangeStatus = "DONE"
+	ScheduledFailed   ScheduledChangeStatus = "FAILED"
+	ScheduledCanceled ScheduledChangeStatus = "CANCELED"
+)
+
+// ScheduledClaimTimeout is how long a scheduled change can stay RUNNING
+// before another instance assumes the one running it died and takes it over.
+const ScheduledClaimTimeout = 5 * time.Minute
+
+// ScheduledChange switches a link to another target, or restores one of its
+// versions, at RunAt. Exactly one of TargetURL and Version is set.
+type ScheduledChange struct {
+	ID         string                `json:"id"`
+	LinkID     string                `json:"link_id"`
+	Domain     string                `json:"domain,omitempty"`
+	ShortID    string                `json:"short_id"`
+	RunAt      time.Time             `json:"run_at" example:"2026-03-06T09:00:00Z"`
+	TargetURL  string                `json:"target_url,omitempty" example:"https://example.com/spring-sale"`
+	Version    int                   `json:"version,omitempty"`
+	Status     ScheduledChangeStatus `json:"status" example:"PENDING"`
+	Error      string                `json:"error,omitempty"`
+	CreatedBy  string                `json:"created_by"`
+	CreatedAt  time.Time             `json:"created_at"`
+	ExecutedAt *time.Time            `json:"executed_at,omitempty"`
+	// ClaimedAt identifies the instance's claim on a RUNNING change; it can
+	// only finish the change while the claim is still its own.
+	ClaimedAt *time.Time `json:"-"`
+}
+
+// ScheduledChangeInput is what an owner schedules. RunAt carries its own
+// offset, so "Friday 09:00" is in whichever time zone the caller means.
+type ScheduledChangeInput struct {
+	RunAt     time.Time `json:"run_at" example:"2026-03-06T09:00:00+01:00"`
+	TargetURL string    `json:"target_url,omitempty" example:"https://example.com/spring-sale"`
+	Version   int       `json:"version,omitempty"`
+}
+
+// MaxScheduleAhead bounds how far in the future a change can be scheduled.
+const MaxScheduleAhead = 366 * 24 * time.Hour
+
+// Normalize trims the target, stores RunAt in UTC and checks that exactly one
+// change is requested, at a time after now.
+func (in ScheduledChangeInput) Normalize(now time.Time) (ScheduledChangeInput, error) {
+	in.TargetURL = strings.TrimSpace(in.TargetURL)
+	in.RunAt = in.RunAt.UTC()
+
+	switch {
+	case (in.TargetURL == "") == (in.Version == 0):
+		return in, fmt.Errorf("%w: exactly one of target_url or version is required", ErrInvalidScheduledChange)
+	case in.Version < 0:
+		return in, fmt.Errorf("%w: version must be positive", ErrInvalidScheduledChange)
+	case in.TargetURL != "" && !isHTTPURL(in.TargetURL):
+		return in, fmt.Errorf("%w: target_url must be an absolute http or https URL", ErrInvalidScheduledChange)
+	case !in.RunAt.After(now):
+		return in, fmt.Errorf("%w: run_at must be in the future", ErrInvalidScheduledChange)
+	case in.RunAt.After(now.Add(MaxScheduleAhead)):
+		return in, fmt.Errorf("%w: run_at must be within a year", ErrInvalidScheduledChange)
+	}
+	return in, nil
+}
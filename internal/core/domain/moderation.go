@@ -0,0 +1,213 @@
+package domain
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxReportDetailsLength  = 1000
+	maxReportEmailLength    = 254
+	maxModerationNoteLength = 1000
+)
+
+type ReportReason string
+
+const (
+	ReportPhishing ReportReason = "PHISHING"
+	ReportMalware  ReportReason = "MALWARE"
+	ReportSpam     ReportReason = "SPAM"
+	ReportIllegal  ReportReason = "ILLEGAL"
+	ReportOther    ReportReason = "OTHER"
+)
+
+var reportReasons = map[ReportReason]struct{}{
+	ReportPhishing: {},
+	ReportMalware:  {},
+	ReportSpam:     {},
+	ReportIllegal:  {},
+	ReportOther:    {},
+}
+
+// ReportStatus is where an abuse report is in the moderation queue. Open
+// reports are waiting for a moderator; actioned ones led to the link being
+// blocked.
+type ReportStatus string
+
+const (
+	ReportOpen      ReportStatus = "OPEN"
+	ReportDismissed ReportStatus = "DISMISSED"
+	ReportActioned  ReportStatus = "ACTIONED"
+)
+
+// AbuseReport is a visitor's complaint about a link. TargetURL is the link's
+// target when it was reported, since the owner may change it afterwards.
+type AbuseReport struct {
+	ID         string       `json:"id" db:"id"`
+	LinkID     string       `json:"link_id" db:"link_id"`
+	Domain     string       `json:"domain,omitempty" db:"domain"`
+	ShortID    string       `json:"short_id" db:"short_id"`
+	TargetURL  string       `json:"target_url" db:"target_url"`
+	Reason     ReportReason `json:"reason" db:"reason"`
+	Details    string       `json:"details,omitempty" db:"details"`
+	Email      string       `json:"email,omitempty" db:"email"`
+	Status     ReportStatus `json:"status" db:"status"`
+	CreatedAt  time.Time    `json:"created_at" db:"created_at"`
+	ReviewedBy *string      `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewedAt *time.Time   `json:"reviewed_at,omitempty" db:"reviewed_at"`
+}
+
+// ReportInput is what a visitor submits when reporting a link. ReporterIP is
+// only used to throttle repeated reports and is not stored.
+type ReportInput struct {
+	Reason     ReportReason
+	Details    string
+	Email      string
+	ReporterIP string
+}
+
+// Normalize trims the input, upper-cases the reason and checks every field.
+func (in ReportInput) Normalize() (ReportInput, error) {
+	in.Reason = ReportReason(strings.ToUpper(strings.TrimSpace(string(in.Reason))))
+	in.Details = strings.TrimSpace(in.Details)
+	in.Email = strings.TrimSpace(in.Email)
+
+	if _, ok := reportReasons[in.Reason]; !ok {
+		return ReportInput{}, fmt.Errorf("%w: reason must be one of PHISHING, MALWARE, SPAM, ILLEGAL or OTHER", ErrInvalidReport)
+	}
+	if utf8.RuneCountInString(in.Details) > maxReportDetailsLength {
+		return ReportInput{}, fmt.Errorf("%w: details must be at most %d characters", ErrInvalidReport, maxReportDetailsLength)
+	}
+	if in.Email != "" {
+		addr, err := mail.ParseAddress(in.Email)
+		if err != nil || addr.Address != in.Email || len(in.Email) > maxReportEmailLength {
+			return ReportInput{}, fmt.Errorf("%w: invalid email address", ErrInvalidReport)
+		}
+	}
+	return in, nil
+}
+
+// ReportFilter narrows the moderation queue. An empty Status lists open
+// reports; LinkID limits it to one link's reports.
+type ReportFilter struct {
+	Status ReportStatus
+	LinkID string
+	Limit  int
+	Offset int
+}
+
+type ReportPage struct {
+	Reports []AbuseReport `json:"reports"`
+	Total   int           `json:"total"`
+}
+
+// ReviewAction is a moderator's decision on an open report.
+type ReviewAction string
+
+const (
+	// ReviewDismiss closes the report without touching the link.
+	ReviewDismiss ReviewAction = "dismiss"
+	// ReviewBlock blocks the link and closes every open report on it.
+	ReviewBlock ReviewAction = "block"
+)
+
+type ReportReview struct {
+	Action ReviewAction
+	Note   string
+}
+
+// Normalize checks the action and the length of the note.
+func (r ReportReview) Normalize() (ReportReview, error) {
+	r.Action = ReviewAction(strings.ToLower(strings.TrimSpace(string(r.Action))))
+	if r.Action != ReviewDismiss && r.Action != ReviewBlock {
+		return ReportReview{}, fmt.Errorf("%w: action must be dismiss or block", ErrInvalidReview)
+	}
+	note, err := NormalizeModerationNote(r.Note)
+	if err != nil {
+		return ReportReview{}, err
+	}
+	r.Note = note
+	return r, nil
+}
+
+// NormalizeModerationNote trims a moderator's note and checks its length.
+func NormalizeModerationNote(note string) (string, error) {
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > maxModerationNoteLength {
+		return "", fmt.Errorf("%w: note must be at most %d characters", ErrInvalidReview, maxModerationNoteLength)
+	}
+	return note, nil
+}
+
+// UserBan keeps a user from using the API. Banning can also block every link
+// the user owns; unbanning leaves those links blocked.
+type UserBan struct {
+	UserID    string    `json:"user_id" db:"user_id"`
+	Reason    string    `json:"reason,omitempty" db:"reason"`
+	BannedBy  string    `json:"banned_by" db:"banned_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// ModerationActionType names what a moderator did.
+type ModerationActionType string
+
+const (
+	ActionDismissReport ModerationActionType = "DISMISS_REPORT"
+	ActionBlockLink     ModerationActionType = "BLOCK_LINK"
+	ActionPauseLink     ModerationActionType = "PAUSE_LINK"
+	ActionActivateLink  ModerationActionType = "ACTIVATE_LINK"
+	ActionDeleteLink    ModerationActionType = "DELETE_LINK"
+	ActionFlagLink      ModerationActionType = "FLAG_LINK"
+	ActionUnflagLink    ModerationActionType = "UNFLAG_LINK"
+	ActionBanUser       ModerationActionType = "BAN_USER"
+	ActionUnbanUser     ModerationActionType = "UNBAN_USER"
+)
+
+// linkStatusActions maps the status a moderator sets on a link to the action
+// recorded for it.
+var linkStatusActions = map[LinkStatus]ModerationActionType{
+	StatusActive:  ActionActivateLink,
+	StatusPaused:  ActionPauseLink,
+	StatusBlocked: ActionBlockLink,
+}
+
+// LinkStatusAction returns the audit log action for setting a link's status,
+// or ErrInvalidLinkStatus.
+func LinkStatusAction(status LinkStatus) (ModerationActionType, error) {
+	action, ok := linkStatusActions[status]
+	if !ok {
+		return "", ErrInvalidLinkStatus
+	}
+	return action, nil
+}
+
+// ModerationAction is one entry of the moderation audit log. It is written in
+// the same transaction as the change it records and never updated.
+type ModerationAction struct {
+	ID          string               `json:"id" db:"id"`
+	ModeratorID string               `json:"moderator_id" db:"moderator_id"`
+	Action      ModerationActionType `json:"action" db:"action"`
+	LinkID      *string              `json:"link_id,omitempty" db:"link_id"`
+	UserID      *string              `json:"user_id,omitempty" db:"user_id"`
+	ReportID    *string              `json:"report_id,omitempty" db:"report_id"`
+	Note        string               `json:"note,omitempty" db:"note"`
+	CreatedAt   time.Time            `json:"created_at" db:"created_at"`
+}
+
+// ModerationActionFilter narrows the audit log; empty fields match every
+// entry.
+type ModerationActionFilter struct {
+	ModeratorID string
+	LinkID      string
+	UserID      string
+	Limit       int
+	Offset      int
+}
+
+type ModerationActionPage struct {
+	Actions []ModerationAction `json:"actions"`
+	Total   int                `json:"total"`
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+type QRFormat string
+
+const (
+	QRFormatPNG QRFormat = "png"
+	QRFormatSVG QRFormat = "svg"
+)
+
+func (f QRFormat) ContentType() string {
+	if f == QRFormatSVG {
+		return "image/svg+xml"
+	}
+	return "image/png"
+}
+
+// QRErrorCorrection is the QR recovery level: L (7%), M (15%), Q (25%) or
+// H (30%) of the code can be damaged or covered and still decode.
+type QRErrorCorrection string
+
+const (
+	QRErrorCorrectionL QRErrorCorrection = "L"
+	QRErrorCorrectionM QRErrorCorrection = "M"
+	QRErrorCorrectionQ QRErrorCorrection = "Q"
+	QRErrorCorrectionH QRErrorCorrection = "H"
+)
+
+const (
+	DefaultQRSize   = 512
+	MinQRSize       = 64
+	MaxQRSize       = 2048
+	DefaultQRMargin = 4
+	MaxQRMargin     = 16
+)
+
+// QROptions controls how a link's QR code is rendered. Size is the image
+// width and height in pixels and Margin the quiet zone in modules.
+type QROptions struct {
+	Format          QRFormat
+	Size            int
+	Margin          int
+	ErrorCorrection QRErrorCorrection
+	Foreground      color.RGBA
+	Background      color.RGBA
+	// LogoURL is an optional PNG, JPEG or GIF drawn over the center of the
+	// code. A logo forces error correction H so the code still decodes.
+	LogoURL string
+}
+
+// DefaultQROptions renders a black-on-white 512px PNG with a 4-module margin.
+func DefaultQROptions() QROptions {
+	return QROptions{
+		Format:          QRFormatPNG,
+		Size:            DefaultQRSize,
+		Margin:          DefaultQRMargin,
+		ErrorCorrection: QRErrorCorrectionM,
+		Foreground:      color.RGBA{A: 0xff},
+		Background:      color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+	}
+}
+
+func (o QROptions) Validate() error {
+	switch {
+	case o.Format != QRFormatPNG && o.Format != QRFormatSVG:
+		return fmt.Errorf("%w: format must be png or svg", ErrInvalidQROptions)
+	case o.Size < MinQRSize || o.Size > MaxQRSize:
+		return fmt.Errorf("%w: size must be between %d and %d", ErrInvalidQROptions, MinQRSize, MaxQRSize)
+	case o.Margin < 0 || o.Margin > MaxQRMargin:
+		return fmt.Errorf("%w: margin must be between 0 and %d", ErrInvalidQROptions, MaxQRMargin)
+	}
+	switch o.ErrorCorrection {
+	case QRErrorCorrectionL, QRErrorCorrectionM, QRErrorCorrectionQ, QRErrorCorrectionH:
+	default:
+		return fmt.Errorf("%w: ecc must be L, M, Q or H", ErrInvalidQROptions)
+	}
+	return nil
+}
+
+// Fingerprint identifies the image rendered for content with these options,
+// for use as an ETag and cache key.
+func (o QROptions) Fingerprint(content string) string {
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s\x00%s\x00%d\x00%d\x00%s\x00%s\x00%s\x00%s",
+		content, o.Format, o.Size, o.Margin, o.ErrorCorrection, HexColor(o.Foreground), HexColor(o.Background), o.LogoURL))
+	return hex.EncodeToString(sum[:16])
+}
+
+// ParseHexColor reads "#rrggbb", "rrggbb" or the short "#rgb" form.
+func ParseHexColor(raw string) (color.RGBA, error) {
+	hexStr := strings.TrimPrefix(strings.TrimSpace(raw), "#")
+	if len(hexStr) == 3 {
+		hexStr = string([]byte{hexStr[0], hexStr[0], hexStr[1], hexStr[1], hexStr[2], hexStr[2]})
+	}
+	if len(hexStr) != 6 {
+		return color.RGBA{}, fmt.Errorf("%w: colors must be hex values like #1a2b3c", ErrInvalidQROptions)
+	}
+	v, err := strconv.ParseUint(hexStr, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("%w: colors must be hex values like #1a2b3c", ErrInvalidQROptions)
+	}
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
+
+func HexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
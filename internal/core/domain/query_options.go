@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// QueryPolicy decides which value wins when a forwarded query parameter is
+// already present on the target.
+type QueryPolicy string
+
+const (
+	// QueryPolicyTarget keeps the target's value and drops the incoming one.
+	QueryPolicyTarget QueryPolicy = "target"
+	// QueryPolicyIncoming replaces the target's value with the incoming one.
+	QueryPolicyIncoming QueryPolicy = "incoming"
+	// QueryPolicyAppend keeps both, target values first.
+	QueryPolicyAppend QueryPolicy = "append"
+)
+
+const maxUTMLength = 255
+
+// UTMParams are campaign parameters added to the target on redirect.
+type UTMParams struct {
+	Source   string `json:"source,omitempty" example:"newsletter"`
+	Medium   string `json:"medium,omitempty" example:"email"`
+	Campaign string `json:"campaign,omitempty" example:"spring_sale"`
+	Term     string `json:"term,omitempty"`
+	Content  string `json:"content,omitempty" example:"header_button"`
+}
+
+func (u UTMParams) pairs() [][2]string {
+	return [][2]string{
+		{"utm_source", u.Source},
+		{"utm_medium", u.Medium},
+		{"utm_campaign", u.Campaign},
+		{"utm_term", u.Term},
+		{"utm_content", u.Content},
+	}
+}
+
+// QueryOptions control the query string of the URL a visitor is sent to.
+// UTM values always replace the target's own utm_ parameters. With
+// ForwardQuery, the short URL's query string is merged in afterwards and
+// conflicts, including with the UTM values, are settled by Policy.
+type QueryOptions struct {
+	UTM          UTMParams   `json:"utm,omitzero"`
+	ForwardQuery bool        `json:"forward_query,omitempty"`
+	Policy       QueryPolicy `json:"policy,omitempty" enums:"target,incoming,append"`
+}
+
+// IsZero reports whether the options leave the target unchanged.
+func (o QueryOptions) IsZero() bool {
+	return o == QueryOptions{}
+}
+
+// Normalize trims the UTM values, defaults Policy to QueryPolicyTarget when
+// forwarding is enabled and validates both.
+func (o QueryOptions) Normalize() (QueryOptions, error) {
+	o.UTM = UTMParams{
+		Source:   strings.TrimSpace(o.UTM.Source),
+		Medium:   strings.TrimSpace(o.UTM.Medium),
+		Campaign: strings.TrimSpace(o.UTM.Campaign),
+		Term:     strings.TrimSpace(o.UTM.Term),
+		Content:  strings.TrimSpace(o.UTM.Content),
+	}
+	for _, p := range o.UTM.pairs() {
+		if utf8.RuneCountInString(p[1]) > maxUTMLength {
+			return QueryOptions{}, fmt.Errorf("%w: %s is longer than %d characters", ErrInvalidQueryOptions, p[0], maxUTMLength)
+		}
+	}
+
+	if !o.ForwardQuery {
+		o.Policy = ""
+		return o, nil
+	}
+	switch o.Policy {
+	case "":
+		o.Policy = QueryPolicyTarget
+	case QueryPolicyTarget, QueryPolicyIncoming, QueryPolicyAppend:
+	default:
+		return QueryOptions{}, fmt.Errorf("%w: policy must be target, incoming or append", ErrInvalidQueryOptions)
+	}
+	return o, nil
+}
+
+// Apply returns target with the UTM values set and, when forwarding is on,
+// the incoming raw query merged in. The target is returned unchanged when
+// there is nothing to add or it cannot be parsed.
+func (o QueryOptions) Apply(target string, incoming string) string {
+	forward := o.ForwardQuery && incoming != ""
+	if o.UTM == (UTMParams{}) && !forward {
+		return target
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return target
+	}
+	q := u.Query()
+	for _, p := range o.UTM.pairs() {
+		if p[1] != "" {
+			q.Set(p[0], p[1])
+		}
+	}
+
+	if forward {
+		in, _ := url.ParseQuery(incoming)
+		for key, values := range in {
+			_, exists := q[key]
+			switch {
+			case !exists || o.Policy == QueryPolicyIncoming:
+				q[key] = values
+			case o.Policy == QueryPolicyAppend:
+				q[key] = append(q[key], values...)
+			}
+		}
+	}
+
+	u.RawQuery = q.Encode()
+	return u.String()
+}
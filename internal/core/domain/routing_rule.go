@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// MaxRoutingRules caps how many rules a link can carry; they are evaluated on
+// every resolve and cached with the link.
+const MaxRoutingRules = 20
+
+// Operating systems and device types recognized in User-Agent strings.
+const (
+	OSiOS      = "ios"
+	OSAndroid  = "android"
+	OSWindows  = "windows"
+	OSMacOS    = "macos"
+	OSLinux    = "linux"
+	OSChromeOS = "chromeos"
+
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceDesktop = "desktop"
+)
+
+var (
+	knownOSes    = map[string]struct{}{OSiOS: {}, OSAndroid: {}, OSWindows: {}, OSMacOS: {}, OSLinux: {}, OSChromeOS: {}}
+	knownDevices = map[string]struct{}{DeviceMobile: {}, DeviceTablet: {}, DeviceDesktop: {}}
+)
+
+// RoutingRule sends visitors matching every non-empty condition to TargetURL.
+// Country is an ISO 3166-1 alpha-2 code.
+type RoutingRule struct {
+	OS        string `json:"os,omitempty" example:"ios"`
+	Device    string `json:"device,omitempty" example:"mobile"`
+	Country   string `json:"country,omitempty" example:"DE"`
+	TargetURL string `json:"target_url" example:"https://apps.apple.com/app/id123456789"`
+}
+
+// VisitorProfile is what routing rules are matched against.
+type VisitorProfile struct {
+	OS      string
+	Device  string
+	Country string
+}
+
+func (r RoutingRule) matches(p VisitorProfile) bool {
+	if r.OS != "" && r.OS != p.OS {
+		return false
+	}
+	if r.Device != "" && r.Device != p.Device {
+		return false
+	}
+	if r.Country != "" && r.Country != p.Country {
+		return false
+	}
+	return true
+}
+
+// NormalizeRoutingRules canonicalizes the case of rule conditions and checks that every rule
+// has at least one known condition and an absolute http or https target.
+func NormalizeRoutingRules(rules []RoutingRule) ([]RoutingRule, error) {
+	if len(rules) > MaxRoutingRules {
+		return nil, fmt.Errorf("%w: at most %d rules are allowed", ErrInvalidRoutingRule, MaxRoutingRules)
+	}
+	out := make([]RoutingRule, len(rules))
+	for i, rule := range rules {
+		rule.OS = strings.ToLower(strings.TrimSpace(rule.OS))
+		rule.Device = strings.ToLower(strings.TrimSpace(rule.Device))
+		rule.Country = strings.ToUpper(strings.TrimSpace(rule.Country))
+		rule.TargetURL = strings.TrimSpace(rule.TargetURL)
+
+		if rule.OS == "" && rule.Device == "" && rule.Country == "" {
+			return nil, fmt.Errorf("%w: rule %d has no condition", ErrInvalidRoutingRule, i)
+		}
+		if _, ok := knownOSes[rule.OS]; rule.OS != "" && !ok {
+			return nil, fmt.Errorf("%w: rule %d has unknown os %q", ErrInvalidRoutingRule, i, rule.OS)
+		}
+		if _, ok := knownDevices[rule.Device]; rule.Device != "" && !ok {
+			return nil, fmt.Errorf("%w: rule %d has unknown device %q", ErrInvalidRoutingRule, i, rule.Device)
+		}
+		if rule.Country != "" && NormalizeCountry(rule.Country) == "" {
+			return nil, fmt.Errorf("%w: rule %d country must be a two-letter ISO code", ErrInvalidRoutingRule, i)
+		}
+		if !isHTTPURL(rule.TargetURL) {
+			return nil, fmt.Errorf("%w: rule %d target must be an absolute http or https URL", ErrInvalidRoutingRule, i)
+		}
+		out[i] = rule
+	}
+	return out, nil
+}
+
+// RouteTarget returns the target of the first rule matching the visitor and
+// that rule's index, or the default target and -1 when none matches.
+func RouteTarget(defaultTarget string, rules []RoutingRule, p VisitorProfile) (string, int) {
+	for i, rule := range rules {
+		if rule.matches(p) {
+			return rule.TargetURL, i
+		}
+	}
+	return defaultTarget, -1
+}
+
+// Profile returns what the rules are matched against for this visitor.
+func (v Visitor) Profile() VisitorProfile {
+	p := ParseUserAgent(v.UserAgent)
+	p.Country = v.Country
+	return p
+}
+
+// ParseUserAgent detects the operating system and device type of a browser
+// User-Agent. Unrecognized values are left empty, so such visitors only match
+// rules without that condition.
+func ParseUserAgent(userAgent string) VisitorProfile {
+	ua := strings.ToLower(userAgent)
+	if ua == "" {
+		return VisitorProfile{}
+	}
+
+	var p VisitorProfile
+	switch {
+	case strings.Contains(ua, "iphone") || strings.Contains(ua, "ipad") || strings.Contains(ua, "ipod"):
+		p.OS = OSiOS
+	case strings.Contains(ua, "android"):
+		p.OS = OSAndroid
+	case strings.Contains(ua, "windows"):
+		p.OS = OSWindows
+	case strings.Contains(ua, "cros "):
+		p.OS = OSChromeOS
+	case strings.Contains(ua, "macintosh") || strings.Contains(ua, "mac os x"):
+		p.OS = OSMacOS
+	case strings.Contains(ua, "linux"):
+		p.OS = OSLinux
+	}
+
+	switch {
+	case strings.Contains(ua, "ipad") || strings.Contains(ua, "tablet") ||
+		(p.OS == OSAndroid && !strings.Contains(ua, "mobile")):
+		p.Device = DeviceTablet
+	case strings.Contains(ua, "mobi") || strings.Contains(ua, "iphone") || strings.Contains(ua, "ipod"):
+		p.Device = DeviceMobile
+	case p.OS != "":
+		p.Device = DeviceDesktop
+	}
+	return p
+}
+
+// NormalizeCountry returns an uppercase ISO 3166-1 alpha-2 code, or an empty
+// string for anything else, including the "XX" and "T1" (Tor) placeholders
+// CDNs send when the country is unknown.
+func NormalizeCountry(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 2 || code[0] < 'A' || code[0] > 'Z' || code[1] < 'A' || code[1] > 'Z' {
+		return ""
+	}
+	if code == "XX" {
+		return ""
+	}
+	return code
+}
+
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SchedulePhase is where a moment falls relative to a link's schedule.
+type SchedulePhase int
+
+const (
+	ScheduleLive SchedulePhase = iota
+	ScheduleNotLive
+	ScheduleEnded
+)
+
+// LinkSchedule limits when a link sends visitors to its targets. Before
+// ActiveFrom they go to NotLiveURL and from ActiveUntil on to EndedURL; when
+// those are empty the link does not resolve. Either bound may be nil.
+type LinkSchedule struct {
+	ActiveFrom  *time.Time `json:"active_from,omitempty" example:"2026-03-01T09:00:00Z"`
+	ActiveUntil *time.Time `json:"active_until,omitempty" example:"2026-03-31T23:59:59Z"`
+	NotLiveURL  string     `json:"not_live_url,omitempty" example:"https://example.com/coming-soon"`
+	EndedURL    string     `json:"ended_url,omitempty" example:"https://example.com/sale-ended"`
+}
+
+// IsZero reports whether the link is live at all times.
+func (s LinkSchedule) IsZero() bool {
+	return s.ActiveFrom == nil && s.ActiveUntil == nil
+}
+
+// Normalize trims the fallback URLs, stores the bounds in UTC and checks
+// that the window is not empty and each fallback URL has its bound.
+func (s LinkSchedule) Normalize() (LinkSchedule, error) {
+	s.NotLiveURL = strings.TrimSpace(s.NotLiveURL)
+	s.EndedURL = strings.TrimSpace(s.EndedURL)
+
+	if s.ActiveFrom != nil {
+		from := s.ActiveFrom.UTC()
+		s.ActiveFrom = &from
+	}
+	if s.ActiveUntil != nil {
+		until := s.ActiveUntil.UTC()
+		s.ActiveUntil = &until
+	}
+	if s.ActiveFrom != nil && s.ActiveUntil != nil && !s.ActiveUntil.After(*s.ActiveFrom) {
+		return LinkSchedule{}, fmt.Errorf("%w: active_until must be after active_from", ErrInvalidSchedule)
+	}
+
+	if s.NotLiveURL != "" {
+		if s.ActiveFrom == nil {
+			return LinkSchedule{}, fmt.Errorf("%w: not_live_url needs active_from", ErrInvalidSchedule)
+		}
+		if !isHTTPURL(s.NotLiveURL) {
+			return LinkSchedule{}, fmt.Errorf("%w: not_live_url must be an absolute http or https URL", ErrInvalidSchedule)
+		}
+	}
+	if s.EndedURL != "" {
+		if s.ActiveUntil == nil {
+			return LinkSchedule{}, fmt.Errorf("%w: ended_url needs active_until", ErrInvalidSchedule)
+		}
+		if !isHTTPURL(s.EndedURL) {
+			return LinkSchedule{}, fmt.Errorf("%w: ended_url must be an absolute http or https URL", ErrInvalidSchedule)
+		}
+	}
+	return s, nil
+}
+
+// Phase reports whether the link is live at now. ActiveFrom is inclusive and
+// ActiveUntil exclusive.
+func (s LinkSchedule) Phase(now time.Time) SchedulePhase {
+	switch {
+	case s.ActiveFrom != nil && now.Before(*s.ActiveFrom):
+		return ScheduleNotLive
+	case s.ActiveUntil != nil && !now.Before(*s.ActiveUntil):
+		return ScheduleEnded
+	}
+	return ScheduleLive
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxTagNameLength    = 50
+	maxFolderNameLength = 100
+)
+
+type Tag struct {
+	ID        string    `json:"id" db:"id"`
+	UserID    string    `json:"user_id" db:"user_id"`
+	Name      string    `json:"name" db:"name"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// TagStats aggregates the links carrying a tag.
+type TagStats struct {
+	Tag
+	LinkCount int   `json:"link_count"`
+	Clicks    int64 `json:"clicks"`
+}
+
+type Folder struct {
+	ID        string    `json:"id" db:"id"`
+	UserID    string    `json:"user_id" db:"user_id"`
+	Name      string    `json:"name" db:"name"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// NormalizeTagName trims a tag name and checks its length. Tag names are
+// unique per user, ignoring case.
+func NormalizeTagName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxTagNameLength {
+		return "", ErrInvalidTagName
+	}
+	return name, nil
+}
+
+// NormalizeFolderName trims a folder name and checks its length.
+func NormalizeFolderName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxFolderNameLength {
+		return "", ErrInvalidFolderName
+	}
+	return name, nil
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strings"
+)
+
+// NormalizeTargetURL returns a canonical form of a target URL so equivalent
+// spellings compare equal: scheme and host are lowercased, default ports,
+// fragments and a bare trailing slash are dropped, and query parameters are
+// sorted. Unparseable input is returned trimmed but otherwise unchanged.
+func NormalizeTargetURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return raw
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	if port := u.Port(); port != "" && !(u.Scheme == "http" && port == "80") && !(u.Scheme == "https" && port == "443") {
+		host += ":" + port
+	}
+	u.Host = host
+	u.Fragment = ""
+	u.RawFragment = ""
+	if u.Path == "/" {
+		u.Path = ""
+		u.RawPath = ""
+	}
+	if u.RawQuery != "" {
+		u.RawQuery = u.Query().Encode()
+	}
+	return u.String()
+}
+
+// TargetHash is the hex SHA-256 of the normalized target URL, used to find a
+// user's existing link for the same destination.
+func TargetHash(raw string) string {
+	sum := sha256.Sum256([]byte(NormalizeTargetURL(raw)))
+	return hex.EncodeToString(sum[:])
+}
+
+// TargetHost returns the lowercased hostname of a target URL, without port,
+// or an empty string when it cannot be parsed.
+func TargetHost(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+}
+
+// IsForwardablePath reports whether a path can be appended to a prefix
+// link's target. Paths with "." or ".." segments are rejected so they cannot
+// climb above the target path.
+func IsForwardablePath(rest string) bool {
+	for _, seg := range strings.Split(strings.Trim(rest, "/"), "/") {
+		if seg == "." || seg == ".." {
+			return false
+		}
+	}
+	return true
+}
+
+// JoinTargetPath appends the rest of a forwarded short URL path to the
+// target's path, keeping the target's query and fragment.
+func JoinTargetPath(target string, rest string) (string, bool) {
+	rest = strings.Trim(rest, "/")
+	if rest == "" {
+		return target, true
+	}
+	if !IsForwardablePath(rest) {
+		return "", false
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return "", false
+	}
+	return u.JoinPath(rest).String(), true
+}
+
+// HasTargetPrefix reports whether the normalized target lies at or under the
+// normalized prefix. A prefix only matches at a path, query or host boundary,
+// so "https://a.com/doc" does not match "https://a.com/docs".
+func HasTargetPrefix(target string, prefix string) bool {
+	target, prefix = NormalizeTargetURL(target), NormalizeTargetURL(prefix)
+	if !strings.HasPrefix(target, prefix) {
+		return false
+	}
+	if len(target) == len(prefix) || strings.HasSuffix(prefix, "/") {
+		return true
+	}
+	switch target[len(prefix)] {
+	case '/', '?', '#':
+		return true
+	}
+	return false
+}
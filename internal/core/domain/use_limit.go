@@ -0,0 +1,20 @@
+package domain
+
+import "fmt"
+
+// MaxLinkUses caps the use limit of a link; limited links are meant for
+// handing out a URL to a few people, not for campaigns.
+const MaxLinkUses = 10000
+
+// ValidateMaxUses checks a link's use limit, where 0 means unlimited.
+func ValidateMaxUses(maxUses int) error {
+	if maxUses < 0 || maxUses > MaxLinkUses {
+		return fmt.Errorf("%w: max_uses must be between 1 and %d, or 0 for no limit", ErrInvalidMaxUses, MaxLinkUses)
+	}
+	return nil
+}
+
+// IsLimited reports whether the link stops resolving after a number of uses.
+func (l Link) IsLimited() bool {
+	return l.MaxUses != nil
+}
@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+)
+
+const (
+	MaxVariants      = 10
+	MaxVariantWeight = 1000
+	maxVariantIDLen  = 32
+)
+
+// Variant is one destination of an A/B split. Visitors are spread across a
+// link's variants in proportion to Weight. ID is chosen by the owner so it
+// can be put in the variant's own target URL and reported back with
+// conversions.
+type Variant struct {
+	ID        string `json:"id" example:"b"`
+	TargetURL string `json:"target_url" example:"https://example.com/landing-b"`
+	Weight    int    `json:"weight" example:"30"`
+}
+
+// VariantStats compares the variants of a split test. Clicks and
+// Conversions count every recorded event for the variant ID, including ones
+// from before its target or weight last changed.
+type VariantStats struct {
+	ID             string  `json:"id"`
+	TargetURL      string  `json:"target_url"`
+	Weight         int     `json:"weight"`
+	Clicks         int     `json:"clicks"`
+	Conversions    int     `json:"conversions"`
+	ConversionRate float64 `json:"conversion_rate"`
+}
+
+// NormalizeVariants trims variant fields and checks IDs are unique slugs,
+// weights are positive and targets are absolute http or https URLs.
+func NormalizeVariants(variants []Variant) ([]Variant, error) {
+	if len(variants) > MaxVariants {
+		return nil, fmt.Errorf("%w: at most %d variants are allowed", ErrInvalidVariant, MaxVariants)
+	}
+	if len(variants) == 1 {
+		return nil, fmt.Errorf("%w: a split test needs at least two variants", ErrInvalidVariant)
+	}
+	seen := make(map[string]struct{}, len(variants))
+	out := make([]Variant, len(variants))
+	for i, v := range variants {
+		v.ID = strings.TrimSpace(v.ID)
+		v.TargetURL = strings.TrimSpace(v.TargetURL)
+
+		if !isVariantID(v.ID) {
+			return nil, fmt.Errorf("%w: variant %d id must be 1 to %d letters, digits, '-' or '_'", ErrInvalidVariant, i, maxVariantIDLen)
+		}
+		if _, dup := seen[v.ID]; dup {
+			return nil, fmt.Errorf("%w: variant id %q is used twice", ErrInvalidVariant, v.ID)
+		}
+		seen[v.ID] = struct{}{}
+		if v.Weight < 1 || v.Weight > MaxVariantWeight {
+			return nil, fmt.Errorf("%w: variant %q weight must be between 1 and %d", ErrInvalidVariant, v.ID, MaxVariantWeight)
+		}
+		if !isHTTPURL(v.TargetURL) {
+			return nil, fmt.Errorf("%w: variant %q target must be an absolute http or https URL", ErrInvalidVariant, v.ID)
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+func isVariantID(id string) bool {
+	if id == "" || len(id) > maxVariantIDLen {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z') && !(r >= 'A' && r <= 'Z') && !(r >= '0' && r <= '9') && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// PickVariant chooses a variant by weight using a hash of key, so the same
+// key always lands on the same variant while the weights stay the same.
+func PickVariant(variants []Variant, key string) (Variant, bool) {
+	total := 0
+	for _, v := range variants {
+		total += v.Weight
+	}
+	if total <= 0 {
+		return Variant{}, false
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	n := int(h.Sum64() % uint64(total))
+	for _, v := range variants {
+		if n < v.Weight {
+			return v, true
+		}
+		n -= v.Weight
+	}
+	return Variant{}, false
+}
+
+// Route is where a visitor of a link is sent. RuleIndex is the routing rule
+// that matched, or -1; VariantID is set when a split-test variant was picked.
+type Route struct {
+	TargetURL string
+	RuleIndex int
+	VariantID string
+}
+
+// Route applies the link's routing rules, then its split test, to a visitor,
+// and finally its query options to the chosen target. A matching rule takes
+// precedence over the variants. Visitors are kept on the same variant by
+// hashing the link ID with their IP and User-Agent.
+func (l Link) Route(v Visitor) Route {
+	route := Route{TargetURL: l.TargetURL, RuleIndex: -1}
+	if len(l.Rules) > 0 {
+		route.TargetURL, route.RuleIndex = RouteTarget(l.TargetURL, l.Rules, v.Profile())
+	}
+	if route.RuleIndex < 0 {
+		if variant, ok := PickVariant(l.Variants, l.ID+"\x00"+v.IP+"\x00"+v.UserAgent); ok {
+			route.TargetURL = variant.TargetURL
+			route.VariantID = variant.ID
+		}
+	}
+	route.TargetURL = l.QueryOptions.Apply(route.TargetURL, v.Query)
+	return route
+}
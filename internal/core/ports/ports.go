@@ -2,23 +2,468 @@ package ports
 
 import (
 	"context"
+	"image"
+	"io"
+	"net/http"
+	"time"
 
 	"github.com/esdrassantos06/go-shortener/internal/core/domain"
 )
 
+// Link lookups take the custom domain hostname the link belongs to; an empty
+// domain means the default SHORT_URL_DOMAIN. Every mutation takes the
+// LinkChange describing it and appends it to the audit log in the same
+// transaction.
 type LinkRepository interface {
-	Save(ctx context.Context, link domain.Link) (domain.Link, error)
-	GetByShortID(ctx context.Context, shortID string) (domain.Link, error)
-	IncrementClicks(ctx context.Context, shortID string) error
+	Save(ctx context.Context, link domain.Link, change domain.LinkChange) (domain.Link, error)
+	// SaveMany inserts links with one multi-row INSERT, keeping CreatedAt and
+	// Clicks when they are set. Links whose slug is already taken on their
+	// domain are returned in conflicts; when atomic is set, any conflict rolls
+	// back the whole batch and nothing is saved. Only the changes of saved
+	// links are recorded.
+	SaveMany(ctx context.Context, links []domain.Link, atomic bool, changes []domain.LinkChange) (saved []domain.Link, conflicts []domain.Link, err error)
+	GetByShortID(ctx context.Context, linkDomain string, shortID string) (domain.Link, error)
+	// FindByTargetHash returns the user's oldest active link on linkDomain
+	// whose normalized target has the given hash.
+	FindByTargetHash(ctx context.Context, userID string, linkDomain string, targetHash string) (domain.Link, error)
+	IncrementClicks(ctx context.Context, linkDomain string, shortID string) error
+	// StreamByUser calls fn for each of the user's links without loading them
+	// all into memory. Iteration stops at the first error fn returns.
+	StreamByUser(ctx context.Context, userID string, fn func(domain.Link) error) error
+	List(ctx context.Context, userID string, filter domain.LinkFilter) (domain.LinkPage, error)
+	// Search ranks the user's links by full-text match on slug, target,
+	// title, description, notes and tags, and by trigram similarity of the
+	// slug. A nil userID searches every user's links.
+	Search(ctx context.Context, userID *string, search domain.LinkSearch) (domain.LinkPage, error)
+	// CountCreatedByDay counts the links created on each day in [from, to),
+	// skipping days without any.
+	CountCreatedByDay(ctx context.Context, from time.Time, to time.Time) ([]domain.DailyCount, error)
+	// StreamByTargetHost calls fn for each link whose target host is host, or
+	// a subdomain of it when includeSubdomains is set. A nil userID covers
+	// every user's links. Iteration stops at the first error fn returns.
+	StreamByTargetHost(ctx context.Context, userID *string, host string, includeSubdomains bool, fn func(domain.Link) error) error
+	// UpdateTargets sets the target of every link in changes in one
+	// transaction; either all of them are updated or none is.
+	UpdateTargets(ctx context.Context, changes []domain.TargetChange, history []domain.LinkChange) error
+	SetFolder(ctx context.Context, linkID string, folderID *string, change domain.LinkChange) error
+	UpdateMetadata(ctx context.Context, link domain.Link, change domain.LinkChange) error
+	SetRules(ctx context.Context, linkID string, rules []domain.RoutingRule, change domain.LinkChange) error
+	SetQueryOptions(ctx context.Context, linkID string, options domain.QueryOptions, change domain.LinkChange) error
+	SetForwardPath(ctx context.Context, linkID string, enabled bool, change domain.LinkChange) error
+	SetSchedule(ctx context.Context, linkID string, schedule domain.LinkSchedule, change domain.LinkChange) error
+	// SetMaxUses limits a link to maxUses opens, resetting the uses left; nil
+	// removes the limit.
+	SetMaxUses(ctx context.Context, linkID string, maxUses *int, change domain.LinkChange) error
+	// ConsumeUse spends one use of a limited link and returns how many are
+	// left, or ErrLinkUsedUp when there were none.
+	ConsumeUse(ctx context.Context, linkID string) (int, error)
+	SetInterstitial(ctx context.Context, linkID string, interstitial domain.Interstitial, change domain.LinkChange) error
+	// StreamPathSlugs calls fn for every link whose slug matters to path
+	// resolution: prefix links and slugs containing "/".
+	StreamPathSlugs(ctx context.Context, fn func(linkDomain string, shortID string, forwardPath bool) error) error
+	// SetVariants replaces a link's split-test variants; an empty token
+	// clears the postback token.
+	SetVariants(ctx context.Context, linkID string, variants []domain.Variant, postbackToken string, change domain.LinkChange) error
+	GetByPostbackToken(ctx context.Context, token string) (domain.Link, error)
+	// SetTitleIfEmpty stores a fetched title unless the link has been given
+	// one in the meantime. Fetched titles are not recorded as changes.
+	SetTitleIfEmpty(ctx context.Context, linkID string, title string) error
+	// ListChanges returns audit log entries, newest first.
+	ListChanges(ctx context.Context, filter domain.LinkChangeFilter) (domain.LinkChangePage, error)
+	// ListVersions returns the previous versions of a link, newest first.
+	ListVersions(ctx context.Context, linkID string) ([]domain.LinkVersion, error)
+	GetVersion(ctx context.Context, linkID string, number int) (domain.LinkVersion, error)
+	// UpdateSettings writes every setting of link, along with its postback
+	// token. Uses left are reset only when the use limit changes.
+	UpdateSettings(ctx context.Context, link domain.Link, change domain.LinkChange) error
+	ScheduleChange(ctx context.Context, change domain.ScheduledChange) (domain.ScheduledChange, error)
+	// ListScheduledChanges returns a link's scheduled changes in the order
+	// they run, including finished ones.
+	ListScheduledChanges(ctx context.Context, linkID string) ([]domain.ScheduledChange, error)
+	// CancelScheduledChange cancels a pending change, or returns
+	// ErrScheduledChangeClosed when it is no longer pending.
+	CancelScheduledChange(ctx context.Context, linkID string, id string) (domain.ScheduledChange, error)
+	// ClaimDueChanges marks up to limit changes due at now as RUNNING and
+	// returns them, oldest first. Changes claimed by another instance are
+	// skipped unless the claim is stale.
+	ClaimDueChanges(ctx context.Context, now time.Time, limit int) ([]domain.ScheduledChange, error)
+	// FinishScheduledChange records how a claimed change ended, or returns
+	// ErrScheduledClaimLost when another instance has since taken it over.
+	FinishScheduledChange(ctx context.Context, change domain.ScheduledChange, status domain.ScheduledChangeStatus, message string) error
+	// NextScheduledRun returns when the next pending change is due, or the
+	// zero time when none is.
+	NextScheduledRun(ctx context.Context) (time.Time, error)
+}
+
+type TagRepository interface {
+	Create(ctx context.Context, tag domain.Tag) (domain.Tag, error)
+	Rename(ctx context.Context, userID string, id string, name string) (domain.Tag, error)
+	ListWithStats(ctx context.Context, userID string) ([]domain.TagStats, error)
+	// EnsureTags returns the user's tags with the given names, creating the
+	// ones that do not exist yet.
+	EnsureTags(ctx context.Context, userID string, names []string) ([]domain.Tag, error)
+	// LinkTags returns the names of a link's tags, sorted.
+	LinkTags(ctx context.Context, linkID string) ([]string, error)
+	// SetLinkTags replaces the tags attached to a link.
+	SetLinkTags(ctx context.Context, linkID string, tagIDs []string, change domain.LinkChange) error
+	// AttachTags adds linkIDs[i] -> tagIDs[i] pairs, ignoring existing ones.
+	AttachTags(ctx context.Context, linkIDs []string, tagIDs []string) error
+}
+
+type FolderRepository interface {
+	Create(ctx context.Context, folder domain.Folder) (domain.Folder, error)
+	Rename(ctx context.Context, userID string, id string, name string) (domain.Folder, error)
+	GetByID(ctx context.Context, id string) (domain.Folder, error)
+	List(ctx context.Context, userID string) ([]domain.Folder, error)
+}
+
+type ClickRepository interface {
+	Record(ctx context.Context, event domain.ClickEvent) error
+	// StreamByUser calls fn for each click on the user's links in [from, to).
+	// A zero from or to leaves that side of the range open.
+	StreamByUser(ctx context.Context, userID string, from time.Time, to time.Time, fn func(domain.ClickEvent) error) error
+	// CountByVariant returns the link's click count per split-test variant.
+	CountByVariant(ctx context.Context, linkID string) (map[string]int, error)
+	// CountByDay counts the clicks on each day in [from, to), skipping days
+	// without any.
+	CountByDay(ctx context.Context, from time.Time, to time.Time) ([]domain.DailyCount, error)
+	// TopLinks returns the links with the most clicks in [from, to).
+	TopLinks(ctx context.Context, from time.Time, to time.Time, limit int) ([]domain.LinkClicks, error)
+}
+
+type ConversionRepository interface {
+	Record(ctx context.Context, conversion domain.Conversion) error
+	CountByVariant(ctx context.Context, linkID string) (map[string]int, error)
+}
+
+type DomainRepository interface {
+	Save(ctx context.Context, d domain.CustomDomain) (domain.CustomDomain, error)
+	// GetByHostname returns the verified claim on a hostname; pending claims
+	// of other users are never returned.
+	GetByHostname(ctx context.Context, hostname string) (domain.CustomDomain, error)
+	// GetClaim returns a user's own claim on a hostname, verified or not.
+	GetClaim(ctx context.Context, hostname string, userID string) (domain.CustomDomain, error)
+	ListByUser(ctx context.Context, userID string) ([]domain.CustomDomain, error)
+	// MarkVerified verifies a claim and drops the other claims on its
+	// hostname. It returns ErrDomainTaken if another claim was verified first.
+	MarkVerified(ctx context.Context, id string) (domain.CustomDomain, error)
+}
+
+type ImportJobRepository interface {
+	Create(ctx context.Context, job domain.ImportJob) (domain.ImportJob, error)
+	UpdateProgress(ctx context.Context, job domain.ImportJob) error
+	GetByID(ctx context.Context, id string) (domain.ImportJob, error)
+}
+
+type ExportJobRepository interface {
+	Create(ctx context.Context, job domain.ExportJob) (domain.ExportJob, error)
+	Update(ctx context.Context, job domain.ExportJob) error
+	GetByID(ctx context.Context, id string) (domain.ExportJob, error)
+}
+
+// ExportStorage holds finished export files until they are downloaded.
+type ExportStorage interface {
+	Create(name string) (io.WriteCloser, error)
+	Open(name string) (io.ReadCloser, error)
+}
+
+// ModerationRepository stores abuse reports, user bans and the moderation
+// audit log. Every change takes the ModerationAction describing it and
+// records it in the same transaction, along with the LinkChange of any link
+// it modifies.
+type ModerationRepository interface {
+	CreateReport(ctx context.Context, report domain.AbuseReport) (domain.AbuseReport, error)
+	GetReport(ctx context.Context, id string) (domain.AbuseReport, error)
+	ListReports(ctx context.Context, filter domain.ReportFilter) (domain.ReportPage, error)
+	// DismissReport closes an open report, or returns ErrReportClosed.
+	DismissReport(ctx context.Context, id string, action domain.ModerationAction) (domain.AbuseReport, error)
+	// SetLinkStatus sets a link's status. Blocking a link also closes its
+	// open reports as actioned.
+	SetLinkStatus(ctx context.Context, linkID string, status domain.LinkStatus, action domain.ModerationAction, change domain.LinkChange) error
+	// DeleteLink deletes a link along with its reports and tags.
+	DeleteLink(ctx context.Context, linkID string, action domain.ModerationAction, change domain.LinkChange) error
+	SetLinkFlagged(ctx context.Context, linkID string, flagged bool, action domain.ModerationAction, change domain.LinkChange) error
+	// BanUser bans a user, replacing any earlier ban. With blockOwned it also
+	// blocks every link the user owns and returns them, with only ID, Domain,
+	// ShortID and Status set; blockChange describes the blocking of each one
+	// given its previous state.
+	BanUser(ctx context.Context, ban domain.UserBan, blockOwned bool, action domain.ModerationAction, blockChange func(before domain.Link) domain.LinkChange) (domain.UserBan, []domain.Link, error)
+	// UnbanUser lifts a ban, or returns ErrUserNotBanned.
+	UnbanUser(ctx context.Context, userID string, action domain.ModerationAction) error
+	IsBanned(ctx context.Context, userID string) (bool, error)
+	// ListActions returns audit log entries, newest first.
+	ListActions(ctx context.Context, filter domain.ModerationActionFilter) (domain.ModerationActionPage, error)
+}
+
+// UserRepository reads the accounts managed by Better Auth.
+type UserRepository interface {
+	Get(ctx context.Context, id string) (domain.UserSummary, error)
+	// Search matches users by exact ID, or by email or name containing the
+	// query, newest first.
+	Search(ctx context.Context, search domain.UserSearch) (domain.UserPage, error)
+	// GetRole returns the user's Better Auth role, which may list several
+	// comma-separated roles, or "" when roles are not in use.
+	GetRole(ctx context.Context, userID string) (string, error)
 }
 
 type CacheRepository interface {
 	Get(ctx context.Context, key string) (string, error)
 	Set(ctx context.Context, key string, value string, ttlSeconds int) error
+	// SetNX stores value only if key does not exist yet and reports whether
+	// it was stored.
+	SetNX(ctx context.Context, key string, value string, ttlSeconds int) (bool, error)
+	Delete(ctx context.Context, keys ...string) error
 	IncrementCounter(ctx context.Context, key string) error
+	// IncrementWindow increments the counter at key and returns the new
+	// count. A new counter expires after ttlSeconds; incrementing it does not
+	// extend that.
+	IncrementWindow(ctx context.Context, key string, ttlSeconds int) (int64, error)
+	// DecrementIfPositive atomically decrements the integer at key unless it
+	// is zero or less. It returns the new value, or -1 when nothing was left,
+	// and reports false when key does not exist.
+	DecrementIfPositive(ctx context.Context, key string) (int64, bool, error)
+}
+
+// HTTPClient is satisfied by *http.Client.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// TitleFetcher reads the title of the page a link points to.
+type TitleFetcher interface {
+	FetchTitle(ctx context.Context, targetURL string) (string, error)
+}
+
+// ImageFetcher downloads and decodes a remote image.
+type ImageFetcher interface {
+	FetchImage(ctx context.Context, imageURL string) (image.Image, error)
+}
+
+// QRRenderer draws a QR code encoding content. logo may be nil.
+type QRRenderer interface {
+	Render(content string, opts domain.QROptions, logo image.Image) ([]byte, error)
+}
+
+// CrawlerClassifier recognizes the bots that fetch a link to render a
+// preview card, as opposed to visitors who should be redirected.
+type CrawlerClassifier interface {
+	IsPreviewCrawler(userAgent string) bool
+}
+
+// CountryLocator finds the ISO country code of a visitor from their IP
+// address or request headers, returning an empty string when unknown.
+type CountryLocator interface {
+	Locate(ip string, header func(key string) string) string
+}
+
+// DNSResolver is satisfied by *net.Resolver.
+type DNSResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// ImportParser reads the links out of one export file format.
+type ImportParser interface {
+	Parse(r io.Reader) ([]domain.ImportRecord, error)
+}
+
+// ExportWriter encodes exported rows in one file format. Close flushes any
+// buffered rows and must be called after the last write.
+type ExportWriter interface {
+	WriteLink(link domain.Link) error
+	WriteClick(click domain.ClickEvent) error
+	Close() error
+}
+
+type ExportWriterFactory interface {
+	NewWriter(w io.Writer, format domain.ExportFormat, dataset domain.ExportDataset) (ExportWriter, error)
+}
+
+// PathInvalidator is told when links that can match multi-segment paths,
+// nested slugs or prefix links, were saved outside the link service.
+type PathInvalidator interface {
+	InvalidatePaths()
 }
 
 type LinkService interface {
-	ShortenURL(ctx context.Context, targetURL string, customSlug string, userID *string) (domain.Link, error)
-	ResolveURL(ctx context.Context, shortID string) (domain.Link, error)
+	PathInvalidator
+	ShortenURL(ctx context.Context, input domain.LinkInput, userID *string) (domain.Link, error)
+	// ShortenBulk validates every input before inserting any of them. In
+	// atomic mode a single failure means no link is created.
+	ShortenBulk(ctx context.Context, inputs []domain.LinkInput, atomic bool, userID *string) ([]domain.BulkLinkResult, error)
+	ListLinks(ctx context.Context, userID string, filter domain.LinkFilter) (domain.LinkPage, error)
+	SearchLinks(ctx context.Context, userID string, search domain.LinkSearch) (domain.LinkPage, error)
+	// SetLinkTags replaces a link's tags by name, creating missing tags.
+	SetLinkTags(ctx context.Context, userID string, linkDomain string, shortID string, tags []string) (domain.Link, error)
+	// SetLinkFolder moves a link into a folder, or out of any folder when
+	// folderID is nil.
+	SetLinkFolder(ctx context.Context, userID string, linkDomain string, shortID string, folderID *string) (domain.Link, error)
+	// GetLink returns one of the user's links; links owned by someone else
+	// are reported as not found.
+	GetLink(ctx context.Context, userID string, linkDomain string, shortID string) (domain.Link, error)
+	UpdateLink(ctx context.Context, userID string, linkDomain string, shortID string, update domain.LinkUpdate) (domain.Link, error)
+	// SetLinkRules replaces the ordered device/OS routing rules of a link.
+	SetLinkRules(ctx context.Context, userID string, linkDomain string, shortID string, rules []domain.RoutingRule) (domain.Link, error)
+	// SetLinkQueryOptions replaces a link's UTM parameters and query-string
+	// forwarding settings.
+	SetLinkQueryOptions(ctx context.Context, userID string, linkDomain string, shortID string, options domain.QueryOptions) (domain.Link, error)
+	// SetLinkSchedule replaces the window in which a link is live and its
+	// fallback URLs for before and after it.
+	SetLinkSchedule(ctx context.Context, userID string, linkDomain string, shortID string, schedule domain.LinkSchedule) (domain.Link, error)
+	// SetLinkMaxUses limits how many times a link can be opened and resets
+	// its uses left; 0 removes the limit.
+	SetLinkMaxUses(ctx context.Context, userID string, linkDomain string, shortID string, maxUses int) (domain.Link, error)
+	// SetLinkInterstitial turns the interstitial page of a link on or off.
+	SetLinkInterstitial(ctx context.Context, userID string, linkDomain string, shortID string, interstitial domain.Interstitial) (domain.Link, error)
+	// FindLinksByTarget returns the links pointing at a URL or domain. A nil
+	// userID searches every user's links and is reserved for admins.
+	FindLinksByTarget(ctx context.Context, userID *string, lookup domain.TargetLookup) (domain.TargetLookupResult, error)
+	// RewriteTargets swaps a URL prefix across the user's links that point at
+	// or under it, and drops their cached entries.
+	RewriteTargets(ctx context.Context, userID string, rewrite domain.TargetRewrite) ([]domain.TargetChange, error)
+	// ResolveURL returns the link with TargetURL set to where the visitor
+	// goes. Outside the link's schedule that is the not-live or ended URL,
+	// without routing or counting a click, or ErrLinkNotLive or ErrLinkEnded
+	// when the link has none. Limited links return ErrConfirmationNeeded
+	// unless the visitor confirmed, and spend a use otherwise.
+	ResolveURL(ctx context.Context, linkDomain string, shortID string, visitor domain.Visitor) (domain.Link, error)
+	// ResolvePath is ResolveURL for a request path that may be longer than a
+	// slug: nested slugs match exactly, and otherwise the longest prefix link
+	// has the rest of the path appended to its target.
+	ResolvePath(ctx context.Context, linkDomain string, path string, visitor domain.Visitor) (domain.Link, error)
+	// SetLinkPathForwarding turns a link into a prefix link, or back.
+	SetLinkPathForwarding(ctx context.Context, userID string, linkDomain string, shortID string, enabled bool) (domain.Link, error)
+	// PreviewLink loads an active link for a preview crawler without counting
+	// a click. path is resolved like in ResolvePath, and the link is gated
+	// the same way: outside its schedule TargetURL is the fallback URL, or
+	// ErrLinkNotLive or ErrLinkEnded is returned, and limited links return
+	// ErrConfirmationNeeded. Showing the interstitial is up to the caller.
+	PreviewLink(ctx context.Context, linkDomain string, path string) (domain.Link, error)
+	// LinkHistory returns the audit log of one of the user's links, newest
+	// first. Other actors' IDs and IP addresses are redacted, admins'
+	// included. The filter's LinkID is ignored.
+	LinkHistory(ctx context.Context, userID string, linkDomain string, shortID string, filter domain.LinkChangeFilter) (domain.LinkChangePage, error)
+	// ListLinkVersions returns the previous settings of one of the user's
+	// links, newest first.
+	ListLinkVersions(ctx context.Context, userID string, linkDomain string, shortID string) ([]domain.LinkVersion, error)
+	// RestoreLinkVersion puts back the settings of a previous version. The
+	// settings it replaces become a new version, so a restore can be undone.
+	RestoreLinkVersion(ctx context.Context, userID string, linkDomain string, shortID string, number int) (domain.Link, error)
+	// ScheduleLinkChange switches the link's target, or restores one of its
+	// versions, at a later time.
+	ScheduleLinkChange(ctx context.Context, userID string, linkDomain string, shortID string, input domain.ScheduledChangeInput) (domain.ScheduledChange, error)
+	ListScheduledChanges(ctx context.Context, userID string, linkDomain string, shortID string) ([]domain.ScheduledChange, error)
+	CancelScheduledChange(ctx context.Context, userID string, linkDomain string, shortID string, id string) error
+	// RunScheduler applies scheduled changes as they fall due, until ctx is
+	// done. Every instance can run one; each change is applied once.
+	RunScheduler(ctx context.Context)
+}
+
+type VariantService interface {
+	// SetLinkVariants replaces a link's weighted split-test targets. The link
+	// gets a postback token the first time variants are set; removing every
+	// variant revokes it.
+	SetLinkVariants(ctx context.Context, userID string, linkDomain string, shortID string, variants []domain.Variant) (domain.Link, error)
+	// VariantStats compares clicks and conversions across a link's variants.
+	VariantStats(ctx context.Context, userID string, linkDomain string, shortID string) ([]domain.VariantStats, error)
+	// RecordConversion counts a conversion for a variant of the link owning
+	// the postback token.
+	RecordConversion(ctx context.Context, postbackToken string, variantID string) error
+}
+
+type QRService interface {
+	// RenderQRCode returns the QR code image for content, reusing a cached
+	// rendering with the same options when there is one.
+	RenderQRCode(ctx context.Context, content string, opts domain.QROptions) ([]byte, error)
+}
+
+type DomainService interface {
+	RegisterDomain(ctx context.Context, hostname string, userID string) (domain.CustomDomain, error)
+	VerifyDomain(ctx context.Context, hostname string, userID string) (domain.CustomDomain, error)
+	ListDomains(ctx context.Context, userID string) ([]domain.CustomDomain, error)
+}
+
+type ImportService interface {
+	// StartImport parses the file synchronously and inserts its links in a
+	// background job whose progress is read back with GetImport.
+	StartImport(ctx context.Context, userID string, format domain.ImportFormat, linkDomain string, r io.Reader) (domain.ImportJob, error)
+	GetImport(ctx context.Context, id string, userID string) (domain.ImportJob, error)
+}
+
+type ExportService interface {
+	// Export streams the requested dataset to w as rows are read.
+	Export(ctx context.Context, userID string, req domain.ExportRequest, w io.Writer) error
+	// StartExport writes the dataset to ExportStorage in a background job.
+	StartExport(ctx context.Context, userID string, req domain.ExportRequest) (domain.ExportJob, error)
+	GetExport(ctx context.Context, id string, userID string) (domain.ExportJob, error)
+	OpenExport(ctx context.Context, id string, userID string) (domain.ExportJob, io.ReadCloser, error)
+}
+
+type TagService interface {
+	CreateTag(ctx context.Context, userID string, name string) (domain.Tag, error)
+	RenameTag(ctx context.Context, userID string, id string, name string) (domain.Tag, error)
+	// ListTags returns the user's tags with link counts and click totals.
+	ListTags(ctx context.Context, userID string) ([]domain.TagStats, error)
+}
+
+type FolderService interface {
+	CreateFolder(ctx context.Context, userID string, name string) (domain.Folder, error)
+	RenameFolder(ctx context.Context, userID string, id string, name string) (domain.Folder, error)
+	ListFolders(ctx context.Context, userID string) ([]domain.Folder, error)
+}
+
+// Blocklist matches URLs against lists of known malicious hosts and URLs.
+type Blocklist interface {
+	// Match returns the name of the list that blocks rawURL, if any.
+	Match(rawURL string) (list string, blocked bool)
+	// Version changes every time the lists are reloaded, so a URL checked
+	// against an older version can be checked again.
+	Version() uint64
+}
+
+// ModerationService handles abuse reports and the moderator actions taken on
+// them. Every action is recorded in the moderation audit log.
+type ModerationService interface {
+	// ReportLink files an abuse report on a link. Repeated reports of the
+	// same link from the same IP address return ErrAlreadyReported.
+	ReportLink(ctx context.Context, linkDomain string, shortID string, input domain.ReportInput) (domain.AbuseReport, error)
+	ListReports(ctx context.Context, filter domain.ReportFilter) (domain.ReportPage, error)
+	// ReviewReport dismisses an open report, or blocks its link, which closes
+	// every open report on the link.
+	ReviewReport(ctx context.Context, moderatorID string, reportID string, review domain.ReportReview) (domain.AbuseReport, error)
+	// SetLinkStatus forces the status of any user's link. Owners cannot lift
+	// a BLOCKED status.
+	SetLinkStatus(ctx context.Context, moderatorID string, linkDomain string, shortID string, status domain.LinkStatus, note string) (domain.Link, error)
+	// DeleteLink deletes any user's link.
+	DeleteLink(ctx context.Context, moderatorID string, linkDomain string, shortID string, note string) error
+	// SetLinkFlagged marks any user's link as suspicious, which forces a
+	// warning interstitial.
+	SetLinkFlagged(ctx context.Context, moderatorID string, linkDomain string, shortID string, flagged bool, note string) (domain.Link, error)
+	// BanUser keeps a user out of the API and, with blockLinks, blocks every
+	// link they own.
+	BanUser(ctx context.Context, moderatorID string, userID string, reason string, blockLinks bool) (domain.UserBan, error)
+	UnbanUser(ctx context.Context, moderatorID string, userID string, note string) error
+	// IsBanned reports whether a user is banned. Lookups are cached briefly;
+	// when they fail the user is treated as not banned.
+	IsBanned(ctx context.Context, userID string) bool
+	ListActions(ctx context.Context, filter domain.ModerationActionFilter) (domain.ModerationActionPage, error)
+}
+
+// AdminService backs the /api/admin routes that act across every user.
+type AdminService interface {
+	// IsAdmin reports whether a user has the admin role in the user table.
+	// Lookups are cached briefly; when they fail the user is not an admin.
+	IsAdmin(ctx context.Context, userID string) bool
+	// SearchLinks searches every user's links, or one user's when userID is
+	// set.
+	SearchLinks(ctx context.Context, userID *string, search domain.LinkSearch) (domain.LinkPage, error)
+	SearchUsers(ctx context.Context, search domain.UserSearch) (domain.UserPage, error)
+	GetUser(ctx context.Context, id string) (domain.UserSummary, error)
+	// PlatformStats counts new links and clicks per day over the last days
+	// days, today included, and finds the most clicked links.
+	PlatformStats(ctx context.Context, days int) (domain.PlatformStats, error)
+	// FlushLinkCache drops the cached copy of any user's link, so the next
+	// visitor reads it from the database.
+	FlushLinkCache(ctx context.Context, linkDomain string, shortID string) error
+	// ListLinkChanges queries the audit log of every link, newest first.
+	ListLinkChanges(ctx context.Context, filter domain.LinkChangeFilter) (domain.LinkChangePage, error)
 }
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+)
+
+const (
+	// roleCacheTTL bounds how long a role lookup is cached, and so how long
+	// a revoked admin keeps access.
+	roleCacheTTL     = 60
+	defaultStatsDays = 30
+	topLinksLimit    = 10
+)
+
+type DefaultAdminService struct {
+	Links  ports.LinkRepository
+	Clicks ports.ClickRepository
+	Users  ports.UserRepository
+	Cache  ports.CacheRepository
+}
+
+func NewAdminService(links ports.LinkRepository, clicks ports.ClickRepository, users ports.UserRepository, cache ports.CacheRepository) ports.AdminService {
+	return &DefaultAdminService{Links: links, Clicks: clicks, Users: users, Cache: cache}
+}
+
+func (s *DefaultAdminService) IsAdmin(ctx context.Context, userID string) bool {
+	key := "role:" + userID
+	role, err := s.Cache.Get(ctx, key)
+	if err != nil || role == "" {
+		if role, err = s.Users.GetRole(ctx, userID); err != nil {
+			log.Printf("failed to look up role of user %s: %v", userID, err)
+			return false
+		}
+		// An empty value would read as a cache miss.
+		_ = s.Cache.Set(ctx, key, role+";", roleCacheTTL)
+	}
+
+	for _, r := range strings.Split(strings.TrimSuffix(role, ";"), ",") {
+		if strings.TrimSpace(r) == domain.AdminRole {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *DefaultAdminService) SearchLinks(ctx context.Context, userID *string, search domain.LinkSearch) (domain.LinkPage, error) {
+	search.Query = strings.TrimSpace(search.Query)
+	if search.Query == "" {
+		return domain.LinkPage{}, domain.ErrEmptySearch
+	}
+	search.Limit, search.Offset = clampPage(search.Limit, search.Offset)
+	return s.Links.Search(ctx, userID, search)
+}
+
+func (s *DefaultAdminService) SearchUsers(ctx context.Context, search domain.UserSearch) (domain.UserPage, error) {
+	search.Query = strings.TrimSpace(search.Query)
+	if search.Query == "" {
+		return domain.UserPage{}, domain.ErrEmptySearch
+	}
+	search.Limit, search.Offset = clampPage(search.Limit, search.Offset)
+	return s.Users.Search(ctx, search)
+}
+
+func (s *DefaultAdminService) GetUser(ctx context.Context, id string) (domain.UserSummary, error) {
+	return s.Users.Get(ctx, strings.TrimSpace(id))
+}
+
+func (s *DefaultAdminService) PlatformStats(ctx context.Context, days int) (domain.PlatformStats, error) {
+	if days <= 0 {
+		days = defaultStatsDays
+	}
+	days = min(days, domain.MaxStatsDays)
+
+	now := time.Now().UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+	from := to.AddDate(0, 0, -days)
+
+	links, err := s.Links.CountCreatedByDay(ctx, from, to)
+	if err != nil {
+		return domain.PlatformStats{}, err
+	}
+	clicks, err := s.Clicks.CountByDay(ctx, from, to)
+	if err != nil {
+		return domain.PlatformStats{}, err
+	}
+	top, err := s.Clicks.TopLinks(ctx, from, to, topLinksLimit)
+	if err != nil {
+		return domain.PlatformStats{}, err
+	}
+
+	return domain.PlatformStats{
+		From:         from,
+		To:           to,
+		LinksPerDay:  fillDays(from, days, links),
+		ClicksPerDay: fillDays(from, days, clicks),
+		TopLinks:     top,
+	}, nil
+}
+
+// fillDays returns one count per day starting at from, taking zero for the
+// days missing from counts.
+func fillDays(from time.Time, days int, counts []domain.DailyCount) []domain.DailyCount {
+	byDay := make(map[string]int, len(counts))
+	for _, c := range counts {
+		byDay[c.Day] = c.Count
+	}
+	filled := make([]domain.DailyCount, days)
+	for i := range filled {
+		day := from.AddDate(0, 0, i).Format(time.DateOnly)
+		filled[i] = domain.DailyCount{Day: day, Count: byDay[day]}
+	}
+	return filled
+}
+
+func (s *DefaultAdminService) FlushLinkCache(ctx context.Context, linkDomain string, shortID string) error {
+	link, err := s.Links.GetByShortID(ctx, linkDomain, shortID)
+	if err != nil {
+		return err
+	}
+	return s.Cache.Delete(ctx, "url"+linkKey(link.Domain, link.ShortID))
+}
+
+func (s *DefaultAdminService) ListLinkChanges(ctx context.Context, filter domain.LinkChangeFilter) (domain.LinkChangePage, error) {
+	filter.Limit, filter.Offset = clampPage(filter.Limit, filter.Offset)
+	return s.Links.ListChanges(ctx, filter)
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+)
+
+// checkTargets rejects URLs on the blocklist. A nil blocklist allows every
+// URL.
+func checkTargets(blocklist ports.Blocklist, targets ...string) error {
+	if blocklist == nil {
+		return nil
+	}
+	for _, target := range targets {
+		if list, blocked := blocklist.Match(target); blocked {
+			return fmt.Errorf("%w: %s is listed in %s", domain.ErrTargetBlocked, target, list)
+		}
+	}
+	return nil
+}
+
+// blocklistCheck records the blocklist version a link's targets were last
+// checked against, and the outcome. Version 0 means never checked.
+type blocklistCheck struct {
+	Version uint64 `json:"version"`
+	Blocked bool   `json:"blocked,omitempty"`
+}
+
+// checkLink checks every target of a link against the current blocklist.
+func (s *DefaultLinkService) checkLink(link domain.Link) blocklistCheck {
+	if s.Blocklist == nil {
+		return blocklistCheck{}
+	}
+	check := blocklistCheck{Version: s.Blocklist.Version()}
+	if err := checkTargets(s.Blocklist, link.Targets()...); err != nil {
+		log.Printf("link %s is blocked: %v", link.ID, err)
+		check.Blocked = true
+	}
+	return check
+}
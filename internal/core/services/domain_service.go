@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type DefaultDomainService struct {
+	Repo     ports.DomainRepository
+	Resolver ports.DNSResolver
+}
+
+func NewDomainService(repo ports.DomainRepository, resolver ports.DNSResolver) ports.DomainService {
+	return &DefaultDomainService{Repo: repo, Resolver: resolver}
+}
+
+func (s *DefaultDomainService) RegisterDomain(ctx context.Context, hostname string, userID string) (domain.CustomDomain, error) {
+	if userID == "" {
+		return domain.CustomDomain{}, errors.New("userID is required")
+	}
+
+	host, err := domain.NormalizeHostname(hostname)
+	if err != nil {
+		return domain.CustomDomain{}, err
+	}
+
+	// Registering only claims the hostname: other users may hold pending
+	// claims on it too, and the first to verify it owns it.
+	verified, err := s.Repo.GetByHostname(ctx, host)
+	if err == nil {
+		if verified.UserID == userID {
+			return verified, nil
+		}
+		return domain.CustomDomain{}, domain.ErrDomainTaken
+	}
+	if !errors.Is(err, domain.ErrDomainNotFound) {
+		return domain.CustomDomain{}, err
+	}
+
+	existing, err := s.Repo.GetClaim(ctx, host, userID)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, domain.ErrDomainNotFound) {
+		return domain.CustomDomain{}, err
+	}
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return domain.CustomDomain{}, err
+	}
+
+	return s.Repo.Save(ctx, domain.CustomDomain{
+		ID:                uuid.New().String(),
+		Hostname:          host,
+		UserID:            userID,
+		VerificationToken: hex.EncodeToString(token),
+	})
+}
+
+// VerifyDomain looks up the TXT records at the domain's verification name and
+// marks it verified when one of them carries the expected token. Verifying
+// drops the other users' pending claims on the hostname.
+func (s *DefaultDomainService) VerifyDomain(ctx context.Context, hostname string, userID string) (domain.CustomDomain, error) {
+	d, err := s.getOwned(ctx, hostname, userID)
+	if err != nil {
+		return domain.CustomDomain{}, err
+	}
+	if d.Verified() {
+		return d, nil
+	}
+
+	records, err := s.Resolver.LookupTXT(ctx, d.VerificationRecordName())
+	if err != nil {
+		return domain.CustomDomain{}, domain.ErrVerificationFailed
+	}
+
+	expected := d.VerificationRecordValue()
+	for _, record := range records {
+		if strings.TrimSpace(record) == expected {
+			return s.Repo.MarkVerified(ctx, d.ID)
+		}
+	}
+
+	return domain.CustomDomain{}, domain.ErrVerificationFailed
+}
+
+func (s *DefaultDomainService) ListDomains(ctx context.Context, userID string) ([]domain.CustomDomain, error) {
+	if userID == "" {
+		return nil, errors.New("userID is required")
+	}
+	return s.Repo.ListByUser(ctx, userID)
+}
+
+func (s *DefaultDomainService) getOwned(ctx context.Context, hostname string, userID string) (domain.CustomDomain, error) {
+	host, err := domain.NormalizeHostname(hostname)
+	if err != nil {
+		return domain.CustomDomain{}, err
+	}
+
+	return s.Repo.GetClaim(ctx, host, userID)
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+)
+
+// fakeDomainRepo keeps claims in memory with the same rules as the Postgres
+// repository: one verified claim per hostname, one claim per user.
+type fakeDomainRepo struct {
+	claims []domain.CustomDomain
+}
+
+func (r *fakeDomainRepo) Save(_ context.Context, d domain.CustomDomain) (domain.CustomDomain, error) {
+	d.CreatedAt = time.Now()
+	r.claims = append(r.claims, d)
+	return d, nil
+}
+
+func (r *fakeDomainRepo) GetByHostname(_ context.Context, hostname string) (domain.CustomDomain, error) {
+	for _, d := range r.claims {
+		if d.Hostname == hostname && d.Verified() {
+			return d, nil
+		}
+	}
+	return domain.CustomDomain{}, domain.ErrDomainNotFound
+}
+
+func (r *fakeDomainRepo) GetClaim(_ context.Context, hostname string, userID string) (domain.CustomDomain, error) {
+	for _, d := range r.claims {
+		if d.Hostname == hostname && d.UserID == userID {
+			return d, nil
+		}
+	}
+	return domain.CustomDomain{}, domain.ErrDomainNotFound
+}
+
+func (r *fakeDomainRepo) ListByUser(_ context.Context, userID string) ([]domain.CustomDomain, error) {
+	var domains []domain.CustomDomain
+	for _, d := range r.claims {
+		if d.UserID == userID {
+			domains = append(domains, d)
+		}
+	}
+	return domains, nil
+}
+
+func (r *fakeDomainRepo) MarkVerified(_ context.Context, id string) (domain.CustomDomain, error) {
+	i := -1
+	for j, d := range r.claims {
+		if d.ID == id {
+			i = j
+		}
+	}
+	if i < 0 {
+		return domain.CustomDomain{}, domain.ErrDomainNotFound
+	}
+	claim := r.claims[i]
+	if verified, err := r.GetByHostname(context.Background(), claim.Hostname); err == nil && verified.ID != id {
+		return domain.CustomDomain{}, domain.ErrDomainTaken
+	}
+	now := time.Now()
+	claim.VerifiedAt = &now
+
+	kept := []domain.CustomDomain{claim}
+	for _, d := range r.claims {
+		if d.Hostname != claim.Hostname {
+			kept = append(kept, d)
+		}
+	}
+	r.claims = kept
+	return claim, nil
+}
+
+// fakeResolver answers TXT lookups from a map; names it does not know fail
+// like an NXDOMAIN.
+type fakeResolver map[string][]string
+
+func (r fakeResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	records, ok := r[name]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return records, nil
+}
+
+const verifyHost = "go.acme.com"
+
+func TestVerifyDomain(t *testing.T) {
+	tests := []struct {
+		name     string
+		records  func(alice, bob domain.CustomDomain) []string
+		verifier string
+		hostname string
+		before   func(t *testing.T, s *DefaultDomainService)
+		wantErr  error
+	}{
+		{
+			name:     "matching record verifies the claim",
+			records:  func(alice, _ domain.CustomDomain) []string { return []string{alice.VerificationRecordValue()} },
+			verifier: "alice",
+			hostname: verifyHost,
+		},
+		{
+			name: "record among others and padded with spaces",
+			records: func(alice, _ domain.CustomDomain) []string {
+				return []string{"v=spf1 -all", "  " + alice.VerificationRecordValue() + " "}
+			},
+			verifier: "alice",
+			hostname: "GO.ACME.COM",
+		},
+		{
+			name:     "another claimant's token does not verify",
+			records:  func(_, bob domain.CustomDomain) []string { return []string{bob.VerificationRecordValue()} },
+			verifier: "alice",
+			hostname: verifyHost,
+			wantErr:  domain.ErrVerificationFailed,
+		},
+		{
+			name:     "missing record",
+			verifier: "alice",
+			hostname: verifyHost,
+			wantErr:  domain.ErrVerificationFailed,
+		},
+		{
+			name:     "user without a claim",
+			records:  func(alice, _ domain.CustomDomain) []string { return []string{alice.VerificationRecordValue()} },
+			verifier: "carol",
+			hostname: verifyHost,
+			wantErr:  domain.ErrDomainNotFound,
+		},
+		{
+			name:     "invalid hostname",
+			verifier: "alice",
+			hostname: "not a host",
+			wantErr:  domain.ErrInvalidHostname,
+		},
+		{
+			name: "another claimant verified first",
+			records: func(alice, bob domain.CustomDomain) []string {
+				return []string{alice.VerificationRecordValue(), bob.VerificationRecordValue()}
+			},
+			verifier: "alice",
+			hostname: verifyHost,
+			before: func(t *testing.T, s *DefaultDomainService) {
+				if _, err := s.VerifyDomain(context.Background(), verifyHost, "bob"); err != nil {
+					t.Fatalf("bob's verification failed: %v", err)
+				}
+			},
+			wantErr: domain.ErrDomainNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			resolver := fakeResolver{}
+			s := &DefaultDomainService{Repo: &fakeDomainRepo{}, Resolver: resolver}
+
+			alice, err := s.RegisterDomain(ctx, verifyHost, "alice")
+			if err != nil {
+				t.Fatalf("alice's claim failed: %v", err)
+			}
+			bob, err := s.RegisterDomain(ctx, verifyHost, "bob")
+			if err != nil {
+				t.Fatalf("bob's pending claim was refused: %v", err)
+			}
+			if tt.records != nil {
+				resolver[alice.VerificationRecordName()] = tt.records(alice, bob)
+			}
+			if tt.before != nil {
+				tt.before(t, s)
+			}
+
+			d, err := s.VerifyDomain(ctx, tt.hostname, tt.verifier)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyDomain() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if !d.Verified() || d.UserID != tt.verifier {
+				t.Fatalf("VerifyDomain() = %+v, want a verified claim of %s", d, tt.verifier)
+			}
+			if _, err := s.Repo.GetClaim(ctx, verifyHost, "bob"); !errors.Is(err, domain.ErrDomainNotFound) {
+				t.Errorf("bob's pending claim survived verification: %v", err)
+			}
+			if _, err := s.RegisterDomain(ctx, verifyHost, "bob"); !errors.Is(err, domain.ErrDomainTaken) {
+				t.Errorf("RegisterDomain() after verification error = %v, want %v", err, domain.ErrDomainTaken)
+			}
+		})
+	}
+}
+
+func TestRegisterDomainReturnsExistingClaim(t *testing.T) {
+	ctx := context.Background()
+	s := &DefaultDomainService{Repo: &fakeDomainRepo{}, Resolver: fakeResolver{}}
+
+	first, err := s.RegisterDomain(ctx, verifyHost, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := s.RegisterDomain(ctx, "Go.Acme.com.", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != first.ID || again.VerificationToken != first.VerificationToken {
+		t.Errorf("registering again issued a new claim: %+v, want %+v", again, first)
+	}
+}
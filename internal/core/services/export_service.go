@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"time"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type DefaultExportService struct {
+	Links   ports.LinkRepository
+	Clicks  ports.ClickRepository
+	Jobs    ports.ExportJobRepository
+	Storage ports.ExportStorage
+	Writers ports.ExportWriterFactory
+}
+
+func NewExportService(links ports.LinkRepository, clicks ports.ClickRepository, jobs ports.ExportJobRepository, storage ports.ExportStorage, writers ports.ExportWriterFactory) ports.ExportService {
+	return &DefaultExportService{Links: links, Clicks: clicks, Jobs: jobs, Storage: storage, Writers: writers}
+}
+
+func validateExportRequest(req domain.ExportRequest) error {
+	if !req.Format.Valid() || !req.Dataset.Valid() {
+		return domain.ErrInvalidExport
+	}
+	if !req.From.IsZero() && !req.To.IsZero() && !req.From.Before(req.To) {
+		return domain.ErrInvalidExport
+	}
+	return nil
+}
+
+func (s *DefaultExportService) Export(ctx context.Context, userID string, req domain.ExportRequest, w io.Writer) error {
+	_, err := s.export(ctx, userID, req, w)
+	return err
+}
+
+// export writes the dataset to w and returns the number of rows written.
+func (s *DefaultExportService) export(ctx context.Context, userID string, req domain.ExportRequest, w io.Writer) (int, error) {
+	if userID == "" {
+		return 0, errors.New("userID is required")
+	}
+	if err := validateExportRequest(req); err != nil {
+		return 0, err
+	}
+
+	writer, err := s.Writers.NewWriter(w, req.Format, req.Dataset)
+	if err != nil {
+		return 0, err
+	}
+
+	rows := 0
+	if req.Dataset == domain.ExportDatasetClicks {
+		err = s.Clicks.StreamByUser(ctx, userID, req.From, req.To, func(click domain.ClickEvent) error {
+			rows++
+			return writer.WriteClick(click)
+		})
+	} else {
+		err = s.Links.StreamByUser(ctx, userID, func(link domain.Link) error {
+			rows++
+			return writer.WriteLink(link)
+		})
+	}
+	if err != nil {
+		return rows, err
+	}
+	return rows, writer.Close()
+}
+
+func (s *DefaultExportService) StartExport(ctx context.Context, userID string, req domain.ExportRequest) (domain.ExportJob, error) {
+	if userID == "" {
+		return domain.ExportJob{}, errors.New("userID is required")
+	}
+	if err := validateExportRequest(req); err != nil {
+		return domain.ExportJob{}, err
+	}
+
+	job := domain.ExportJob{
+		ID:      uuid.New().String(),
+		UserID:  userID,
+		Dataset: req.Dataset,
+		Format:  req.Format,
+		Status:  domain.JobStatusPending,
+	}
+	if !req.From.IsZero() {
+		job.From = &req.From
+	}
+	if !req.To.IsZero() {
+		job.To = &req.To
+	}
+
+	job, err := s.Jobs.Create(ctx, job)
+	if err != nil {
+		return domain.ExportJob{}, err
+	}
+
+	go s.run(job)
+
+	return job, nil
+}
+
+func (s *DefaultExportService) run(job domain.ExportJob) {
+	ctx := context.Background()
+
+	job.Status = domain.JobStatusRunning
+	s.save(ctx, job)
+
+	rows, err := s.writeFile(ctx, job)
+	now := time.Now()
+	job.FinishedAt = &now
+	job.Rows = rows
+	if err != nil {
+		log.Printf("export %s failed: %v", job.ID, err)
+		job.Status = domain.JobStatusFailed
+		job.Error = "export failed"
+	} else {
+		job.Status = domain.JobStatusCompleted
+	}
+	s.save(ctx, job)
+}
+
+func (s *DefaultExportService) writeFile(ctx context.Context, job domain.ExportJob) (int, error) {
+	f, err := s.Storage.Create(exportFileName(job))
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := s.export(ctx, job.UserID, job.Request(), f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return rows, err
+}
+
+func (s *DefaultExportService) save(ctx context.Context, job domain.ExportJob) {
+	if err := s.Jobs.Update(ctx, job); err != nil {
+		log.Printf("failed to update export %s: %v", job.ID, err)
+	}
+}
+
+func (s *DefaultExportService) GetExport(ctx context.Context, id string, userID string) (domain.ExportJob, error) {
+	job, err := s.Jobs.GetByID(ctx, id)
+	if err != nil {
+		return domain.ExportJob{}, err
+	}
+	if job.UserID != userID {
+		return domain.ExportJob{}, domain.ErrExportNotFound
+	}
+	return job, nil
+}
+
+func (s *DefaultExportService) OpenExport(ctx context.Context, id string, userID string) (domain.ExportJob, io.ReadCloser, error) {
+	job, err := s.GetExport(ctx, id, userID)
+	if err != nil {
+		return domain.ExportJob{}, nil, err
+	}
+	if job.Status != domain.JobStatusCompleted {
+		return job, nil, domain.ErrExportNotReady
+	}
+
+	f, err := s.Storage.Open(exportFileName(job))
+	if err != nil {
+		return job, nil, err
+	}
+	return job, f, nil
+}
+
+func exportFileName(job domain.ExportJob) string {
+	return job.ID + "." + string(job.Format)
+}
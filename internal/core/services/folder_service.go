@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type DefaultFolderService struct {
+	Repo ports.FolderRepository
+}
+
+func NewFolderService(repo ports.FolderRepository) ports.FolderService {
+	return &DefaultFolderService{Repo: repo}
+}
+
+func (s *DefaultFolderService) CreateFolder(ctx context.Context, userID string, name string) (domain.Folder, error) {
+	if userID == "" {
+		return domain.Folder{}, errors.New("userID is required")
+	}
+
+	name, err := domain.NormalizeFolderName(name)
+	if err != nil {
+		return domain.Folder{}, err
+	}
+
+	return s.Repo.Create(ctx, domain.Folder{
+		ID:     uuid.New().String(),
+		UserID: userID,
+		Name:   name,
+	})
+}
+
+func (s *DefaultFolderService) RenameFolder(ctx context.Context, userID string, id string, name string) (domain.Folder, error) {
+	name, err := domain.NormalizeFolderName(name)
+	if err != nil {
+		return domain.Folder{}, err
+	}
+	return s.Repo.Rename(ctx, userID, id, name)
+}
+
+func (s *DefaultFolderService) ListFolders(ctx context.Context, userID string) ([]domain.Folder, error) {
+	return s.Repo.List(ctx, userID)
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+// importChunkSize is the number of links written per multi-row INSERT. Job
+// progress is persisted after every chunk.
+const importChunkSize = 500
+
+type DefaultImportService struct {
+	Jobs    ports.ImportJobRepository
+	Links   ports.LinkRepository
+	Domains ports.DomainRepository
+	Tags    ports.TagRepository
+	Parsers map[domain.ImportFormat]ports.ImportParser
+	// Blocklist rejects imported links with malicious targets; nil allows
+	// every target.
+	Blocklist ports.Blocklist
+	// Paths is told when nested slugs or prefix links were imported.
+	Paths ports.PathInvalidator
+}
+
+func NewImportService(jobs ports.ImportJobRepository, links ports.LinkRepository, domains ports.DomainRepository, tags ports.TagRepository, parsers map[domain.ImportFormat]ports.ImportParser, blocklist ports.Blocklist, paths ports.PathInvalidator) ports.ImportService {
+	return &DefaultImportService{Jobs: jobs, Links: links, Domains: domains, Tags: tags, Parsers: parsers, Blocklist: blocklist, Paths: paths}
+}
+
+func (s *DefaultImportService) StartImport(ctx context.Context, userID string, format domain.ImportFormat, linkDomain string, r io.Reader) (domain.ImportJob, error) {
+	if userID == "" {
+		return domain.ImportJob{}, errors.New("userID is required")
+	}
+
+	parser, ok := s.Parsers[format]
+	if !ok {
+		return domain.ImportJob{}, domain.ErrUnsupportedFormat
+	}
+
+	linkDomain, err := resolveDomain(ctx, s.Domains, linkDomain, userID)
+	if err != nil {
+		return domain.ImportJob{}, err
+	}
+
+	records, err := parser.Parse(r)
+	if err != nil {
+		return domain.ImportJob{}, err
+	}
+	if len(records) == 0 {
+		return domain.ImportJob{}, domain.ErrEmptyImport
+	}
+
+	job, err := s.Jobs.Create(ctx, domain.ImportJob{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Format:    format,
+		Domain:    linkDomain,
+		Status:    domain.JobStatusPending,
+		Total:     len(records),
+		Conflicts: []domain.ImportIssue{},
+		Errors:    []domain.ImportIssue{},
+	})
+	if err != nil {
+		return domain.ImportJob{}, err
+	}
+
+	// The import outlives the request, but its links are still created by
+	// whoever started it.
+	go s.run(domain.ContextWithActor(context.Background(), domain.ActorFromContext(ctx)), job, records)
+
+	return job, nil
+}
+
+func (s *DefaultImportService) GetImport(ctx context.Context, id string, userID string) (domain.ImportJob, error) {
+	job, err := s.Jobs.GetByID(ctx, id)
+	if err != nil {
+		return domain.ImportJob{}, err
+	}
+	if job.UserID != userID {
+		return domain.ImportJob{}, domain.ErrImportNotFound
+	}
+	return job, nil
+}
+
+func (s *DefaultImportService) run(ctx context.Context, job domain.ImportJob, records []domain.ImportRecord) {
+	job.Status = domain.JobStatusRunning
+	s.saveProgress(ctx, job)
+
+	userID := job.UserID
+	seen := make(map[string]struct{}, len(records))
+
+	for start := 0; start < len(records); start += importChunkSize {
+		end := min(start+importChunkSize, len(records))
+
+		links := make([]domain.Link, 0, end-start)
+		changes := make([]domain.LinkChange, 0, end-start)
+		rows := make(map[string]domain.ImportRecord, end-start)
+		for _, record := range records[start:end] {
+			record.Title = domain.TruncateTitle(record.Title)
+			input, err := normalizeLinkInput(domain.LinkInput{
+				TargetURL:    record.TargetURL,
+				CustomSlug:   record.Slug,
+				ForwardPath:  record.ForwardPath,
+				Rules:        record.Rules,
+				QueryOptions: record.QueryOptions,
+				Schedule:     record.Schedule,
+				MaxUses:      record.MaxUses,
+				Interstitial: record.Interstitial,
+				LinkMetadata: record.LinkMetadata,
+			})
+			if err == nil && record.Slug == "" {
+				err = domain.ErrInvalidSlug
+			}
+			var variants []domain.Variant
+			if err == nil && len(record.Variants) > 0 {
+				variants, err = domain.NormalizeVariants(record.Variants)
+			}
+			var token string
+			if err == nil {
+				token, err = postbackToken("", variants)
+			}
+			if err == nil {
+				err = checkTargets(s.Blocklist, domain.Link{TargetURL: input.TargetURL, Rules: input.Rules, Variants: variants, Schedule: input.Schedule}.Targets()...)
+			}
+			if err != nil {
+				job.Errors = append(job.Errors, domain.ImportIssue{Row: record.Row, Slug: record.Slug, Error: err.Error()})
+				continue
+			}
+			if _, dup := seen[record.Slug]; dup {
+				job.Conflicts = append(job.Conflicts, domain.ImportIssue{Row: record.Row, Slug: record.Slug, Error: domain.ErrDuplicateInBatch.Error()})
+				continue
+			}
+			seen[record.Slug] = struct{}{}
+
+			link := newLink(input, job.Domain, &userID)
+			link.Variants, link.PostbackToken = variants, token
+			link.Clicks, link.CreatedAt = record.Clicks, record.CreatedAt
+			rows[link.ID] = record
+			links = append(links, link)
+			changes = append(changes, newLinkChange(ctx, domain.ChangeCreate, domain.Link{}, link))
+		}
+
+		saved, conflicts, err := s.Links.SaveMany(ctx, links, false, changes)
+		if err != nil {
+			log.Printf("import %s failed: %v", job.ID, err)
+			job.Status = domain.JobStatusFailed
+			s.finish(ctx, job)
+			return
+		}
+		for _, link := range conflicts {
+			record := rows[link.ID]
+			job.Conflicts = append(job.Conflicts, domain.ImportIssue{Row: record.Row, Slug: record.Slug, Error: domain.ErrSlugTaken.Error()})
+		}
+
+		if slices.ContainsFunc(saved, isPathLink) {
+			s.Paths.InvalidatePaths()
+		}
+		if err := s.attachTags(ctx, userID, saved, rows); err != nil {
+			log.Printf("failed to tag links for import %s: %v", job.ID, err)
+		}
+
+		job.Imported += len(saved)
+		job.Processed = end
+		s.saveProgress(ctx, job)
+	}
+
+	job.Status = domain.JobStatusCompleted
+	s.finish(ctx, job)
+}
+
+// attachTags tags the saved links of a chunk with the tags of their source
+// records, creating any tags the user does not have yet. Invalid tag names are
+// skipped rather than failing the import.
+func (s *DefaultImportService) attachTags(ctx context.Context, userID string, saved []domain.Link, rows map[string]domain.ImportRecord) error {
+	linkTags := make(map[string][]string, len(saved))
+	var names []string
+	seen := make(map[string]struct{})
+	for _, link := range saved {
+		for _, raw := range rows[link.ID].Tags {
+			name, err := domain.NormalizeTagName(raw)
+			if err != nil {
+				continue
+			}
+			key := strings.ToLower(name)
+			linkTags[link.ID] = append(linkTags[link.ID], key)
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				names = append(names, name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	tags, err := s.Tags.EnsureTags(ctx, userID, names)
+	if err != nil {
+		return err
+	}
+	tagIDs := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		tagIDs[strings.ToLower(tag.Name)] = tag.ID
+	}
+
+	var linkIDs, pairTagIDs []string
+	for linkID, keys := range linkTags {
+		for _, key := range keys {
+			if id, ok := tagIDs[key]; ok {
+				linkIDs = append(linkIDs, linkID)
+				pairTagIDs = append(pairTagIDs, id)
+			}
+		}
+	}
+	return s.Tags.AttachTags(ctx, linkIDs, pairTagIDs)
+}
+
+func (s *DefaultImportService) finish(ctx context.Context, job domain.ImportJob) {
+	now := time.Now()
+	job.FinishedAt = &now
+	s.saveProgress(ctx, job)
+}
+
+func (s *DefaultImportService) saveProgress(ctx context.Context, job domain.ImportJob) {
+	if err := s.Jobs.UpdateProgress(ctx, job); err != nil {
+		log.Printf("failed to update progress for import %s: %v", job.ID, err)
+	}
+}
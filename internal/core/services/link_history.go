@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+// newLinkChange describes a mutation of a link for the audit log, made by the
+// actor of ctx. before is the zero Link for a creation and after for a
+// deletion. When the mutation replaces the link's settings, the old ones are
+// kept as a version.
+func newLinkChange(ctx context.Context, changeType domain.LinkChangeType, before domain.Link, after domain.Link) domain.LinkChange {
+	link := after
+	if link.ID == "" {
+		link = before
+	}
+	actor := domain.ActorFromContext(ctx)
+	change := domain.LinkChange{
+		ID:        uuid.New().String(),
+		LinkID:    link.ID,
+		Domain:    link.Domain,
+		ShortID:   link.ShortID,
+		Type:      changeType,
+		ActorType: actor.Type,
+		ActorID:   actor.ID,
+		IP:        actor.IP,
+	}
+	change.Before, change.After = domain.DiffLinks(before, after)
+	if before.ID != "" && after.ID != "" && !domain.SameSettings(before, after) {
+		previous := before.Settings()
+		change.Previous = &previous
+	}
+	return change
+}
+
+func (s *DefaultLinkService) LinkHistory(ctx context.Context, userID string, linkDomain string, shortID string, filter domain.LinkChangeFilter) (domain.LinkChangePage, error) {
+	link, err := getOwnedLink(ctx, s.Repo, userID, linkDomain, shortID)
+	if err != nil {
+		return domain.LinkChangePage{}, err
+	}
+	filter.LinkID = link.ID
+	filter.Limit, filter.Offset = clampPage(filter.Limit, filter.Offset)
+
+	page, err := s.Repo.ListChanges(ctx, filter)
+	if err != nil {
+		return domain.LinkChangePage{}, err
+	}
+	for i := range page.Changes {
+		redactActor(&page.Changes[i], userID)
+	}
+	return page, nil
+}
+
+// redactActor hides who made a change, and from where, unless the user
+// reading the history made it. System changes keep their ID, which names the
+// job rather than a person.
+func redactActor(change *domain.LinkChange, userID string) {
+	if change.ActorType == domain.ActorUser && change.ActorID == userID {
+		return
+	}
+	change.IP = ""
+	if change.ActorType != domain.ActorSystem {
+		change.ActorID = ""
+	}
+}
@@ -5,6 +5,11 @@ import (
 	"encoding/json"
 	"errors"
 	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/esdrassantos06/go-shortener/internal/core/domain"
 	"github.com/esdrassantos06/go-shortener/internal/core/ports"
@@ -12,104 +17,1049 @@ import (
 )
 
 type DefaultLinkService struct {
-	Repo  ports.LinkRepository
-	Cache ports.CacheRepository
+	Repo    ports.LinkRepository
+	Cache   ports.CacheRepository
+	Domains ports.DomainRepository
+	Clicks  ports.ClickRepository
+	Tags    ports.TagRepository
+	Folders ports.FolderRepository
+	Titles  ports.TitleFetcher
+	// Blocklist rejects malicious targets; nil allows every target.
+	Blocklist ports.Blocklist
+
+	paths *pathIndex
+	// scheduled wakes RunScheduler when a change is scheduled.
+	scheduled chan struct{}
 }
 
-func NewLinkService(repo ports.LinkRepository, cache ports.CacheRepository) ports.LinkService {
-	return &DefaultLinkService{Repo: repo, Cache: cache}
+func NewLinkService(repo ports.LinkRepository, cache ports.CacheRepository, domains ports.DomainRepository, clicks ports.ClickRepository, tags ports.TagRepository, folders ports.FolderRepository, titles ports.TitleFetcher, blocklist ports.Blocklist) ports.LinkService {
+	return &DefaultLinkService{Repo: repo, Cache: cache, Domains: domains, Clicks: clicks, Tags: tags, Folders: folders, Titles: titles, Blocklist: blocklist, paths: newPathIndex(repo), scheduled: make(chan struct{}, 1)}
 }
 
-func (s *DefaultLinkService) ShortenURL(ctx context.Context, targetURL string, customSlug string, userID *string) (domain.Link, error) {
+func (s *DefaultLinkService) ShortenURL(ctx context.Context, input domain.LinkInput, userID *string) (domain.Link, error) {
 	if userID == nil || *userID == "" {
 		return domain.Link{}, errors.New("userID is required")
 	}
 
+	input, err := normalizeLinkInput(input)
+	if err != nil {
+		return domain.Link{}, err
+	}
+	if err := checkTargets(s.Blocklist, inputTargets(input)...); err != nil {
+		return domain.Link{}, err
+	}
+
+	linkDomain, err := resolveDomain(ctx, s.Domains, input.Domain, *userID)
+	if err != nil {
+		return domain.Link{}, err
+	}
+
+	// Only a bare target is reused, so the settings of a request are never
+	// dropped for those of an older link. Limited links are handed out one by
+	// one, so they are never reused either.
+	if input.ReuseExisting && input.CustomSlug == "" && !input.HasSettings() {
+		existing, err := s.Repo.FindByTargetHash(ctx, *userID, linkDomain, domain.TargetHash(input.TargetURL))
+		if err == nil {
+			return existing, nil
+		}
+		if !errors.Is(err, domain.ErrLinkNotFound) {
+			return domain.Link{}, err
+		}
+	}
+
+	link := newLink(input, linkDomain, userID)
+	link, err = s.Repo.Save(ctx, link, newLinkChange(ctx, domain.ChangeCreate, domain.Link{}, link))
+	if err != nil {
+		return domain.Link{}, err
+	}
+
+	if isPathLink(link) {
+		s.paths.invalidate()
+	}
+	go s.cacheLink(link, blocklistCheck{})
+	go s.fillTitles([]domain.Link{link})
+
+	return link, nil
+}
+
+func (s *DefaultLinkService) ShortenBulk(ctx context.Context, inputs []domain.LinkInput, atomic bool, userID *string) ([]domain.BulkLinkResult, error) {
+	if userID == nil || *userID == "" {
+		return nil, errors.New("userID is required")
+	}
+
+	results := make([]domain.BulkLinkResult, len(inputs))
+	links := make([]domain.Link, 0, len(inputs))
+	indexByID := make(map[string]int, len(inputs))
+	domains := make(map[string]string)
+	seen := make(map[string]int, len(inputs))
+	failed := false
+
+	for i, input := range inputs {
+		results[i].Index = i
+
+		input, err := normalizeLinkInput(input)
+		if err != nil {
+			results[i].Err = err
+			failed = true
+			continue
+		}
+		if err := checkTargets(s.Blocklist, inputTargets(input)...); err != nil {
+			results[i].Err = err
+			failed = true
+			continue
+		}
+
+		linkDomain, ok := domains[input.Domain]
+		if !ok {
+			var err error
+			if linkDomain, err = resolveDomain(ctx, s.Domains, input.Domain, *userID); err != nil {
+				results[i].Err = err
+				failed = true
+				continue
+			}
+			domains[input.Domain] = linkDomain
+		}
+
+		link := newLink(input, linkDomain, userID)
+		key := linkKey(link.Domain, link.ShortID)
+		if _, dup := seen[key]; dup {
+			results[i].Err = domain.ErrDuplicateInBatch
+			failed = true
+			continue
+		}
+		seen[key] = i
+
+		indexByID[link.ID] = i
+		links = append(links, link)
+	}
+
+	if atomic && failed {
+		return results, nil
+	}
+
+	changes := make([]domain.LinkChange, len(links))
+	for i, link := range links {
+		changes[i] = newLinkChange(ctx, domain.ChangeCreate, domain.Link{}, link)
+	}
+	saved, conflicts, err := s.Repo.SaveMany(ctx, links, atomic, changes)
+	if err != nil && !errors.Is(err, domain.ErrSlugTaken) {
+		return nil, err
+	}
+	for _, link := range conflicts {
+		results[indexByID[link.ID]].Err = domain.ErrSlugTaken
+	}
+	for i := range saved {
+		results[indexByID[saved[i].ID]].Link = &saved[i]
+		if isPathLink(saved[i]) {
+			s.paths.invalidate()
+		}
+	}
+
+	go func(links []domain.Link) {
+		for _, link := range links {
+			s.cacheLink(link, blocklistCheck{})
+		}
+		s.fillTitles(links)
+	}(saved)
+
+	return results, nil
+}
+
+func newLink(input domain.LinkInput, linkDomain string, userID *string) domain.Link {
 	var linkID, shortID string
-	if customSlug == "" {
+	if input.CustomSlug == "" {
 		linkUUID := uuid.New().String()
 		linkID = linkUUID
 		shortID = linkUUID[:6]
 	} else {
-		shortID = customSlug
+		shortID = input.CustomSlug
 		linkID = uuid.New().String()
 	}
 
 	link := domain.Link{
-		ID:        linkID,
-		ShortID:   shortID,
-		TargetURL: targetURL,
-		UserID:    userID,
-		Status:    domain.StatusActive,
+		ID:           linkID,
+		ShortID:      shortID,
+		Domain:       linkDomain,
+		TargetURL:    input.TargetURL,
+		TargetHash:   domain.TargetHash(input.TargetURL),
+		UserID:       userID,
+		Status:       domain.StatusActive,
+		ForwardPath:  input.ForwardPath,
+		Rules:        input.Rules,
+		QueryOptions: input.QueryOptions,
+		Schedule:     input.Schedule,
+		Interstitial: input.Interstitial,
+		LinkMetadata: input.LinkMetadata,
+	}
+	if input.MaxUses > 0 {
+		maxUses, usesLeft := input.MaxUses, input.MaxUses
+		link.MaxUses, link.UsesLeft = &maxUses, &usesLeft
+	}
+	return link
+}
+
+// resolveDomain checks that a custom domain belongs to the user and has been
+// verified, returning its normalized hostname. An empty host is the default
+// domain.
+func resolveDomain(ctx context.Context, domains ports.DomainRepository, host string, userID string) (string, error) {
+	if host == "" {
+		return "", nil
+	}
+
+	hostname, err := domain.NormalizeHostname(host)
+	if err != nil {
+		return "", err
+	}
+
+	d, err := domains.GetClaim(ctx, hostname, userID)
+	if err != nil {
+		return "", err
+	}
+	if !d.Verified() {
+		return "", domain.ErrDomainNotVerified
+	}
+	return d.Hostname, nil
+}
+
+func validateLinkInput(input domain.LinkInput) error {
+	if err := validateTargetURL(input.TargetURL); err != nil {
+		return err
+	}
+
+	if err := input.LinkMetadata.Validate(); err != nil {
+		return err
+	}
+
+	if err := domain.ValidateMaxUses(input.MaxUses); err != nil {
+		return err
+	}
+
+	if input.CustomSlug == "" {
+		return nil
+	}
+	if domain.IsReservedSlug(input.CustomSlug) {
+		return domain.ErrReservedSlug
+	}
+	// Slugs may be nested ("guide/v2"), but every segment must be a plain
+	// name so the slug maps to exactly one request path.
+	for _, seg := range strings.Split(input.CustomSlug, "/") {
+		if seg == "" || seg == "." || seg == ".." {
+			return domain.ErrInvalidSlug
+		}
+		for _, r := range seg {
+			if !isSlugRune(r) {
+				return domain.ErrInvalidSlug
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeLinkInput validates a new link's input and normalizes its
+// settings. Targets are not checked against the blocklist.
+func normalizeLinkInput(input domain.LinkInput) (domain.LinkInput, error) {
+	if err := validateLinkInput(input); err != nil {
+		return input, err
+	}
+
+	var err error
+	if input.Rules, err = domain.NormalizeRoutingRules(input.Rules); err != nil {
+		return input, err
+	}
+	if input.QueryOptions, err = input.QueryOptions.Normalize(); err != nil {
+		return input, err
+	}
+	if input.Schedule, err = input.Schedule.Normalize(); err != nil {
+		return input, err
+	}
+	if input.Interstitial, err = input.Interstitial.Normalize(); err != nil {
+		return input, err
+	}
+	return input, nil
+}
+
+// inputTargets returns the URLs a new link could send visitors to.
+func inputTargets(input domain.LinkInput) []string {
+	return domain.Link{TargetURL: input.TargetURL, Rules: input.Rules, Schedule: input.Schedule}.Targets()
+}
+
+// isPathLink reports whether a link belongs in the path index.
+func isPathLink(link domain.Link) bool {
+	return link.ForwardPath || strings.Contains(link.ShortID, "/")
+}
+
+func validateTargetURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return domain.ErrInvalidTargetURL
 	}
+	return nil
+}
+
+func isSlugRune(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_' || r == '.'
+}
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 200
+)
+
+func (s *DefaultLinkService) ListLinks(ctx context.Context, userID string, filter domain.LinkFilter) (domain.LinkPage, error) {
+	filter.Limit, filter.Offset = clampPage(filter.Limit, filter.Offset)
+	return s.Repo.List(ctx, userID, filter)
+}
+
+// clampPage applies the default and maximum page size to a listing.
+func clampPage(limit int, offset int) (int, int) {
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	return min(limit, maxListLimit), max(offset, 0)
+}
+
+func (s *DefaultLinkService) SearchLinks(ctx context.Context, userID string, search domain.LinkSearch) (domain.LinkPage, error) {
+	search.Query = strings.TrimSpace(search.Query)
+	if search.Query == "" {
+		return domain.LinkPage{}, domain.ErrEmptySearch
+	}
+	search.Limit, search.Offset = clampPage(search.Limit, search.Offset)
+	return s.Repo.Search(ctx, &userID, search)
+}
+
+func (s *DefaultLinkService) SetLinkTags(ctx context.Context, userID string, linkDomain string, shortID string, names []string) (domain.Link, error) {
+	link, err := getOwnedLink(ctx, s.Repo, userID, linkDomain, shortID)
+	if err != nil {
+		return domain.Link{}, err
+	}
+
+	names, err = normalizeTagNames(names)
+	if err != nil {
+		return domain.Link{}, err
+	}
+
+	tags, err := s.Tags.EnsureTags(ctx, userID, names)
+	if err != nil {
+		return domain.Link{}, err
+	}
+	if link.Tags, err = s.Tags.LinkTags(ctx, link.ID); err != nil {
+		return domain.Link{}, err
+	}
+
+	updated := link
+	tagIDs := make([]string, len(tags))
+	updated.Tags = make([]string, len(tags))
+	for i, tag := range tags {
+		tagIDs[i] = tag.ID
+		updated.Tags[i] = tag.Name
+	}
+	if err := s.Tags.SetLinkTags(ctx, link.ID, tagIDs, newLinkChange(ctx, domain.ChangeUpdate, link, updated)); err != nil {
+		return domain.Link{}, err
+	}
+	return updated, nil
+}
+
+func (s *DefaultLinkService) SetLinkFolder(ctx context.Context, userID string, linkDomain string, shortID string, folderID *string) (domain.Link, error) {
+	link, err := getOwnedLink(ctx, s.Repo, userID, linkDomain, shortID)
+	if err != nil {
+		return domain.Link{}, err
+	}
+
+	if folderID != nil {
+		folder, err := s.Folders.GetByID(ctx, *folderID)
+		if err != nil {
+			return domain.Link{}, err
+		}
+		if folder.UserID != userID {
+			return domain.Link{}, domain.ErrFolderNotFound
+		}
+	}
+
+	updated := link
+	updated.FolderID = folderID
+	if err := s.Repo.SetFolder(ctx, link.ID, folderID, newLinkChange(ctx, domain.ChangeUpdate, link, updated)); err != nil {
+		return domain.Link{}, err
+	}
+	return updated, nil
+}
+
+func (s *DefaultLinkService) GetLink(ctx context.Context, userID string, linkDomain string, shortID string) (domain.Link, error) {
+	return getOwnedLink(ctx, s.Repo, userID, linkDomain, shortID)
+}
 
-	link, err := s.Repo.Save(ctx, link)
+func (s *DefaultLinkService) UpdateLink(ctx context.Context, userID string, linkDomain string, shortID string, update domain.LinkUpdate) (domain.Link, error) {
+	link, err := getOwnedLink(ctx, s.Repo, userID, linkDomain, shortID)
 	if err != nil {
 		return domain.Link{}, err
 	}
 
-	go s.cacheLink(link)
+	updated := link
+	update.Apply(&updated.LinkMetadata)
+	if err := updated.LinkMetadata.Validate(); err != nil {
+		return domain.Link{}, err
+	}
+
+	if err := s.Repo.UpdateMetadata(ctx, updated, newLinkChange(ctx, domain.ChangeUpdate, link, updated)); err != nil {
+		return domain.Link{}, err
+	}
+	return updated, nil
+}
+
+// SetLinkRules replaces a link's routing rules and drops its cached entry so
+// the next resolve picks them up.
+func (s *DefaultLinkService) SetLinkRules(ctx context.Context, userID string, linkDomain string, shortID string, rules []domain.RoutingRule) (domain.Link, error) {
+	link, err := getOwnedLink(ctx, s.Repo, userID, linkDomain, shortID)
+	if err != nil {
+		return domain.Link{}, err
+	}
+
+	rules, err = domain.NormalizeRoutingRules(rules)
+	if err != nil {
+		return domain.Link{}, err
+	}
+	updated := link
+	updated.Rules = rules
+	if err := checkTargets(s.Blocklist, updated.Targets()...); err != nil {
+		return domain.Link{}, err
+	}
+
+	if err := s.Repo.SetRules(ctx, link.ID, rules, newLinkChange(ctx, domain.ChangeUpdate, link, updated)); err != nil {
+		return domain.Link{}, err
+	}
+	if err := s.Cache.Delete(ctx, "url"+linkKey(link.Domain, link.ShortID)); err != nil {
+		log.Printf("failed to drop cached link %s: %v", link.ID, err)
+	}
+	return updated, nil
+}
+
+func (s *DefaultLinkService) SetLinkPathForwarding(ctx context.Context, userID string, linkDomain string, shortID string, enabled bool) (domain.Link, error) {
+	link, err := getOwnedLink(ctx, s.Repo, userID, linkDomain, shortID)
+	if err != nil {
+		return domain.Link{}, err
+	}
+
+	updated := link
+	updated.ForwardPath = enabled
+	if err := s.Repo.SetForwardPath(ctx, link.ID, enabled, newLinkChange(ctx, domain.ChangeUpdate, link, updated)); err != nil {
+		return domain.Link{}, err
+	}
+	s.paths.invalidate()
+	if err := s.Cache.Delete(ctx, "url"+linkKey(link.Domain, link.ShortID)); err != nil {
+		log.Printf("failed to drop cached link %s: %v", link.ID, err)
+	}
+	return updated, nil
+}
+
+func (s *DefaultLinkService) SetLinkSchedule(ctx context.Context, userID string, linkDomain string, shortID string, schedule domain.LinkSchedule) (domain.Link, error) {
+	link, err := getOwnedLink(ctx, s.Repo, userID, linkDomain, shortID)
+	if err != nil {
+		return domain.Link{}, err
+	}
+
+	schedule, err = schedule.Normalize()
+	if err != nil {
+		return domain.Link{}, err
+	}
+	updated := link
+	updated.Schedule = schedule
+	if err := checkTargets(s.Blocklist, updated.Targets()...); err != nil {
+		return domain.Link{}, err
+	}
+
+	if err := s.Repo.SetSchedule(ctx, link.ID, schedule, newLinkChange(ctx, domain.ChangeUpdate, link, updated)); err != nil {
+		return domain.Link{}, err
+	}
+	if err := s.Cache.Delete(ctx, "url"+linkKey(link.Domain, link.ShortID)); err != nil {
+		log.Printf("failed to drop cached link %s: %v", link.ID, err)
+	}
+	return updated, nil
+}
 
+func (s *DefaultLinkService) SetLinkMaxUses(ctx context.Context, userID string, linkDomain string, shortID string, maxUses int) (domain.Link, error) {
+	link, err := getOwnedLink(ctx, s.Repo, userID, linkDomain, shortID)
+	if err != nil {
+		return domain.Link{}, err
+	}
+
+	if err := domain.ValidateMaxUses(maxUses); err != nil {
+		return domain.Link{}, err
+	}
+
+	updated := link
+	updated.MaxUses, updated.UsesLeft = nil, nil
+	if maxUses > 0 {
+		usesLeft := maxUses
+		updated.MaxUses, updated.UsesLeft = &maxUses, &usesLeft
+	}
+	if err := s.Repo.SetMaxUses(ctx, link.ID, updated.MaxUses, newLinkChange(ctx, domain.ChangeUpdate, link, updated)); err != nil {
+		return domain.Link{}, err
+	}
+	if err := s.Cache.Delete(ctx, "url"+linkKey(link.Domain, link.ShortID), "uses:"+link.ID); err != nil {
+		log.Printf("failed to drop cached link %s: %v", link.ID, err)
+	}
+	return updated, nil
+}
+
+func (s *DefaultLinkService) SetLinkInterstitial(ctx context.Context, userID string, linkDomain string, shortID string, interstitial domain.Interstitial) (domain.Link, error) {
+	link, err := getOwnedLink(ctx, s.Repo, userID, linkDomain, shortID)
+	if err != nil {
+		return domain.Link{}, err
+	}
+
+	interstitial, err = interstitial.Normalize()
+	if err != nil {
+		return domain.Link{}, err
+	}
+
+	updated := link
+	updated.Interstitial = interstitial
+	if err := s.Repo.SetInterstitial(ctx, link.ID, interstitial, newLinkChange(ctx, domain.ChangeUpdate, link, updated)); err != nil {
+		return domain.Link{}, err
+	}
+	if err := s.Cache.Delete(ctx, "url"+linkKey(link.Domain, link.ShortID)); err != nil {
+		log.Printf("failed to drop cached link %s: %v", link.ID, err)
+	}
+	return updated, nil
+}
+
+func (s *DefaultLinkService) SetLinkQueryOptions(ctx context.Context, userID string, linkDomain string, shortID string, options domain.QueryOptions) (domain.Link, error) {
+	link, err := getOwnedLink(ctx, s.Repo, userID, linkDomain, shortID)
+	if err != nil {
+		return domain.Link{}, err
+	}
+
+	options, err = options.Normalize()
+	if err != nil {
+		return domain.Link{}, err
+	}
+
+	updated := link
+	updated.QueryOptions = options
+	if err := s.Repo.SetQueryOptions(ctx, link.ID, options, newLinkChange(ctx, domain.ChangeUpdate, link, updated)); err != nil {
+		return domain.Link{}, err
+	}
+	if err := s.Cache.Delete(ctx, "url"+linkKey(link.Domain, link.ShortID)); err != nil {
+		log.Printf("failed to drop cached link %s: %v", link.ID, err)
+	}
+	return updated, nil
+}
+
+const (
+	maxTargetLookupResults = 1000
+	maxTargetRewriteLinks  = 5000
+)
+
+var errLookupFull = errors.New("target lookup result limit reached")
+
+func (s *DefaultLinkService) FindLinksByTarget(ctx context.Context, userID *string, lookup domain.TargetLookup) (domain.TargetLookupResult, error) {
+	result := domain.TargetLookupResult{Links: make([]domain.Link, 0)}
+	err := s.streamByTarget(ctx, userID, lookup, func(link domain.Link) error {
+		if len(result.Links) == maxTargetLookupResults {
+			result.Truncated = true
+			return errLookupFull
+		}
+		result.Links = append(result.Links, link)
+		return nil
+	})
+	if err != nil && !errors.Is(err, errLookupFull) {
+		return domain.TargetLookupResult{}, err
+	}
+	return result, nil
+}
+
+// RewriteTargets replaces the normalized From prefix of every matching target
+// with To. Rewritten targets keep their normalized form.
+func (s *DefaultLinkService) RewriteTargets(ctx context.Context, userID string, rewrite domain.TargetRewrite) ([]domain.TargetChange, error) {
+	if err := validateTargetURL(rewrite.From); err != nil {
+		return nil, err
+	}
+	if err := validateTargetURL(rewrite.To); err != nil {
+		return nil, err
+	}
+
+	from := domain.NormalizeTargetURL(rewrite.From)
+	changes := make([]domain.TargetChange, 0)
+	err := s.streamByTarget(ctx, &userID, domain.TargetLookup{URL: rewrite.From}, func(link domain.Link) error {
+		newTarget := joinTarget(rewrite.To, strings.TrimPrefix(domain.NormalizeTargetURL(link.TargetURL), from))
+		if domain.NormalizeTargetURL(newTarget) == domain.NormalizeTargetURL(link.TargetURL) {
+			return nil
+		}
+		if err := validateTargetURL(newTarget); err != nil {
+			return err
+		}
+		if err := checkTargets(s.Blocklist, newTarget); err != nil {
+			return err
+		}
+		if len(changes) == maxTargetRewriteLinks {
+			return domain.ErrTooManyMatches
+		}
+		changes = append(changes, domain.TargetChange{Link: link, OldTarget: link.TargetURL, NewTarget: newTarget})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range changes {
+		changes[i].Link.TargetURL = changes[i].NewTarget
+	}
+	if rewrite.DryRun || len(changes) == 0 {
+		return changes, nil
+	}
+
+	history := make([]domain.LinkChange, len(changes))
+	for i, change := range changes {
+		before := change.Link
+		before.TargetURL = change.OldTarget
+		history[i] = newLinkChange(ctx, domain.ChangeUpdate, before, change.Link)
+	}
+	if err := s.Repo.UpdateTargets(ctx, changes, history); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, len(changes))
+	for i, change := range changes {
+		keys[i] = "url" + linkKey(change.Link.Domain, change.Link.ShortID)
+	}
+	if err := s.Cache.Delete(ctx, keys...); err != nil {
+		log.Printf("failed to invalidate %d rewritten links: %v", len(keys), err)
+	}
+	return changes, nil
+}
+
+// joinTarget appends the unmatched remainder of a target to a rewrite
+// destination without doubling the "/" or "?" separators.
+func joinTarget(to string, rest string) string {
+	switch {
+	case strings.HasPrefix(rest, "/") && strings.HasSuffix(to, "/"):
+		rest = rest[1:]
+	case strings.HasPrefix(rest, "?") && strings.Contains(to, "?"):
+		rest = "&" + rest[1:]
+	}
+	return to + rest
+}
+
+// streamByTarget calls fn for each link matching the lookup. Hosts are
+// matched in the database; URL prefixes are then checked on the normalized
+// targets.
+func (s *DefaultLinkService) streamByTarget(ctx context.Context, userID *string, lookup domain.TargetLookup, fn func(domain.Link) error) error {
+	switch {
+	case lookup.URL != "" && lookup.Domain == "":
+		if err := validateTargetURL(lookup.URL); err != nil {
+			return err
+		}
+		return s.Repo.StreamByTargetHost(ctx, userID, domain.TargetHost(lookup.URL), false, func(link domain.Link) error {
+			if !domain.HasTargetPrefix(link.TargetURL, lookup.URL) {
+				return nil
+			}
+			return fn(link)
+		})
+	case lookup.Domain != "" && lookup.URL == "":
+		host, err := domain.NormalizeHostname(lookup.Domain)
+		if err != nil {
+			return err
+		}
+		return s.Repo.StreamByTargetHost(ctx, userID, host, true, fn)
+	default:
+		return domain.ErrInvalidLookup
+	}
+}
+
+// getOwnedLink loads a link and checks it belongs to the user. Links owned by
+// someone else are reported as not found.
+func getOwnedLink(ctx context.Context, repo ports.LinkRepository, userID string, linkDomain string, shortID string) (domain.Link, error) {
+	link, err := repo.GetByShortID(ctx, linkDomain, shortID)
+	if err != nil {
+		return domain.Link{}, err
+	}
+	if link.UserID == nil || *link.UserID != userID {
+		return domain.Link{}, domain.ErrLinkNotFound
+	}
 	return link, nil
 }
 
+// normalizeTagNames validates tag names and drops case-insensitive duplicates,
+// keeping the first spelling.
+func normalizeTagNames(names []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(names))
+	out := make([]string, 0, len(names))
+	for _, raw := range names {
+		name, err := domain.NormalizeTagName(raw)
+		if err != nil {
+			return nil, err
+		}
+		key := strings.ToLower(name)
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		seen[key] = struct{}{}
+		out = append(out, name)
+	}
+	return out, nil
+}
+
 type cachedLink struct {
-	TargetURL string            `json:"target_url"`
-	Status    domain.LinkStatus `json:"status"`
+	ID        string               `json:"id"`
+	TargetURL string               `json:"target_url"`
+	Status    domain.LinkStatus    `json:"status"`
+	Rules     []domain.RoutingRule `json:"rules,omitempty"`
+	Variants  []domain.Variant     `json:"variants,omitempty"`
+	Query     domain.QueryOptions  `json:"query,omitzero"`
+	// ForwardPath is checked on every path resolve, because the path index
+	// can lag behind a link being switched back to an exact match.
+	ForwardPath bool `json:"forward_path,omitempty"`
+	// Schedule is enforced on cache hits too, so an entry cached during a
+	// campaign stops redirecting when it ends.
+	Schedule domain.LinkSchedule `json:"schedule,omitzero"`
+	// MaxUses marks limited links; the uses left are counted under
+	// "uses:<id>" instead, see consumeUse.
+	MaxUses      *int                `json:"max_uses,omitempty"`
+	Interstitial domain.Interstitial `json:"interstitial,omitzero"`
+	Flagged      bool                `json:"flagged,omitempty"`
+	// ChangePending is dropped with the entry when a scheduled change is
+	// added, canceled or run.
+	ChangePending bool           `json:"change_pending,omitempty"`
+	Blocklist     blocklistCheck `json:"blocklist,omitzero"`
+}
+
+// linkKey identifies a link across domains. Links on the default domain keep
+// the bare slug so existing "url<slug>" cache entries stay valid.
+func linkKey(linkDomain string, shortID string) string {
+	if linkDomain == "" {
+		return shortID
+	}
+	return linkDomain + "/" + shortID
+}
+
+func (s *DefaultLinkService) ResolveURL(ctx context.Context, linkDomain string, shortID string, visitor domain.Visitor) (domain.Link, error) {
+	return s.resolve(ctx, linkDomain, shortID, "", visitor)
 }
 
-func (s *DefaultLinkService) ResolveURL(ctx context.Context, shortID string) (domain.Link, error) {
+func (s *DefaultLinkService) ResolvePath(ctx context.Context, linkDomain string, path string, visitor domain.Visitor) (domain.Link, error) {
+	slug, rest, err := s.matchPath(ctx, linkDomain, path)
+	if err != nil {
+		return domain.Link{}, err
+	}
+	if !domain.IsForwardablePath(rest) {
+		return domain.Link{}, domain.ErrLinkNotFound
+	}
+	return s.resolve(ctx, linkDomain, slug, rest, visitor)
+}
+
+// resolve sends a visitor of a link, forwarding rest when the link is a
+// prefix link and live.
+func (s *DefaultLinkService) resolve(ctx context.Context, linkDomain string, shortID string, rest string, visitor domain.Visitor) (domain.Link, error) {
+	link, err := s.loadLink(ctx, linkDomain, shortID)
+	if err != nil {
+		return domain.Link{}, err
+	}
+
+	switch link.Status {
+	case domain.StatusPaused:
+		return domain.Link{}, errors.New("link is paused")
+	case domain.StatusBlocked:
+		return domain.Link{}, domain.ErrLinkBlocked
+	}
+	if rest != "" && !link.ForwardPath {
+		return domain.Link{}, domain.ErrLinkNotFound
+	}
+
+	switch link.Schedule.Phase(time.Now()) {
+	case domain.ScheduleNotLive:
+		if link.Schedule.NotLiveURL == "" {
+			return domain.Link{}, domain.ErrLinkNotLive
+		}
+		link.TargetURL = link.Schedule.NotLiveURL
+		return link, nil
+	case domain.ScheduleEnded:
+		if link.Schedule.EndedURL == "" {
+			return domain.Link{}, domain.ErrLinkEnded
+		}
+		link.TargetURL = link.Schedule.EndedURL
+		return link, nil
+	}
+
+	if link.IsLimited() {
+		if !visitor.Confirmed {
+			return domain.Link{}, domain.ErrConfirmationNeeded
+		}
+		if err := s.consumeUse(ctx, link); err != nil {
+			return domain.Link{}, err
+		}
+	}
+
+	// Route to the target the visitor's routing rules or split test select
+	// and record the click, including the rule or variant.
+	route := link.Route(visitor)
+	link.TargetURL = route.TargetURL
+	if rest != "" {
+		target, ok := domain.JoinTargetPath(link.TargetURL, rest)
+		if !ok {
+			return domain.Link{}, domain.ErrLinkNotFound
+		}
+		link.TargetURL = target
+	}
+	go s.trackClick(link, visitor, route)
+	return link, nil
+}
+
+// usesCacheTTL bounds how long the Redis copy of a limited link's uses left
+// lives before it is read again from Postgres.
+const usesCacheTTL = 86400
+
+// consumeUse spends one use of a limited link. The Redis counter, decremented
+// by a script so instances can't race past zero, turns away used-up links
+// without a database round trip. Every other use is decided by the
+// conditional decrement in Postgres, so a lost or stale counter can never
+// grant a use the database doesn't have.
+func (s *DefaultLinkService) consumeUse(ctx context.Context, link domain.Link) error {
+	key := "uses:" + link.ID
+	left, cached, err := s.Cache.DecrementIfPositive(ctx, key)
+	if err != nil {
+		log.Printf("failed to decrement cached uses of link %s: %v", link.ID, err)
+		cached = false
+	}
+	if cached && left < 0 {
+		return domain.ErrLinkUsedUp
+	}
+
+	remaining, err := s.Repo.ConsumeUse(ctx, link.ID)
+	switch {
+	case errors.Is(err, domain.ErrLinkUsedUp):
+		if err := s.Cache.Set(ctx, key, "0", usesCacheTTL); err != nil {
+			log.Printf("failed to cache uses of link %s: %v", link.ID, err)
+		}
+		return err
+	case err != nil:
+		// The counter may have been decremented for a use that didn't
+		// happen; drop it so it is read again from Postgres.
+		if err := s.Cache.Delete(ctx, key); err != nil {
+			log.Printf("failed to drop cached uses of link %s: %v", link.ID, err)
+		}
+		return err
+	}
+
+	if !cached {
+		if _, err := s.Cache.SetNX(ctx, key, strconv.Itoa(remaining), usesCacheTTL); err != nil {
+			log.Printf("failed to cache uses of link %s: %v", link.ID, err)
+		}
+	}
+	return nil
+}
+
+// loadLink reads a link from the cache, falling back to the database and
+// caching what it finds.
+func (s *DefaultLinkService) loadLink(ctx context.Context, linkDomain string, shortID string) (domain.Link, error) {
 	if shortID == "" {
 		return domain.Link{}, errors.New("shortID is required")
 	}
 
-	cacheKey := "url" + shortID
+	cacheKey := "url" + linkKey(linkDomain, shortID)
 	if val, err := s.Cache.Get(ctx, cacheKey); err == nil && val != "" {
 		var cached cachedLink
-		if json.Unmarshal([]byte(val), &cached) == nil {
-			if cached.Status == domain.StatusPaused {
-				return domain.Link{}, errors.New("link is paused")
+		// Entries cached before link IDs were stored are treated as misses.
+		if json.Unmarshal([]byte(val), &cached) == nil && cached.ID != "" {
+			link := domain.Link{
+				ID:            cached.ID,
+				ShortID:       shortID,
+				Domain:        linkDomain,
+				TargetURL:     cached.TargetURL,
+				Status:        cached.Status,
+				Rules:         cached.Rules,
+				Variants:      cached.Variants,
+				QueryOptions:  cached.Query,
+				ForwardPath:   cached.ForwardPath,
+				Schedule:      cached.Schedule,
+				MaxUses:       cached.MaxUses,
+				Interstitial:  cached.Interstitial,
+				Flagged:       cached.Flagged,
+				ChangePending: cached.ChangePending,
 			}
-			go s.trackClick(shortID)
-			return domain.Link{
-				ShortID:   shortID,
-				TargetURL: cached.TargetURL,
-				Status:    cached.Status,
-			}, nil
+			// Entries checked against older lists are checked again and
+			// cached with the outcome.
+			check := cached.Blocklist
+			if s.Blocklist != nil && check.Version != s.Blocklist.Version() {
+				check = s.checkLink(link)
+				go s.cacheLink(link, check)
+			}
+			if check.Blocked {
+				return domain.Link{}, domain.ErrTargetBlocked
+			}
+			return link, nil
 		}
 	}
 
-	link, err := s.Repo.GetByShortID(ctx, shortID)
+	link, err := s.Repo.GetByShortID(ctx, linkDomain, shortID)
+	if err != nil {
+		return domain.Link{}, err
+	}
+	check := s.checkLink(link)
+	go s.cacheLink(link, check)
+	if check.Blocked {
+		return domain.Link{}, domain.ErrTargetBlocked
+	}
+	return link, nil
+}
+
+// matchPath splits a request path into the slug serving it and the rest to
+// forward. Single-segment paths are slugs as they are; longer ones are looked
+// up in the path index.
+func (s *DefaultLinkService) matchPath(ctx context.Context, linkDomain string, path string) (string, string, error) {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return "", "", errors.New("shortID is required")
+	}
+	if !strings.Contains(path, "/") {
+		return path, "", nil
+	}
+	slug, rest, ok, err := s.paths.match(ctx, linkDomain, path)
+	if err != nil {
+		return "", "", err
+	}
+	if !ok {
+		return "", "", domain.ErrLinkNotFound
+	}
+	return slug, rest, nil
+}
+
+func (s *DefaultLinkService) PreviewLink(ctx context.Context, linkDomain string, path string) (domain.Link, error) {
+	slug, rest, err := s.matchPath(ctx, linkDomain, path)
 	if err != nil {
 		return domain.Link{}, err
 	}
 
-	if link.Status == domain.StatusPaused {
+	link, err := s.Repo.GetByShortID(ctx, linkDomain, slug)
+	if err != nil {
+		return domain.Link{}, err
+	}
+	if rest != "" && !link.ForwardPath {
+		return domain.Link{}, domain.ErrLinkNotFound
+	}
+	switch link.Status {
+	case domain.StatusPaused:
 		return domain.Link{}, errors.New("link is paused")
+	case domain.StatusBlocked:
+		return domain.Link{}, domain.ErrLinkBlocked
+	}
+	if s.checkLink(link).Blocked {
+		return domain.Link{}, domain.ErrTargetBlocked
 	}
 
-	go func(l domain.Link) {
-		s.cacheLink(l)
-		s.trackClick(shortID)
-	}(link)
-
+	// Anyone can claim to be a crawler, so a preview reveals no more than a
+	// visitor would get: outside the schedule, the fallback page; for a
+	// limited link, nothing before confirmation.
+	switch link.Schedule.Phase(time.Now()) {
+	case domain.ScheduleNotLive:
+		if link.Schedule.NotLiveURL == "" {
+			return domain.Link{}, domain.ErrLinkNotLive
+		}
+		link.TargetURL = link.Schedule.NotLiveURL
+	case domain.ScheduleEnded:
+		if link.Schedule.EndedURL == "" {
+			return domain.Link{}, domain.ErrLinkEnded
+		}
+		link.TargetURL = link.Schedule.EndedURL
+	default:
+		if link.IsLimited() {
+			return domain.Link{}, domain.ErrConfirmationNeeded
+		}
+	}
 	return link, nil
 }
 
-func (s *DefaultLinkService) trackClick(shortID string) {
+func (s *DefaultLinkService) trackClick(link domain.Link, visitor domain.Visitor, route domain.Route) {
 	ctx := context.Background()
-	s.Cache.IncrementCounter(ctx, "stats:"+shortID)
+	key := linkKey(link.Domain, link.ShortID)
+	s.Cache.IncrementCounter(ctx, "stats:"+key)
+
+	if err := s.Repo.IncrementClicks(ctx, link.Domain, link.ShortID); err != nil {
+		log.Printf("failed to increment clicks for shortID %s: %v", key, err)
+	}
 
-	if err := s.Repo.IncrementClicks(ctx, shortID); err != nil {
-		log.Printf("failed to increment clicks for shortID %s: %v", shortID, err)
+	event := domain.ClickEvent{
+		LinkID:    link.ID,
+		ShortID:   link.ShortID,
+		Domain:    link.Domain,
+		ClickedAt: time.Now(),
+		Referrer:  visitor.Referrer,
+		UserAgent: visitor.UserAgent,
+		VariantID: route.VariantID,
+	}
+	if route.RuleIndex >= 0 {
+		event.RuleIndex = &route.RuleIndex
+	}
+	if err := s.Clicks.Record(ctx, event); err != nil {
+		log.Printf("failed to record click for shortID %s: %v", key, err)
+	}
+}
+
+const (
+	titleFetchTimeout     = 10 * time.Second
+	titleFetchConcurrency = 4
+)
+
+// fillTitles fetches the page title of every link created without one. It
+// runs in the background after links are saved, so failures are only logged.
+func (s *DefaultLinkService) fillTitles(links []domain.Link) {
+	if s.Titles == nil {
+		return
+	}
+
+	pending := make(chan domain.Link)
+	var wg sync.WaitGroup
+	for range titleFetchConcurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for link := range pending {
+				s.fillTitle(link)
+			}
+		}()
+	}
+	for _, link := range links {
+		if link.Title == "" {
+			pending <- link
+		}
+	}
+	close(pending)
+	wg.Wait()
+}
+
+func (s *DefaultLinkService) fillTitle(link domain.Link) {
+	ctx, cancel := context.WithTimeout(context.Background(), titleFetchTimeout)
+	defer cancel()
+
+	title, err := s.Titles.FetchTitle(ctx, link.TargetURL)
+	if err != nil {
+		log.Printf("failed to fetch title for link %s: %v", link.ID, err)
+		return
+	}
+	if title == "" {
+		return
+	}
+	if err := s.Repo.SetTitleIfEmpty(ctx, link.ID, domain.TruncateTitle(title)); err != nil {
+		log.Printf("failed to save title for link %s: %v", link.ID, err)
 	}
 }
 
-func (s *DefaultLinkService) cacheLink(link domain.Link) {
-	cacheKey := "url" + link.ShortID
+// cacheLink stores what resolving a link needs, along with the blocklist
+// check of its targets; new links are cached unchecked.
+func (s *DefaultLinkService) cacheLink(link domain.Link, check blocklistCheck) {
+	cacheKey := "url" + linkKey(link.Domain, link.ShortID)
 	payload, err := json.Marshal(cachedLink{
-		TargetURL: link.TargetURL,
-		Status:    link.Status,
+		ID:            link.ID,
+		TargetURL:     link.TargetURL,
+		Status:        link.Status,
+		Rules:         link.Rules,
+		Variants:      link.Variants,
+		Query:         link.QueryOptions,
+		ForwardPath:   link.ForwardPath,
+		Schedule:      link.Schedule,
+		MaxUses:       link.MaxUses,
+		Interstitial:  link.Interstitial,
+		Flagged:       link.Flagged,
+		ChangePending: link.ChangePending,
+		Blocklist:     check,
 	})
 	if err != nil {
 		return
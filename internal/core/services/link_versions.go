@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	// schedulerPoll bounds how long the scheduler sleeps, and so how late it
+	// notices a change scheduled through another instance for sooner than
+	// the next one it knew of.
+	schedulerPoll = 30 * time.Second
+	// schedulerRetry is how long the scheduler waits after the database
+	// failed it.
+	schedulerRetry = 5 * time.Second
+	schedulerBatch = 50
+)
+
+func (s *DefaultLinkService) ListLinkVersions(ctx context.Context, userID string, linkDomain string, shortID string) ([]domain.LinkVersion, error) {
+	link, err := getOwnedLink(ctx, s.Repo, userID, linkDomain, shortID)
+	if err != nil {
+		return nil, err
+	}
+	return s.Repo.ListVersions(ctx, link.ID)
+}
+
+func (s *DefaultLinkService) RestoreLinkVersion(ctx context.Context, userID string, linkDomain string, shortID string, number int) (domain.Link, error) {
+	link, err := getOwnedLink(ctx, s.Repo, userID, linkDomain, shortID)
+	if err != nil {
+		return domain.Link{}, err
+	}
+
+	version, err := s.Repo.GetVersion(ctx, link.ID, number)
+	if err != nil {
+		return domain.Link{}, err
+	}
+	return s.applySettings(ctx, domain.ChangeRestore, link, version.Settings)
+}
+
+// applySettings replaces every setting of a link and drops its cached entry,
+// so visitors follow the new settings from the next request on. Targets are
+// checked against the blocklist again, since they may have been listed since
+// they were last set.
+func (s *DefaultLinkService) applySettings(ctx context.Context, changeType domain.LinkChangeType, link domain.Link, settings domain.LinkSettings) (domain.Link, error) {
+	updated := link.WithSettings(settings)
+	if err := checkTargets(s.Blocklist, updated.Targets()...); err != nil {
+		return domain.Link{}, err
+	}
+	token, err := postbackToken(link.PostbackToken, updated.Variants)
+	if err != nil {
+		return domain.Link{}, err
+	}
+	updated.PostbackToken = token
+
+	if err := s.Repo.UpdateSettings(ctx, updated, newLinkChange(ctx, changeType, link, updated)); err != nil {
+		return domain.Link{}, err
+	}
+	if updated.ForwardPath != link.ForwardPath {
+		s.paths.invalidate()
+	}
+	if err := s.Cache.Delete(ctx, "url"+linkKey(link.Domain, link.ShortID), "uses:"+link.ID); err != nil {
+		log.Printf("failed to drop cached link %s: %v", link.ID, err)
+	}
+	return updated, nil
+}
+
+func (s *DefaultLinkService) ScheduleLinkChange(ctx context.Context, userID string, linkDomain string, shortID string, input domain.ScheduledChangeInput) (domain.ScheduledChange, error) {
+	link, err := getOwnedLink(ctx, s.Repo, userID, linkDomain, shortID)
+	if err != nil {
+		return domain.ScheduledChange{}, err
+	}
+
+	input, err = input.Normalize(time.Now())
+	if err != nil {
+		return domain.ScheduledChange{}, err
+	}
+	if input.Version > 0 {
+		if _, err := s.Repo.GetVersion(ctx, link.ID, input.Version); err != nil {
+			return domain.ScheduledChange{}, err
+		}
+	} else if err := checkTargets(s.Blocklist, input.TargetURL); err != nil {
+		return domain.ScheduledChange{}, err
+	}
+
+	change, err := s.Repo.ScheduleChange(ctx, domain.ScheduledChange{
+		ID:        uuid.New().String(),
+		LinkID:    link.ID,
+		Domain:    link.Domain,
+		ShortID:   link.ShortID,
+		RunAt:     input.RunAt,
+		TargetURL: input.TargetURL,
+		Version:   input.Version,
+		Status:    domain.ScheduledPending,
+		CreatedBy: userID,
+	})
+	if err != nil {
+		return domain.ScheduledChange{}, err
+	}
+	s.dropPendingFlag(ctx, change)
+	s.wakeScheduler()
+	return change, nil
+}
+
+func (s *DefaultLinkService) ListScheduledChanges(ctx context.Context, userID string, linkDomain string, shortID string) ([]domain.ScheduledChange, error) {
+	link, err := getOwnedLink(ctx, s.Repo, userID, linkDomain, shortID)
+	if err != nil {
+		return nil, err
+	}
+	return s.Repo.ListScheduledChanges(ctx, link.ID)
+}
+
+func (s *DefaultLinkService) CancelScheduledChange(ctx context.Context, userID string, linkDomain string, shortID string, id string) error {
+	link, err := getOwnedLink(ctx, s.Repo, userID, linkDomain, shortID)
+	if err != nil {
+		return err
+	}
+	change, err := s.Repo.CancelScheduledChange(ctx, link.ID, id)
+	if err != nil {
+		return err
+	}
+	s.dropPendingFlag(ctx, change)
+	return nil
+}
+
+// dropPendingFlag drops the cached entry of a scheduled change's link, which
+// carries whether a change is pending: redirects answer with 302 while one
+// is, so browsers do not keep following the old target.
+func (s *DefaultLinkService) dropPendingFlag(ctx context.Context, change domain.ScheduledChange) {
+	if err := s.Cache.Delete(ctx, "url"+linkKey(change.Domain, change.ShortID)); err != nil {
+		log.Printf("failed to drop cached link %s: %v", change.LinkID, err)
+	}
+}
+
+// wakeScheduler makes this instance's scheduler look again for the next
+// change, which may now be sooner than the one it is waiting for.
+func (s *DefaultLinkService) wakeScheduler() {
+	select {
+	case s.scheduled <- struct{}{}:
+	default:
+	}
+}
+
+func (s *DefaultLinkService) RunScheduler(ctx context.Context) {
+	for {
+		wait := schedulerPoll
+		if err := s.runDueChanges(ctx); err != nil {
+			log.Printf("failed to run scheduled link changes: %v", err)
+			wait = schedulerRetry
+		} else if next, err := s.Repo.NextScheduledRun(ctx); err != nil {
+			log.Printf("failed to look up the next scheduled link change: %v", err)
+			wait = schedulerRetry
+		} else if !next.IsZero() {
+			wait = max(min(wait, time.Until(next)), 0)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-s.scheduled:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// runDueChanges applies every change due now, a batch at a time.
+func (s *DefaultLinkService) runDueChanges(ctx context.Context) error {
+	for {
+		claimed := time.Now()
+		due, err := s.Repo.ClaimDueChanges(ctx, claimed, schedulerBatch)
+		if err != nil {
+			return err
+		}
+		// Stop applying the batch well before its claims go stale, so an
+		// instance that takes them over never applies a change alongside
+		// this one. Changes left over run once another instance claims them.
+		batchCtx, cancel := context.WithDeadline(ctx, claimed.Add(domain.ScheduledClaimTimeout/2))
+		done := s.runClaimedChanges(ctx, batchCtx, due)
+		cancel()
+		if !done || len(due) < schedulerBatch {
+			return nil
+		}
+	}
+}
+
+// runClaimedChanges applies claimed changes until batchCtx ends, and reports
+// whether it got through all of them. Results are recorded with ctx, which
+// outlives the batch.
+func (s *DefaultLinkService) runClaimedChanges(ctx context.Context, batchCtx context.Context, due []domain.ScheduledChange) bool {
+	for _, change := range due {
+		if batchCtx.Err() != nil {
+			return false
+		}
+		status, message := domain.ScheduledDone, ""
+		if err := s.runScheduledChange(batchCtx, change); err != nil {
+			if batchCtx.Err() != nil {
+				return false
+			}
+			log.Printf("scheduled change %s of link %s failed: %v", change.ID, change.LinkID, err)
+			status, message = domain.ScheduledFailed, scheduledChangeError(err)
+		}
+		if err := s.Repo.FinishScheduledChange(ctx, change, status, message); err != nil {
+			log.Printf("failed to finish scheduled change %s: %v", change.ID, err)
+		}
+		s.dropPendingFlag(ctx, change)
+	}
+	return true
+}
+
+// runScheduledChange applies a scheduled change as the system actor. A target
+// switch keeps every other setting as it is when the change runs.
+func (s *DefaultLinkService) runScheduledChange(ctx context.Context, change domain.ScheduledChange) error {
+	ctx = domain.ContextWithActor(ctx, domain.Actor{Type: domain.ActorSystem, ID: change.ID})
+	link, err := s.Repo.GetByShortID(ctx, change.Domain, change.ShortID)
+	if err != nil {
+		return err
+	}
+
+	settings, changeType := link.Settings(), domain.ChangeUpdate
+	if change.Version > 0 {
+		version, err := s.Repo.GetVersion(ctx, link.ID, change.Version)
+		if err != nil {
+			return err
+		}
+		settings, changeType = version.Settings, domain.ChangeRestore
+	} else {
+		settings.TargetURL = change.TargetURL
+	}
+	_, err = s.applySettings(ctx, changeType, link, settings)
+	return err
+}
+
+// scheduledChangeError is the reason a failed change shows its owner;
+// database errors are only logged.
+func scheduledChangeError(err error) string {
+	for _, known := range []error{domain.ErrTargetBlocked, domain.ErrVersionNotFound, domain.ErrLinkNotFound} {
+		if errors.Is(err, known) {
+			return err.Error()
+		}
+	}
+	return "internal error"
+}
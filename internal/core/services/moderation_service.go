@@ -0,0 +1,279 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"strings"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+const (
+	// reportCooldownTTL is how long an IP address cannot report the same
+	// link again.
+	reportCooldownTTL = 24 * 60 * 60
+	// banCacheTTL bounds how long a ban lookup is cached. Banning and
+	// unbanning through the service update the cache straight away.
+	banCacheTTL = 60
+)
+
+type DefaultModerationService struct {
+	Repo  ports.ModerationRepository
+	Links ports.LinkRepository
+	Cache ports.CacheRepository
+}
+
+func NewModerationService(repo ports.ModerationRepository, links ports.LinkRepository, cache ports.CacheRepository) ports.ModerationService {
+	return &DefaultModerationService{Repo: repo, Links: links, Cache: cache}
+}
+
+// newAction starts an audit log entry for something a moderator does.
+func newAction(moderatorID string, action domain.ModerationActionType, note string) domain.ModerationAction {
+	return domain.ModerationAction{
+		ID:          uuid.New().String(),
+		ModeratorID: moderatorID,
+		Action:      action,
+		Note:        note,
+	}
+}
+
+// dropCachedLinks removes the cached entries of links whose status changed,
+// so the change applies to the next visitor.
+func (s *DefaultModerationService) dropCachedLinks(ctx context.Context, links ...domain.Link) {
+	if len(links) == 0 {
+		return
+	}
+	keys := make([]string, len(links))
+	for i, link := range links {
+		keys[i] = "url" + linkKey(link.Domain, link.ShortID)
+	}
+	if err := s.Cache.Delete(ctx, keys...); err != nil {
+		log.Printf("failed to drop %d cached links: %v", len(keys), err)
+	}
+}
+
+func (s *DefaultModerationService) ReportLink(ctx context.Context, linkDomain string, shortID string, input domain.ReportInput) (domain.AbuseReport, error) {
+	input, err := input.Normalize()
+	if err != nil {
+		return domain.AbuseReport{}, err
+	}
+
+	link, err := s.Links.GetByShortID(ctx, linkDomain, shortID)
+	if err != nil {
+		return domain.AbuseReport{}, err
+	}
+
+	if input.ReporterIP != "" {
+		sum := sha256.Sum256([]byte(input.ReporterIP))
+		first, err := s.Cache.SetNX(ctx, "report:"+link.ID+":"+hex.EncodeToString(sum[:8]), "1", reportCooldownTTL)
+		if err == nil && !first {
+			return domain.AbuseReport{}, domain.ErrAlreadyReported
+		}
+	}
+
+	return s.Repo.CreateReport(ctx, domain.AbuseReport{
+		ID:        uuid.New().String(),
+		LinkID:    link.ID,
+		Domain:    link.Domain,
+		ShortID:   link.ShortID,
+		TargetURL: link.TargetURL,
+		Reason:    input.Reason,
+		Details:   input.Details,
+		Email:     input.Email,
+		Status:    domain.ReportOpen,
+	})
+}
+
+func (s *DefaultModerationService) ListReports(ctx context.Context, filter domain.ReportFilter) (domain.ReportPage, error) {
+	if filter.Status == "" {
+		filter.Status = domain.ReportOpen
+	}
+	filter.Limit, filter.Offset = clampPage(filter.Limit, filter.Offset)
+	return s.Repo.ListReports(ctx, filter)
+}
+
+func (s *DefaultModerationService) ReviewReport(ctx context.Context, moderatorID string, reportID string, review domain.ReportReview) (domain.AbuseReport, error) {
+	review, err := review.Normalize()
+	if err != nil {
+		return domain.AbuseReport{}, err
+	}
+
+	report, err := s.Repo.GetReport(ctx, reportID)
+	if err != nil {
+		return domain.AbuseReport{}, err
+	}
+	if report.Status != domain.ReportOpen {
+		return domain.AbuseReport{}, domain.ErrReportClosed
+	}
+
+	if review.Action == domain.ReviewDismiss {
+		action := newAction(moderatorID, domain.ActionDismissReport, review.Note)
+		action.LinkID, action.ReportID = &report.LinkID, &report.ID
+		return s.Repo.DismissReport(ctx, report.ID, action)
+	}
+
+	link, err := s.Links.GetByShortID(ctx, report.Domain, report.ShortID)
+	if err != nil {
+		return domain.AbuseReport{}, err
+	}
+	blocked := link
+	blocked.Status = domain.StatusBlocked
+
+	action := newAction(moderatorID, domain.ActionBlockLink, review.Note)
+	action.LinkID, action.ReportID = &report.LinkID, &report.ID
+	if err := s.Repo.SetLinkStatus(ctx, link.ID, domain.StatusBlocked, action, newLinkChange(ctx, domain.ChangeBlock, link, blocked)); err != nil {
+		return domain.AbuseReport{}, err
+	}
+	s.dropCachedLinks(ctx, link)
+	return s.Repo.GetReport(ctx, report.ID)
+}
+
+func (s *DefaultModerationService) SetLinkStatus(ctx context.Context, moderatorID string, linkDomain string, shortID string, status domain.LinkStatus, note string) (domain.Link, error) {
+	actionType, err := domain.LinkStatusAction(status)
+	if err != nil {
+		return domain.Link{}, err
+	}
+	note, err = domain.NormalizeModerationNote(note)
+	if err != nil {
+		return domain.Link{}, err
+	}
+
+	link, err := s.Links.GetByShortID(ctx, linkDomain, shortID)
+	if err != nil {
+		return domain.Link{}, err
+	}
+
+	updated := link
+	updated.Status = status
+
+	action := newAction(moderatorID, actionType, note)
+	action.LinkID = &link.ID
+	if err := s.Repo.SetLinkStatus(ctx, link.ID, status, action, newLinkChange(ctx, domain.StatusChange(status), link, updated)); err != nil {
+		return domain.Link{}, err
+	}
+	s.dropCachedLinks(ctx, link)
+	return updated, nil
+}
+
+func (s *DefaultModerationService) DeleteLink(ctx context.Context, moderatorID string, linkDomain string, shortID string, note string) error {
+	note, err := domain.NormalizeModerationNote(note)
+	if err != nil {
+		return err
+	}
+
+	link, err := s.Links.GetByShortID(ctx, linkDomain, shortID)
+	if err != nil {
+		return err
+	}
+
+	action := newAction(moderatorID, domain.ActionDeleteLink, note)
+	action.LinkID = &link.ID
+	if err := s.Repo.DeleteLink(ctx, link.ID, action, newLinkChange(ctx, domain.ChangeDelete, link, domain.Link{})); err != nil {
+		return err
+	}
+	s.dropCachedLinks(ctx, link)
+	if link.IsLimited() {
+		if err := s.Cache.Delete(ctx, "uses:"+link.ID); err != nil {
+			log.Printf("failed to drop use counter of link %s: %v", link.ID, err)
+		}
+	}
+	return nil
+}
+
+func (s *DefaultModerationService) SetLinkFlagged(ctx context.Context, moderatorID string, linkDomain string, shortID string, flagged bool, note string) (domain.Link, error) {
+	note, err := domain.NormalizeModerationNote(note)
+	if err != nil {
+		return domain.Link{}, err
+	}
+
+	link, err := s.Links.GetByShortID(ctx, linkDomain, shortID)
+	if err != nil {
+		return domain.Link{}, err
+	}
+
+	actionType := domain.ActionFlagLink
+	if !flagged {
+		actionType = domain.ActionUnflagLink
+	}
+	updated := link
+	updated.Flagged = flagged
+
+	action := newAction(moderatorID, actionType, note)
+	action.LinkID = &link.ID
+	if err := s.Repo.SetLinkFlagged(ctx, link.ID, flagged, action, newLinkChange(ctx, domain.ChangeUpdate, link, updated)); err != nil {
+		return domain.Link{}, err
+	}
+	s.dropCachedLinks(ctx, link)
+	return updated, nil
+}
+
+func (s *DefaultModerationService) BanUser(ctx context.Context, moderatorID string, userID string, reason string, blockLinks bool) (domain.UserBan, error) {
+	userID = strings.TrimSpace(userID)
+	reason, err := domain.NormalizeModerationNote(reason)
+	if err != nil {
+		return domain.UserBan{}, err
+	}
+
+	action := newAction(moderatorID, domain.ActionBanUser, reason)
+	action.UserID = &userID
+	blockChange := func(before domain.Link) domain.LinkChange {
+		after := before
+		after.Status = domain.StatusBlocked
+		return newLinkChange(ctx, domain.ChangeBlock, before, after)
+	}
+	ban, blocked, err := s.Repo.BanUser(ctx, domain.UserBan{UserID: userID, Reason: reason, BannedBy: moderatorID}, blockLinks, action, blockChange)
+	if err != nil {
+		return domain.UserBan{}, err
+	}
+
+	if err := s.Cache.Set(ctx, "banned:"+userID, "1", banCacheTTL); err != nil {
+		log.Printf("failed to cache ban of user %s: %v", userID, err)
+	}
+	s.dropCachedLinks(ctx, blocked...)
+	return ban, nil
+}
+
+func (s *DefaultModerationService) UnbanUser(ctx context.Context, moderatorID string, userID string, note string) error {
+	note, err := domain.NormalizeModerationNote(note)
+	if err != nil {
+		return err
+	}
+
+	action := newAction(moderatorID, domain.ActionUnbanUser, note)
+	action.UserID = &userID
+	if err := s.Repo.UnbanUser(ctx, userID, action); err != nil {
+		return err
+	}
+	if err := s.Cache.Set(ctx, "banned:"+userID, "0", banCacheTTL); err != nil {
+		log.Printf("failed to cache unban of user %s: %v", userID, err)
+	}
+	return nil
+}
+
+func (s *DefaultModerationService) IsBanned(ctx context.Context, userID string) bool {
+	key := "banned:" + userID
+	if cached, err := s.Cache.Get(ctx, key); err == nil && cached != "" {
+		return cached == "1"
+	}
+
+	banned, err := s.Repo.IsBanned(ctx, userID)
+	if err != nil {
+		log.Printf("failed to look up ban of user %s: %v", userID, err)
+		return false
+	}
+	value := "0"
+	if banned {
+		value = "1"
+	}
+	_ = s.Cache.Set(ctx, key, value, banCacheTTL)
+	return banned
+}
+
+func (s *DefaultModerationService) ListActions(ctx context.Context, filter domain.ModerationActionFilter) (domain.ModerationActionPage, error) {
+	filter.Limit, filter.Offset = clampPage(filter.Limit, filter.Offset)
+	return s.Repo.ListActions(ctx, filter)
+}
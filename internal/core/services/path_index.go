@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+)
+
+// pathIndexTTL bounds how long a prefix link or nested slug created on
+// another instance can go unnoticed; changes made through this instance
+// invalidate the index immediately.
+const pathIndexTTL = 30 * time.Second
+
+// pathIndex is an in-memory trie, per link domain, of the slugs that can
+// match a multi-segment request path: nested slugs such as "guide/v2" and
+// prefix links. It lets ResolvePath find the longest matching slug by
+// walking the path once instead of querying every candidate prefix.
+//
+// The trie is built without holding any lock and swapped in whole, so
+// requests only wait on a reload when the index was invalidated or has
+// never been loaded.
+type pathIndex struct {
+	repo ports.LinkRepository
+
+	current atomic.Pointer[pathTrie]
+	// generation is bumped by invalidate; a trie loaded under an older
+	// generation is reloaded before its next use.
+	generation atomic.Uint64
+	// reload lets one caller scan the links at a time; the others wait for
+	// its trie instead of starting their own scan.
+	reload sync.Mutex
+}
+
+type pathTrie struct {
+	roots      map[string]*pathNode
+	loadedAt   time.Time
+	generation uint64
+}
+
+type pathNode struct {
+	children map[string]*pathNode
+	// isLink is set when the segments leading here form a slug; forward when
+	// that link forwards the rest of the path.
+	isLink  bool
+	forward bool
+}
+
+func newPathIndex(repo ports.LinkRepository) *pathIndex {
+	return &pathIndex{repo: repo}
+}
+
+// match returns the slug that serves path and the remaining segments to
+// forward. An exact slug match wins over a prefix link; among prefix links
+// the longest wins.
+func (ix *pathIndex) match(ctx context.Context, linkDomain string, path string) (slug string, rest string, ok bool, err error) {
+	trie, err := ix.refresh(ctx)
+	if err != nil {
+		return "", "", false, err
+	}
+
+	node := trie.roots[linkDomain]
+	segments := strings.Split(path, "/")
+	longest := -1
+	for i, seg := range segments {
+		if node == nil {
+			break
+		}
+		node = node.children[seg]
+		if node == nil || !node.isLink {
+			continue
+		}
+		if i == len(segments)-1 {
+			return path, "", true, nil
+		}
+		if node.forward {
+			longest = i
+		}
+	}
+	if longest < 0 {
+		return "", "", false, nil
+	}
+	return strings.Join(segments[:longest+1], "/"), strings.Join(segments[longest+1:], "/"), true, nil
+}
+
+// invalidate makes the next match reload the index.
+func (ix *pathIndex) invalidate() {
+	ix.generation.Add(1)
+}
+
+// InvalidatePaths makes the next path resolve reload the index.
+func (s *DefaultLinkService) InvalidatePaths() {
+	s.paths.invalidate()
+}
+
+func (ix *pathIndex) fresh(trie *pathTrie) bool {
+	return trie != nil && trie.generation == ix.generation.Load() && time.Since(trie.loadedAt) < pathIndexTTL
+}
+
+func (ix *pathIndex) refresh(ctx context.Context) (*pathTrie, error) {
+	trie := ix.current.Load()
+	if ix.fresh(trie) {
+		return trie, nil
+	}
+
+	// A trie that only aged out is still used while another caller reloads
+	// it. An invalidated one is not: it misses a change made through this
+	// instance.
+	if trie != nil && trie.generation == ix.generation.Load() {
+		if !ix.reload.TryLock() {
+			return trie, nil
+		}
+	} else {
+		ix.reload.Lock()
+	}
+	defer ix.reload.Unlock()
+	if trie := ix.current.Load(); ix.fresh(trie) {
+		return trie, nil
+	}
+
+	// The generation is read before the scan, so an invalidation during it
+	// leaves the new trie stale.
+	trie = &pathTrie{roots: make(map[string]*pathNode), loadedAt: time.Now(), generation: ix.generation.Load()}
+	err := ix.repo.StreamPathSlugs(ctx, func(linkDomain string, shortID string, forwardPath bool) error {
+		node := trie.roots[linkDomain]
+		if node == nil {
+			node = &pathNode{}
+			trie.roots[linkDomain] = node
+		}
+		for _, seg := range strings.Split(shortID, "/") {
+			child := node.children[seg]
+			if child == nil {
+				child = &pathNode{}
+				if node.children == nil {
+					node.children = make(map[string]*pathNode)
+				}
+				node.children[seg] = child
+			}
+			node = child
+		}
+		node.isLink = true
+		node.forward = forwardPath
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ix.current.Store(trie)
+	return trie, nil
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"image"
+	"log"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+)
+
+// qrCacheTTL is how long rendered QR codes are kept in Redis, in seconds.
+const qrCacheTTL = 86400
+
+type DefaultQRService struct {
+	Renderer ports.QRRenderer
+	Images   ports.ImageFetcher
+	Cache    ports.CacheRepository
+}
+
+func NewQRService(renderer ports.QRRenderer, images ports.ImageFetcher, cache ports.CacheRepository) ports.QRService {
+	return &DefaultQRService{Renderer: renderer, Images: images, Cache: cache}
+}
+
+func (s *DefaultQRService) RenderQRCode(ctx context.Context, content string, opts domain.QROptions) ([]byte, error) {
+	if opts.LogoURL != "" {
+		opts.ErrorCorrection = domain.QRErrorCorrectionH
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	cacheKey := "qr:" + opts.Fingerprint(content)
+	if val, err := s.Cache.Get(ctx, cacheKey); err == nil && val != "" {
+		return []byte(val), nil
+	}
+
+	var logo image.Image
+	if opts.LogoURL != "" {
+		var err error
+		if logo, err = s.Images.FetchImage(ctx, opts.LogoURL); err != nil {
+			log.Printf("failed to fetch QR logo %s: %v", opts.LogoURL, err)
+			return nil, domain.ErrInvalidLogo
+		}
+	}
+
+	img, err := s.Renderer.Render(content, opts, logo)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Cache.Set(ctx, cacheKey, string(img), qrCacheTTL); err != nil {
+		log.Printf("failed to cache QR code %s: %v", cacheKey, err)
+	}
+	return img, nil
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+	"github.com/google/uuid"
+)
+
+type DefaultTagService struct {
+	Repo ports.TagRepository
+}
+
+func NewTagService(repo ports.TagRepository) ports.TagService {
+	return &DefaultTagService{Repo: repo}
+}
+
+func (s *DefaultTagService) CreateTag(ctx context.Context, userID string, name string) (domain.Tag, error) {
+	if userID == "" {
+		return domain.Tag{}, errors.New("userID is required")
+	}
+
+	name, err := domain.NormalizeTagName(name)
+	if err != nil {
+		return domain.Tag{}, err
+	}
+
+	return s.Repo.Create(ctx, domain.Tag{
+		ID:     uuid.New().String(),
+		UserID: userID,
+		Name:   name,
+	})
+}
+
+func (s *DefaultTagService) RenameTag(ctx context.Context, userID string, id string, name string) (domain.Tag, error) {
+	name, err := domain.NormalizeTagName(name)
+	if err != nil {
+		return domain.Tag{}, err
+	}
+	return s.Repo.Rename(ctx, userID, id, name)
+}
+
+func (s *DefaultTagService) ListTags(ctx context.Context, userID string) ([]domain.TagStats, error) {
+	return s.Repo.ListWithStats(ctx, userID)
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"github.com/esdrassantos06/go-shortener/internal/core/domain"
+	"github.com/esdrassantos06/go-shortener/internal/core/ports"
+)
+
+type DefaultVariantService struct {
+	Links       ports.LinkRepository
+	Clicks      ports.ClickRepository
+	Conversions ports.ConversionRepository
+	Cache       ports.CacheRepository
+	Blocklist   ports.Blocklist
+}
+
+func NewVariantService(links ports.LinkRepository, clicks ports.ClickRepository, conversions ports.ConversionRepository, cache ports.CacheRepository, blocklist ports.Blocklist) ports.VariantService {
+	return &DefaultVariantService{Links: links, Clicks: clicks, Conversions: conversions, Cache: cache, Blocklist: blocklist}
+}
+
+func (s *DefaultVariantService) SetLinkVariants(ctx context.Context, userID string, linkDomain string, shortID string, variants []domain.Variant) (domain.Link, error) {
+	link, err := getOwnedLink(ctx, s.Links, userID, linkDomain, shortID)
+	if err != nil {
+		return domain.Link{}, err
+	}
+
+	variants, err = domain.NormalizeVariants(variants)
+	if err != nil {
+		return domain.Link{}, err
+	}
+	updated := link
+	updated.Variants = variants
+	if err := checkTargets(s.Blocklist, updated.Targets()...); err != nil {
+		return domain.Link{}, err
+	}
+
+	token, err := postbackToken(link.PostbackToken, variants)
+	if err != nil {
+		return domain.Link{}, err
+	}
+
+	updated.PostbackToken = token
+	if err := s.Links.SetVariants(ctx, link.ID, variants, token, newLinkChange(ctx, domain.ChangeUpdate, link, updated)); err != nil {
+		return domain.Link{}, err
+	}
+	if err := s.Cache.Delete(ctx, "url"+linkKey(link.Domain, link.ShortID)); err != nil {
+		log.Printf("failed to drop cached link %s: %v", link.ID, err)
+	}
+	return updated, nil
+}
+
+// postbackToken returns the token a link keeps with the given variants: its
+// current one, a new one for its first variants, or none without variants.
+func postbackToken(current string, variants []domain.Variant) (string, error) {
+	switch {
+	case len(variants) == 0:
+		return "", nil
+	case current != "":
+		return current, nil
+	}
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+func (s *DefaultVariantService) VariantStats(ctx context.Context, userID string, linkDomain string, shortID string) ([]domain.VariantStats, error) {
+	link, err := getOwnedLink(ctx, s.Links, userID, linkDomain, shortID)
+	if err != nil {
+		return nil, err
+	}
+
+	clicks, err := s.Clicks.CountByVariant(ctx, link.ID)
+	if err != nil {
+		return nil, err
+	}
+	conversions, err := s.Conversions.CountByVariant(ctx, link.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]domain.VariantStats, len(link.Variants))
+	for i, v := range link.Variants {
+		stats[i] = domain.VariantStats{
+			ID:          v.ID,
+			TargetURL:   v.TargetURL,
+			Weight:      v.Weight,
+			Clicks:      clicks[v.ID],
+			Conversions: conversions[v.ID],
+		}
+		if stats[i].Clicks > 0 {
+			stats[i].ConversionRate = float64(stats[i].Conversions) / float64(stats[i].Clicks)
+		}
+	}
+	return stats, nil
+}
+
+func (s *DefaultVariantService) RecordConversion(ctx context.Context, postbackToken string, variantID string) error {
+	if postbackToken == "" {
+		return domain.ErrLinkNotFound
+	}
+	link, err := s.Links.GetByPostbackToken(ctx, postbackToken)
+	if err != nil {
+		return err
+	}
+
+	known := false
+	for _, v := range link.Variants {
+		if v.ID == variantID {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return domain.ErrVariantNotFound
+	}
+
+	return s.Conversions.Record(ctx, domain.Conversion{
+		LinkID:      link.ID,
+		VariantID:   variantID,
+		ConvertedAt: time.Now(),
+	})
+}
@@ -0,0 +1,20 @@
+-- Custom branded domains owned by users. A domain must be verified through a
+-- DNS TXT record before links can be created on it.
+CREATE TABLE IF NOT EXISTS custom_domains (
+    id VARCHAR(36) PRIMARY KEY,
+    hostname VARCHAR(255) UNIQUE NOT NULL,
+    "userId" VARCHAR(255) NOT NULL,
+    verification_token VARCHAR(64) NOT NULL,
+    verified_at TIMESTAMP,
+    "createdAt" TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS custom_domains_user_idx ON custom_domains ("userId");
+
+-- Links are now scoped to a domain. The empty string is the default
+-- SHORT_URL_DOMAIN, so existing links keep working unchanged.
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS domain VARCHAR(255) NOT NULL DEFAULT '';
+
+ALTER TABLE urls DROP CONSTRAINT IF EXISTS "urls_shortId_key";
+DROP INDEX IF EXISTS "urls_shortId_key";
+CREATE UNIQUE INDEX IF NOT EXISTS urls_domain_short_id_key ON urls (domain, "shortId");
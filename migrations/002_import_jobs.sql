@@ -0,0 +1,18 @@
+-- Background jobs that import links from Bitly, Rebrandly or generic CSV
+-- exports. Conflicts and errors hold per-row issues as JSON arrays.
+CREATE TABLE IF NOT EXISTS import_jobs (
+    id VARCHAR(36) PRIMARY KEY,
+    "userId" VARCHAR(255) NOT NULL,
+    format VARCHAR(20) NOT NULL,
+    domain VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL,
+    total INTEGER NOT NULL DEFAULT 0,
+    processed INTEGER NOT NULL DEFAULT 0,
+    imported INTEGER NOT NULL DEFAULT 0,
+    conflicts JSONB NOT NULL DEFAULT '[]',
+    errors JSONB NOT NULL DEFAULT '[]',
+    "createdAt" TIMESTAMP DEFAULT NOW(),
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS import_jobs_user_idx ON import_jobs ("userId");
@@ -0,0 +1,30 @@
+-- Raw click events, one row per resolve. Visitor IPs are not stored.
+CREATE TABLE IF NOT EXISTS click_events (
+    id BIGSERIAL PRIMARY KEY,
+    link_id VARCHAR(36) NOT NULL,
+    "shortId" VARCHAR(255) NOT NULL,
+    domain VARCHAR(255) NOT NULL DEFAULT '',
+    clicked_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    referrer TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS click_events_link_time_idx ON click_events (link_id, clicked_at);
+CREATE INDEX IF NOT EXISTS urls_user_idx ON urls ("userId");
+
+-- Asynchronous exports written to the export storage directory.
+CREATE TABLE IF NOT EXISTS export_jobs (
+    id VARCHAR(36) PRIMARY KEY,
+    "userId" VARCHAR(255) NOT NULL,
+    dataset VARCHAR(20) NOT NULL,
+    format VARCHAR(20) NOT NULL,
+    from_time TIMESTAMP,
+    to_time TIMESTAMP,
+    status VARCHAR(20) NOT NULL,
+    rows INTEGER NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    "createdAt" TIMESTAMP DEFAULT NOW(),
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS export_jobs_user_idx ON export_jobs ("userId");
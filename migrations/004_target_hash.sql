@@ -0,0 +1,6 @@
+-- Hash of the normalized target URL, used to reuse a user's existing link
+-- for the same destination. Links created before this migration have no hash
+-- and are never reused.
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS target_hash VARCHAR(64);
+
+CREATE INDEX IF NOT EXISTS urls_user_target_hash_idx ON urls ("userId", target_hash);
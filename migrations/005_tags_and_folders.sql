@@ -0,0 +1,30 @@
+-- Per-user tags (many-to-many with links) and folders (one per link).
+CREATE TABLE IF NOT EXISTS tags (
+    id VARCHAR(36) PRIMARY KEY,
+    "userId" VARCHAR(255) NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    "createdAt" TIMESTAMP DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS tags_user_name_key ON tags ("userId", lower(name));
+
+CREATE TABLE IF NOT EXISTS link_tags (
+    link_id VARCHAR(36) NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    tag_id VARCHAR(36) NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (link_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS link_tags_tag_idx ON link_tags (tag_id);
+
+CREATE TABLE IF NOT EXISTS folders (
+    id VARCHAR(36) PRIMARY KEY,
+    "userId" VARCHAR(255) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    "createdAt" TIMESTAMP DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS folders_user_name_key ON folders ("userId", lower(name));
+
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS folder_id VARCHAR(36) REFERENCES folders(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS urls_folder_idx ON urls (folder_id);
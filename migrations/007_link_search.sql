@@ -0,0 +1,65 @@
+-- Full-text search over a link's slug, target, title, description, notes and
+-- tags, plus trigram matching for partial slugs.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS search_vector tsvector;
+
+-- URLs are split on punctuation so "example.com/spring-sale" matches
+-- "example", "spring" and "sale". The 'simple' configuration is used because
+-- slugs and URLs are not natural-language text.
+CREATE OR REPLACE FUNCTION urls_search_vector_update() RETURNS trigger AS $$
+BEGIN
+    NEW.search_vector :=
+        setweight(to_tsvector('simple', regexp_replace(NEW."shortId", '[^[:alnum:]]+', ' ', 'g')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(NEW.title, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce((
+            SELECT string_agg(t.name, ' ')
+            FROM link_tags lt JOIN tags t ON t.id = lt.tag_id
+            WHERE lt.link_id = NEW.id), '')), 'B') ||
+        setweight(to_tsvector('simple', regexp_replace(NEW.target_url, '[^[:alnum:]]+', ' ', 'g')), 'B') ||
+        setweight(to_tsvector('simple', coalesce(NEW.description, '')), 'C') ||
+        setweight(to_tsvector('simple', coalesce(NEW.notes, '')), 'C');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Only the searched columns fire the trigger, so click increments stay cheap.
+DROP TRIGGER IF EXISTS urls_search_vector_trigger ON urls;
+CREATE TRIGGER urls_search_vector_trigger
+    BEFORE INSERT OR UPDATE OF "shortId", target_url, title, description, notes ON urls
+    FOR EACH ROW EXECUTE FUNCTION urls_search_vector_update();
+
+-- Tag changes refresh the vector by touching the link's title.
+CREATE OR REPLACE FUNCTION link_tags_search_vector_update() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE urls SET title = title WHERE id = OLD.link_id;
+    ELSE
+        UPDATE urls SET title = title WHERE id = NEW.link_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS link_tags_search_vector_trigger ON link_tags;
+CREATE TRIGGER link_tags_search_vector_trigger
+    AFTER INSERT OR DELETE ON link_tags
+    FOR EACH ROW EXECUTE FUNCTION link_tags_search_vector_update();
+
+CREATE OR REPLACE FUNCTION tags_search_vector_update() RETURNS trigger AS $$
+BEGIN
+    UPDATE urls SET title = title
+    WHERE id IN (SELECT link_id FROM link_tags WHERE tag_id = NEW.id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS tags_search_vector_trigger ON tags;
+CREATE TRIGGER tags_search_vector_trigger
+    AFTER UPDATE OF name ON tags
+    FOR EACH ROW EXECUTE FUNCTION tags_search_vector_update();
+
+UPDATE urls SET title = title WHERE search_vector IS NULL;
+
+CREATE INDEX IF NOT EXISTS urls_search_vector_idx ON urls USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS urls_short_id_trgm_idx ON urls USING GIN ("shortId" gin_trgm_ops);
@@ -0,0 +1,9 @@
+-- Lowercased target hostname for reverse lookups ("which links point here?").
+-- It is normalised like domain.TargetHost: a trailing "." and the brackets
+-- around IPv6 literals are dropped, so "evil.com." matches "evil.com".
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS target_host TEXT
+    GENERATED ALWAYS AS (regexp_replace(lower(substring(target_url FROM '^[a-zA-Z][a-zA-Z0-9+.-]*://(?:[^@/?#]*@)?(\[[^]/?#]*\]|[^/:?#]+)')), '^\[|\]$|\.$', '', 'g')) STORED;
+
+CREATE INDEX IF NOT EXISTS urls_target_host_idx ON urls (target_host);
+-- Subdomain matches compare the reversed host by prefix.
+CREATE INDEX IF NOT EXISTS urls_target_host_rev_idx ON urls (reverse(target_host) text_pattern_ops);
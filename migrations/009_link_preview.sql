@@ -0,0 +1,5 @@
+-- Per-link overrides for the Open Graph / Twitter Card tags shown to
+-- link-preview crawlers.
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS og_title TEXT NOT NULL DEFAULT '';
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS og_description TEXT NOT NULL DEFAULT '';
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS og_image_url TEXT NOT NULL DEFAULT '';
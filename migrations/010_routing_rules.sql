@@ -0,0 +1,6 @@
+-- Ordered device/OS routing rules, stored with the link so a single row (and
+-- a single cache entry) carries everything needed to resolve it.
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS rules JSONB NOT NULL DEFAULT '[]';
+
+-- Index of the rule that picked the target; NULL for the default target.
+ALTER TABLE click_events ADD COLUMN IF NOT EXISTS rule_index SMALLINT;
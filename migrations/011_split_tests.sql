@@ -0,0 +1,18 @@
+-- Weighted A/B variants, cached with the link like its routing rules.
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS variants JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS postback_token VARCHAR(64);
+CREATE UNIQUE INDEX IF NOT EXISTS urls_postback_token_idx ON urls (postback_token) WHERE postback_token IS NOT NULL;
+
+-- Variant the visitor was sent to; NULL when the link has no split test.
+ALTER TABLE click_events ADD COLUMN IF NOT EXISTS variant_id VARCHAR(32);
+CREATE INDEX IF NOT EXISTS click_events_link_variant_idx ON click_events (link_id, variant_id) WHERE variant_id IS NOT NULL;
+
+-- Conversions reported back through the link's postback URL.
+CREATE TABLE IF NOT EXISTS variant_conversions (
+    id BIGSERIAL PRIMARY KEY,
+    link_id VARCHAR(36) NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    variant_id VARCHAR(32) NOT NULL,
+    converted_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS variant_conversions_link_variant_idx ON variant_conversions (link_id, variant_id);
@@ -0,0 +1,3 @@
+-- UTM parameters and query-string forwarding applied to the target on
+-- redirect.
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS query_options JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,7 @@
+-- Prefix links: "<slug>/rest" redirects to "<target>/rest".
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS forward_path BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- The in-memory path index is loaded from the prefix links and the nested
+-- slugs ("docs/v2"); keep that scan off the full table.
+CREATE INDEX IF NOT EXISTS urls_path_slugs_idx ON urls (domain, "shortId")
+    WHERE forward_path OR "shortId" LIKE '%/%';
@@ -0,0 +1,6 @@
+-- Time window in which a link redirects to its targets, with optional
+-- fallback pages for before and after it.
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS active_from TIMESTAMP;
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS active_until TIMESTAMP;
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS not_live_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS ended_url TEXT NOT NULL DEFAULT '';
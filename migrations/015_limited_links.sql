@@ -0,0 +1,5 @@
+-- Limited-use links stop resolving once uses_left reaches zero. NULL means
+-- unlimited. uses_left is the source of truth; Redis only keeps a copy to
+-- turn away used-up links without a database round trip.
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS max_uses INTEGER;
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS uses_left INTEGER;
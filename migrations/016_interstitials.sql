@@ -0,0 +1,4 @@
+-- Owner-enabled interstitial page, and the admin flag that forces a warning
+-- interstitial on suspicious links.
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS interstitial JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE urls ADD COLUMN IF NOT EXISTS flagged BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,42 @@
+-- Abuse reports from visitors, waiting in the moderation queue until a
+-- moderator dismisses them or blocks the link (urls.status = 'BLOCKED').
+CREATE TABLE IF NOT EXISTS abuse_reports (
+    id VARCHAR(36) PRIMARY KEY,
+    link_id VARCHAR(36) NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    target_url TEXT NOT NULL,
+    reason VARCHAR(20) NOT NULL,
+    details TEXT NOT NULL DEFAULT '',
+    email VARCHAR(254) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'OPEN',
+    "createdAt" TIMESTAMP DEFAULT NOW(),
+    reviewed_by VARCHAR(255),
+    reviewed_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS abuse_reports_status_idx ON abuse_reports (status, "createdAt");
+CREATE INDEX IF NOT EXISTS abuse_reports_link_idx ON abuse_reports (link_id);
+
+-- Users banned by moderators. RequireAuth rejects their sessions.
+CREATE TABLE IF NOT EXISTS user_bans (
+    "userId" VARCHAR(255) PRIMARY KEY,
+    reason TEXT NOT NULL DEFAULT '',
+    banned_by VARCHAR(255) NOT NULL,
+    "createdAt" TIMESTAMP DEFAULT NOW()
+);
+
+-- Append-only log of moderator actions. Link and report IDs are kept when
+-- the link is deleted, so there are no foreign keys.
+CREATE TABLE IF NOT EXISTS moderation_actions (
+    id VARCHAR(36) PRIMARY KEY,
+    moderator_id VARCHAR(255) NOT NULL,
+    action VARCHAR(30) NOT NULL,
+    link_id VARCHAR(36),
+    "userId" VARCHAR(255),
+    report_id VARCHAR(36),
+    note TEXT NOT NULL DEFAULT '',
+    "createdAt" TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS moderation_actions_time_idx ON moderation_actions ("createdAt");
+CREATE INDEX IF NOT EXISTS moderation_actions_link_idx ON moderation_actions (link_id);
+CREATE INDEX IF NOT EXISTS moderation_actions_user_idx ON moderation_actions ("userId");
@@ -0,0 +1,31 @@
+-- Append-only audit log of link mutations. Entries outlive the links they
+-- describe, so there is no foreign key, and a trigger refuses updates and
+-- deletes.
+CREATE TABLE IF NOT EXISTS link_changes (
+    id VARCHAR(36) PRIMARY KEY,
+    link_id VARCHAR(36) NOT NULL,
+    domain VARCHAR(255) NOT NULL DEFAULT '',
+    "shortId" VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    actor_type VARCHAR(20) NOT NULL,
+    actor_id VARCHAR(255) NOT NULL DEFAULT '',
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    before JSONB,
+    after JSONB,
+    "createdAt" TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS link_changes_link_idx ON link_changes (link_id, "createdAt");
+CREATE INDEX IF NOT EXISTS link_changes_actor_idx ON link_changes (actor_id, "createdAt");
+CREATE INDEX IF NOT EXISTS link_changes_time_idx ON link_changes ("createdAt");
+
+CREATE OR REPLACE FUNCTION link_changes_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'link_changes is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS link_changes_append_only ON link_changes;
+CREATE TRIGGER link_changes_append_only
+    BEFORE UPDATE OR DELETE ON link_changes
+    FOR EACH ROW EXECUTE FUNCTION link_changes_append_only();
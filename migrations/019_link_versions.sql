@@ -0,0 +1,32 @@
+-- Previous settings of each link, numbered from 1 per link. A row is added
+-- whenever a change replaces a link's target or settings, in the same
+-- transaction as its link_changes entry.
+CREATE TABLE IF NOT EXISTS link_versions (
+    link_id VARCHAR(36) NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    number INTEGER NOT NULL,
+    settings JSONB NOT NULL,
+    change_id VARCHAR(36) NOT NULL,
+    "createdAt" TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (link_id, number)
+);
+
+-- Target switches and version restores waiting for their time. Every
+-- instance runs a scheduler that claims due rows with SKIP LOCKED; a RUNNING
+-- row whose claim is stale is taken over, so a crash does not lose it.
+CREATE TABLE IF NOT EXISTS link_scheduled_changes (
+    id VARCHAR(36) PRIMARY KEY,
+    link_id VARCHAR(36) NOT NULL REFERENCES urls(id) ON DELETE CASCADE,
+    run_at TIMESTAMP NOT NULL,
+    target_url TEXT,
+    version INTEGER,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    error TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR(255) NOT NULL,
+    "createdAt" TIMESTAMP DEFAULT NOW(),
+    claimed_at TIMESTAMP,
+    executed_at TIMESTAMP,
+    CHECK ((target_url IS NULL) <> (version IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS link_scheduled_changes_due_idx ON link_scheduled_changes (run_at) WHERE status IN ('PENDING', 'RUNNING');
+CREATE INDEX IF NOT EXISTS link_scheduled_changes_link_idx ON link_scheduled_changes (link_id, run_at);
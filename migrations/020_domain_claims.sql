@@ -0,0 +1,7 @@
+-- Registering a domain only claims it; several users can hold pending claims
+-- on the same hostname, each with its own token, and the first to verify it
+-- owns it. Verifying drops the other claims.
+ALTER TABLE custom_domains DROP CONSTRAINT IF EXISTS custom_domains_hostname_key;
+DROP INDEX IF EXISTS custom_domains_hostname_key;
+CREATE UNIQUE INDEX IF NOT EXISTS custom_domains_verified_hostname_key ON custom_domains (hostname) WHERE verified_at IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS custom_domains_hostname_user_key ON custom_domains (hostname, "userId");